@@ -10,12 +10,16 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/elchemista/driplnk/internal/adapters/affiliate"
+	jobs "github.com/elchemista/driplnk/internal/adapters/handler"
 	adapters_http "github.com/elchemista/driplnk/internal/adapters/http"
 	"github.com/elchemista/driplnk/internal/adapters/oauth"
 	"github.com/elchemista/driplnk/internal/adapters/repository"
+	"github.com/elchemista/driplnk/internal/adapters/safety"
 	"github.com/elchemista/driplnk/internal/adapters/seo"
 	"github.com/elchemista/driplnk/internal/adapters/social"
 	"github.com/elchemista/driplnk/internal/adapters/storage"
+	"github.com/elchemista/driplnk/internal/adapters/traffic"
 	"github.com/elchemista/driplnk/internal/config"
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/ports"
@@ -47,6 +51,8 @@ func main() {
 	// 3. Setup Repository (Postgres or Pebble)
 	var userRepo domain.UserRepository
 	var linkRepo domain.LinkRepository
+	var groupRepo domain.LinkGroupRepository
+	var revisionRepo domain.LinkRevisionRepository
 	var analyticsRepo domain.AnalyticsRepository
 	var dbCloser io.Closer
 
@@ -60,6 +66,8 @@ func main() {
 		}
 		userRepo = repo
 		linkRepo = repository.NewPostgresLinkRepository(repo)
+		groupRepo = repository.NewPostgresLinkGroupRepository(repo)
+		revisionRepo = repository.NewPostgresLinkRevisionRepository(repo)
 		analyticsRepo = repo
 		dbCloser = repo
 		log.Println("[INFO] Using PostgreSQL as database backend")
@@ -81,6 +89,8 @@ func main() {
 		}
 		userRepo = repo
 		linkRepo = repository.NewPebbleLinkRepository(repo)
+		groupRepo = repository.NewPebbleLinkGroupRepository(repo)
+		revisionRepo = repository.NewPebbleLinkRevisionRepository(repo)
 		analyticsRepo = repo
 		dbCloser = repo
 		log.Println("[INFO] Using PebbleDB as database backend")
@@ -97,13 +107,20 @@ func main() {
 	log.Println("[INFO] Initializing AnalyticsService")
 	analyticsService := service.NewAnalyticsService(analyticsRepo)
 
+	configDir := "config"
+
 	log.Println("[INFO] Initializing HTMLFetcher for metadata extraction")
-	metadataFetcher := seo.NewHTMLFetcher()
+	var embedConfigs []config.EmbedProviderConfig
+	if err := config.LoadJSONConfig(configDir+"/embeds.json", &embedConfigs); err != nil {
+		log.Printf("[WARN] Failed to load embeds.json: %v", err)
+	} else {
+		log.Printf("[INFO] Loaded %d embed provider configs", len(embedConfigs))
+	}
+	metadataFetcher := seo.NewHTMLFetcher(embedConfigs)
 
 	log.Println("[INFO] Initializing LinkService with metadata fetching")
 
 	// 5. Setup Social Adapter (Load JSON Config)
-	configDir := "config"
 	var socialConfigs []config.SocialPlatformConfig
 	if err := config.LoadJSONConfig(configDir+"/socials.json", &socialConfigs); err != nil {
 		log.Printf("[WARN] Failed to load socials.json: %v", err)
@@ -112,7 +129,67 @@ func main() {
 	}
 	socialAdapter := social.NewSocialAdapter(socialConfigs)
 
-	linkService := service.NewLinkService(linkRepo, metadataFetcher, socialAdapter)
+	var safetyConfig config.LinkSafetyConfig
+	if err := config.LoadJSONConfig(configDir+"/link_safety.json", &safetyConfig); err != nil {
+		log.Printf("[WARN] Failed to load link_safety.json: %v", err)
+	} else {
+		log.Printf("[INFO] Loaded link safety policy with %d blocked domains", len(safetyConfig.BlockedDomains))
+	}
+	blocklistPath := ""
+	if safetyConfig.BlocklistFile != "" {
+		blocklistPath = configDir + "/" + safetyConfig.BlocklistFile
+	}
+	safetyAdapter := safety.NewSafetyAdapter(safetyConfig, blocklistPath)
+
+	// Init Uploader (can be nil if not configured)
+	var uploader ports.FileUploader
+	if s3Cfg.Bucket != "" {
+		up, err := storage.NewS3Uploader(ctx, storage.S3UploaderConfig{
+			Bucket:     s3Cfg.Bucket,
+			Region:     s3Cfg.Region,
+			CDNURL:     s3Cfg.CDNURL,
+			FolderPath: s3Cfg.Folder,
+		})
+		if err != nil {
+			log.Printf("[WARN] Failed to init S3 uploader: %v", err)
+		} else {
+			uploader = up
+			log.Println("[INFO] S3 Uploader initialized for file uploads")
+		}
+	}
+
+	// Link thumbnails are stored next to the other media (nil keeps remote OG images)
+	var thumbnailStore domain.ThumbnailStore
+	if uploader != nil {
+		thumbnailStore = storage.NewThumbnailCache(uploader)
+	}
+
+	linkService := service.NewLinkService(linkRepo, metadataFetcher, socialAdapter, revisionRepo, safetyAdapter, thumbnailStore)
+	groupService := service.NewLinkGroupService(groupRepo, linkRepo)
+
+	var affiliateConfigs []config.AffiliateNetworkConfig
+	if err := config.LoadJSONConfig(configDir+"/affiliates.json", &affiliateConfigs); err != nil {
+		log.Printf("[WARN] Failed to load affiliates.json: %v", err)
+	} else {
+		log.Printf("[INFO] Loaded %d affiliate network configs", len(affiliateConfigs))
+	}
+	affiliateAdapter := affiliate.NewAffiliateAdapter(affiliateConfigs)
+
+	var trafficSourceConfigs []config.TrafficSourceConfig
+	if err := config.LoadJSONConfig(configDir+"/traffic_sources.json", &trafficSourceConfigs); err != nil {
+		log.Printf("[WARN] Failed to load traffic_sources.json: %v", err)
+	} else {
+		log.Printf("[INFO] Loaded %d traffic source configs", len(trafficSourceConfigs))
+	}
+	sourceAdapter := traffic.NewSourceAdapter(trafficSourceConfigs)
+
+	var botConfig config.BotDetectionConfig
+	if err := config.LoadJSONConfig(configDir+"/bots.json", &botConfig); err != nil {
+		log.Printf("[WARN] Failed to load bots.json: %v", err)
+	} else {
+		log.Printf("[INFO] Loaded %d bot User-Agent patterns", len(botConfig.UserAgentPatterns))
+	}
+	botAdapter := traffic.NewBotAdapter(botConfig)
 
 	// 6. Setup OAuth Providers
 	baseURL := "http://localhost:" + serverCfg.Port
@@ -139,29 +216,21 @@ func main() {
 	// Rate Limiter (e.g., 10 req/s, burst 20)
 	rateLimiter := adapters_http.NewRateLimiter(10, 20)
 
-	// Init Uploader (can be nil if not configured)
-	var uploader ports.FileUploader
-	if s3Cfg.Bucket != "" {
-		up, err := storage.NewS3Uploader(ctx, storage.S3UploaderConfig{
-			Bucket:     s3Cfg.Bucket,
-			Region:     s3Cfg.Region,
-			CDNURL:     s3Cfg.CDNURL,
-			FolderPath: s3Cfg.Folder,
-		})
-		if err != nil {
-			log.Printf("[WARN] Failed to init S3 uploader: %v", err)
-		} else {
-			uploader = up
-			log.Println("[INFO] S3 Uploader initialized for file uploads")
-		}
-	}
-
 	authHandler := adapters_http.NewAuthHandler(authService, githubProvider, googleProvider, sessionManager, secureCookie)
-	analyticsHandler := adapters_http.NewAnalyticsHandler(analyticsService)
-	analyticsMiddleware := adapters_http.NewAnalyticsMiddleware(analyticsService)
-	pageHandler := adapters_http.NewPageHandler(userRepo, sessionManager, linkService, analyticsService)
+	analyticsHandler := adapters_http.NewAnalyticsHandler(analyticsService, botAdapter)
+	analyticsMiddleware := adapters_http.NewAnalyticsMiddleware(analyticsService, sourceAdapter, botAdapter)
+	pageHandler := adapters_http.NewPageHandler(userRepo, sessionManager, linkService, groupService, analyticsService)
 	userHandler := adapters_http.NewUserHandler(userRepo, sessionManager, uploader)
-	linkHandler := adapters_http.NewLinkHandler(linkService, analyticsService, sessionManager, userRepo)
+	linkHandler := adapters_http.NewLinkHandler(linkService, analyticsService, sessionManager, userRepo, affiliateAdapter, botAdapter)
+	groupHandler := adapters_http.NewLinkGroupHandler(groupService, linkService, sessionManager, userRepo)
+	qrHandler := adapters_http.NewQRHandler(userRepo, linkService)
+
+	// Background jobs (stopped on shutdown)
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	linkHealthJob := jobs.NewLinkHealthJob(userRepo, linkService, metadataFetcher, jobs.LoadLinkHealthConfig())
+	go linkHealthJob.Run(jobsCtx)
+	trashPurgeJob := jobs.NewTrashPurgeJob(userRepo, linkService, jobs.LoadTrashPurgeConfig())
+	go trashPurgeJob.Run(jobsCtx)
 
 	// 8. HTTP Server
 	mux := http.NewServeMux()
@@ -186,16 +255,61 @@ func main() {
 	mux.HandleFunc("POST /dashboard/profile", userHandler.UpdateProfile)
 	mux.HandleFunc("POST /dashboard/seo", userHandler.UpdateSEO)
 	mux.HandleFunc("POST /dashboard/theme", userHandler.UpdateTheme)
+	mux.HandleFunc("POST /dashboard/affiliates", userHandler.UpdateAffiliateTags)
+	mux.HandleFunc("POST /dashboard/utm", userHandler.UpdateUTM)
 
 	// Dashboard Link Routes
 	mux.HandleFunc("POST /dashboard/links", linkHandler.CreateLink)
 	mux.HandleFunc("POST /dashboard/links/{id}", linkHandler.UpdateLink)
 	mux.HandleFunc("POST /dashboard/links/{id}/delete", linkHandler.DeleteLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/restore", linkHandler.RestoreLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/purge", linkHandler.PurgeLink)
 	mux.HandleFunc("POST /dashboard/links/{id}/refresh", linkHandler.RefreshLinkMetadata)
+	mux.HandleFunc("POST /dashboard/links/{id}/schedule", linkHandler.ScheduleLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/limits", linkHandler.LimitLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/slug", linkHandler.UpdateLinkSlug)
+	mux.HandleFunc("POST /dashboard/links/{id}/variants", linkHandler.UpdateLinkVariants)
+	mux.HandleFunc("GET /dashboard/links/{id}/variants", linkHandler.LinkVariantStats)
+	mux.HandleFunc("POST /dashboard/links/{id}/rules", linkHandler.UpdateLinkRules)
+	mux.HandleFunc("POST /dashboard/links/{id}/protection", linkHandler.ProtectLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/product", linkHandler.UpdateLinkProduct)
+	mux.HandleFunc("POST /dashboard/links/{id}/utm", linkHandler.UpdateLinkUTM)
+	mux.HandleFunc("POST /dashboard/links/{id}/deep-link", linkHandler.UpdateLinkDeepLink)
+	mux.HandleFunc("POST /dashboard/links/{id}/thumbnail", linkHandler.UpdateLinkThumbnail)
+	mux.HandleFunc("GET /dashboard/links/{id}/history", linkHandler.LinkHistory)
+	mux.HandleFunc("GET /dashboard/links/{id}/stats", pageHandler.LinkStats)
 	mux.HandleFunc("POST /dashboard/links/reorder", linkHandler.ReorderLinks)
+	mux.HandleFunc("GET /dashboard/links/export", linkHandler.ExportLinks)
+	mux.HandleFunc("POST /dashboard/links/import", linkHandler.ImportLinks)
+	mux.HandleFunc("POST /dashboard/links/arrange", groupHandler.ArrangeLinks)
+	mux.HandleFunc("GET /dashboard/trash", linkHandler.Trash)
+	mux.HandleFunc("GET /dashboard/revisions", linkHandler.RecentRevisions)
+	mux.HandleFunc("POST /dashboard/revisions/{id}/revert", linkHandler.RevertRevision)
+
+	// Dashboard Link Group Routes
+	mux.HandleFunc("POST /dashboard/groups", groupHandler.CreateGroup)
+	mux.HandleFunc("POST /dashboard/groups/{id}", groupHandler.UpdateGroup)
+	mux.HandleFunc("POST /dashboard/groups/{id}/delete", groupHandler.DeleteGroup)
+	mux.HandleFunc("POST /dashboard/groups/move", groupHandler.MoveLink)
+	mux.HandleFunc("POST /dashboard/groups/reorder", groupHandler.ReorderGroups)
 
 	// Link Redirect Handler (for tracking clicks)
 	mux.HandleFunc("/go/{id}", linkHandler.HandleRedirect)
+	mux.HandleFunc("/{handle}/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		// Profile QR codes share the per-profile path: a /{handle}/qr.png pattern
+		// would conflict with /go/{id}, and slugs never contain a dot
+		switch r.PathValue("slug") {
+		case "qr.png", "qr.svg":
+			qrHandler.ProfileQR(w, r)
+		default:
+			linkHandler.HandleRedirect(w, r)
+		}
+	})
+	mux.HandleFunc("POST /go/{id}/unlock", linkHandler.UnlockLink)
+
+	// QR Code Routes (scans are tracked with source=qr)
+	mux.HandleFunc("GET /go/{id}/qr.svg", qrHandler.LinkQR)
+	mux.HandleFunc("GET /go/{id}/qr.png", qrHandler.LinkQR)
 
 	// Sitemap Handler
 	sitemapHandler := adapters_http.NewSitemapHandler("http://localhost:"+serverCfg.Port, userRepo)
@@ -262,6 +376,7 @@ func main() {
 
 	<-stop
 	log.Println("[INFO] Shutting down server...")
+	stopJobs()
 
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,86 @@
+package affiliate
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+type AffiliateAdapter struct {
+	networks []networkRule
+}
+
+type networkRule struct {
+	hosts   *regexp.Regexp
+	param   string
+	network domain.AffiliateNetwork
+}
+
+func NewAffiliateAdapter(configs []config.AffiliateNetworkConfig) *AffiliateAdapter {
+	var rules []networkRule
+	for _, cfg := range configs {
+		if cfg.ID == "" || cfg.Param == "" {
+			continue
+		}
+		hosts, err := regexp.Compile(cfg.HostPattern)
+		if err != nil {
+			// Skip invalid regex, same as the social adapter
+			continue
+		}
+		rules = append(rules, networkRule{
+			hosts: hosts,
+			param: cfg.Param,
+			network: domain.AffiliateNetwork{
+				ID:   cfg.ID,
+				Name: cfg.Name,
+			},
+		})
+	}
+	return &AffiliateAdapter{
+		networks: rules,
+	}
+}
+
+func (a *AffiliateAdapter) Networks() []domain.AffiliateNetwork {
+	networks := make([]domain.AffiliateNetwork, len(a.networks))
+	for i, rule := range a.networks {
+		networks[i] = rule.network
+	}
+	return networks
+}
+
+func (a *AffiliateAdapter) Tag(rawURL, networkID, tag string) (string, error) {
+	rule := a.find(networkID)
+	if rule == nil {
+		return rawURL, fmt.Errorf("unknown affiliate network %q", networkID)
+	}
+	if tag == "" {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, fmt.Errorf("invalid product URL: %w", err)
+	}
+	// Never leak a tag to hosts the network does not cover (e.g. a geo rule pointing elsewhere)
+	if !rule.hosts.MatchString(u.Hostname()) {
+		return rawURL, nil
+	}
+
+	q := u.Query()
+	q.Set(rule.param, tag)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (a *AffiliateAdapter) find(networkID string) *networkRule {
+	for i := range a.networks {
+		if a.networks[i].network.ID == networkID {
+			return &a.networks[i]
+		}
+	}
+	return nil
+}
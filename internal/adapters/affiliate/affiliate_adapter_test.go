@@ -0,0 +1,52 @@
+package affiliate_test
+
+import (
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/affiliate"
+	"github.com/elchemista/driplnk/internal/config"
+)
+
+func TestAffiliateAdapter_Tag(t *testing.T) {
+	adapter := affiliate.NewAffiliateAdapter([]config.AffiliateNetworkConfig{
+		{ID: "amazon", Name: "Amazon Associates", HostPattern: `(?i)(^|\.)amazon\.(com|de)$`, Param: "tag"},
+		{ID: "broken", Name: "Broken", HostPattern: `(`, Param: "x"},
+	})
+
+	if got := len(adapter.Networks()); got != 1 {
+		t.Fatalf("expected invalid configs to be skipped, got %d networks", got)
+	}
+
+	t.Run("adds tag on matching host", func(t *testing.T) {
+		got, err := adapter.Tag("https://www.amazon.de/dp/B000?th=1", "amazon", "drip-21")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got != "https://www.amazon.de/dp/B000?tag=drip-21&th=1" {
+			t.Errorf("unexpected URL %s", got)
+		}
+	})
+
+	t.Run("replaces existing tag", func(t *testing.T) {
+		got, _ := adapter.Tag("https://amazon.com/dp/B000?tag=someone-else", "amazon", "drip-20")
+		if got != "https://amazon.com/dp/B000?tag=drip-20" {
+			t.Errorf("unexpected URL %s", got)
+		}
+	})
+
+	t.Run("leaves other hosts untouched", func(t *testing.T) {
+		got, err := adapter.Tag("https://amazon.com.evil.example/dp/B000", "amazon", "drip-20")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got != "https://amazon.com.evil.example/dp/B000" {
+			t.Errorf("expected URL unchanged, got %s", got)
+		}
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		if _, err := adapter.Tag("https://amazon.com/dp/B000", "nope", "x"); err == nil {
+			t.Error("expected error for unknown network")
+		}
+	})
+}
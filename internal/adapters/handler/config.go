@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type LinkHealthConfig struct {
+	Interval            time.Duration // 0 disables the checker
+	AutoDeactivateAfter int           // consecutive broken checks before a link is deactivated; 0 never deactivates
+}
+
+func LoadLinkHealthConfig() *LinkHealthConfig {
+	cfg := &LinkHealthConfig{Interval: 6 * time.Hour}
+	if v := os.Getenv("LINK_CHECK_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.Interval = d
+		}
+	}
+	if v := os.Getenv("LINK_CHECK_AUTO_DEACTIVATE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.AutoDeactivateAfter = n
+		}
+	}
+	return cfg
+}
+
+type TrashPurgeConfig struct {
+	Interval  time.Duration // 0 disables the purge
+	Retention time.Duration // how long deleted links stay in the trash
+}
+
+func LoadTrashPurgeConfig() *TrashPurgeConfig {
+	cfg := &TrashPurgeConfig{Interval: time.Hour, Retention: 30 * 24 * time.Hour}
+	if v := os.Getenv("TRASH_PURGE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.Interval = d
+		}
+	}
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.Retention = d
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+// LinkHealthJob periodically checks every active link destination and records the result.
+type LinkHealthJob struct {
+	users   domain.UserRepository
+	links   *service.LinkService
+	checker domain.LinkChecker
+	cfg     *LinkHealthConfig
+}
+
+func NewLinkHealthJob(users domain.UserRepository, links *service.LinkService, checker domain.LinkChecker, cfg *LinkHealthConfig) *LinkHealthJob {
+	return &LinkHealthJob{
+		users:   users,
+		links:   links,
+		checker: checker,
+		cfg:     cfg,
+	}
+}
+
+// Run checks all links every configured interval until ctx is cancelled.
+func (j *LinkHealthJob) Run(ctx context.Context) {
+	if j.cfg.Interval <= 0 {
+		log.Println("[INFO] Link health checker disabled")
+		return
+	}
+	log.Printf("[INFO] Link health checker running every %s (auto-deactivate after %d failures)", j.cfg.Interval, j.cfg.AutoDeactivateAfter)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.CheckAll(ctx); err != nil {
+				log.Printf("[ERR] Link health check failed: %v", err)
+			}
+		}
+	}
+}
+
+// CheckAll walks every user's active links once. A failing link does not stop the walk.
+func (j *LinkHealthJob) CheckAll(ctx context.Context) error {
+	users, err := j.users.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	checked, broken := 0, 0
+	for _, user := range users {
+		links, err := j.links.ListAllLinks(ctx, user.ID)
+		if err != nil {
+			log.Printf("[WARN] Failed to list links of user %s: %v", user.ID, err)
+			continue
+		}
+
+		for _, link := range links {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !link.IsActive || link.URL == "" {
+				continue
+			}
+
+			health := j.checker.Check(ctx, link.URL)
+			if _, err := j.links.RecordLinkHealth(ctx, link.ID, health, j.cfg.AutoDeactivateAfter); err != nil {
+				log.Printf("[WARN] Failed to record health of link %s: %v", link.ID, err)
+				continue
+			}
+			checked++
+			if health.IsBroken() {
+				broken++
+			}
+		}
+	}
+
+	log.Printf("[INFO] Link health check finished: %d checked, %d broken", checked, broken)
+	return nil
+}
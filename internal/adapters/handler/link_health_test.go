@@ -0,0 +1,57 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/adapters/handler"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+type fakeChecker struct {
+	status map[string]int
+	calls  []string
+}
+
+func (f *fakeChecker) Check(ctx context.Context, url string) *domain.LinkHealth {
+	f.calls = append(f.calls, url)
+	return &domain.LinkHealth{StatusCode: f.status[url], FinalURL: url, CheckedAt: time.Now()}
+}
+
+func TestLinkHealthJob_CheckAll(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewMockUserRepository()
+	links := mocks.NewMockLinkRepository()
+
+	users.AddUser(&domain.User{ID: "u1", Email: "u1@example.com", Handle: "one"})
+	links.AddLink(&domain.Link{ID: "ok", UserID: "u1", URL: "https://ok.example", IsActive: true})
+	links.AddLink(&domain.Link{ID: "dead", UserID: "u1", URL: "https://dead.example", IsActive: true})
+	links.AddLink(&domain.Link{ID: "off", UserID: "u1", URL: "https://off.example", IsActive: false})
+
+	checker := &fakeChecker{status: map[string]int{
+		"https://ok.example":   200,
+		"https://dead.example": 404,
+	}}
+	job := handler.NewLinkHealthJob(users, service.NewLinkService(links, nil, nil, nil, nil, nil), checker, &handler.LinkHealthConfig{AutoDeactivateAfter: 1})
+
+	if err := job.CheckAll(ctx); err != nil {
+		t.Fatalf("CheckAll failed: %v", err)
+	}
+
+	if len(checker.calls) != 2 {
+		t.Errorf("expected inactive links to be skipped, got calls %v", checker.calls)
+	}
+
+	ok, _ := links.GetByID(ctx, "ok")
+	if ok.Health == nil || ok.Health.StatusCode != 200 || !ok.IsActive {
+		t.Errorf("expected healthy link, got %+v", ok.Health)
+	}
+
+	dead, _ := links.GetByID(ctx, "dead")
+	if dead.Health == nil || !dead.Health.IsBroken() || dead.IsActive {
+		t.Errorf("expected broken link to be deactivated, got %+v (active=%v)", dead.Health, dead.IsActive)
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+// TrashPurgeJob permanently deletes links that have been in the trash longer than the retention period.
+type TrashPurgeJob struct {
+	users domain.UserRepository
+	links *service.LinkService
+	cfg   *TrashPurgeConfig
+}
+
+func NewTrashPurgeJob(users domain.UserRepository, links *service.LinkService, cfg *TrashPurgeConfig) *TrashPurgeJob {
+	return &TrashPurgeJob{
+		users: users,
+		links: links,
+		cfg:   cfg,
+	}
+}
+
+// Run purges expired trash every configured interval until ctx is cancelled.
+func (j *TrashPurgeJob) Run(ctx context.Context) {
+	if j.cfg.Interval <= 0 {
+		log.Println("[INFO] Trash purge disabled")
+		return
+	}
+	log.Printf("[INFO] Trash purge running every %s (retention %s)", j.cfg.Interval, j.cfg.Retention)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.PurgeAll(ctx); err != nil {
+				log.Printf("[ERR] Trash purge failed: %v", err)
+			}
+		}
+	}
+}
+
+// PurgeAll walks every user's trash once. A failing user does not stop the walk.
+func (j *TrashPurgeJob) PurgeAll(ctx context.Context) error {
+	users, err := j.users.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	cutoff := time.Now().Add(-j.cfg.Retention)
+	total := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		purged, err := j.links.PurgeTrash(ctx, user.ID, cutoff)
+		total += purged
+		if err != nil {
+			log.Printf("[WARN] Failed to purge trash of user %s: %v", user.ID, err)
+		}
+	}
+
+	if total > 0 {
+		log.Printf("[INFO] Trash purge finished: %d links deleted permanently", total)
+	}
+	return nil
+}
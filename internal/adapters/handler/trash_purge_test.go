@@ -0,0 +1,39 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/adapters/handler"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+func TestTrashPurgeJob_PurgeAll(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewMockUserRepository()
+	links := mocks.NewMockLinkRepository()
+
+	expired := time.Now().Add(-40 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	users.AddUser(&domain.User{ID: "u1", Email: "u1@example.com", Handle: "one"})
+	links.AddLink(&domain.Link{ID: "expired", UserID: "u1", URL: "https://expired.example", DeletedAt: &expired})
+	links.AddLink(&domain.Link{ID: "recent", UserID: "u1", URL: "https://recent.example", DeletedAt: &recent})
+	links.AddLink(&domain.Link{ID: "live", UserID: "u1", URL: "https://live.example"})
+
+	job := handler.NewTrashPurgeJob(users, service.NewLinkService(links, nil, nil, nil, nil, nil), &handler.TrashPurgeConfig{Retention: 30 * 24 * time.Hour})
+	if err := job.PurgeAll(ctx); err != nil {
+		t.Fatalf("PurgeAll failed: %v", err)
+	}
+
+	if _, err := links.GetByID(ctx, "expired"); err == nil {
+		t.Error("expected the expired link to be purged")
+	}
+	for _, id := range []domain.LinkID{"recent", "live"} {
+		if _, err := links.GetByID(ctx, id); err != nil {
+			t.Errorf("expected %s to be kept, got %v", id, err)
+		}
+	}
+}
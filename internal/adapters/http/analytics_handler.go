@@ -3,7 +3,6 @@ package http
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/service"
@@ -11,10 +10,11 @@ import (
 
 type AnalyticsHandler struct {
 	service *service.AnalyticsService
+	bots    domain.BotClassifier // optional: flags events of automated clients
 }
 
-func NewAnalyticsHandler(s *service.AnalyticsService) *AnalyticsHandler {
-	return &AnalyticsHandler{service: s}
+func NewAnalyticsHandler(s *service.AnalyticsService, bots domain.BotClassifier) *AnalyticsHandler {
+	return &AnalyticsHandler{service: s, bots: bots}
 }
 
 // RecordScrollRequest is a lightweight request for scroll events.
@@ -42,24 +42,13 @@ func (h *AnalyticsHandler) RecordScroll(w http.ResponseWriter, r *http.Request)
 	// Add scroll depth to meta
 	req.Meta["scroll_depth"] = string(rune(req.Depth)) // simple encoding
 
-	// Extract User-Agent
-	ua := r.Header.Get("User-Agent")
-	if ua != "" {
-		req.Meta["user_agent"] = ua
-		if strings.Contains(strings.ToLower(ua), "mobile") || strings.Contains(strings.ToLower(ua), "android") {
-			req.Meta["device_type"] = "mobile"
-		} else {
-			req.Meta["device_type"] = "desktop"
-		}
-	}
-
-	// Extract Country
-	country := r.Header.Get("CF-IPCountry")
-	if country == "" {
-		country = r.Header.Get("X-AppEngine-Country")
-	}
-	if country != "" {
-		req.Meta["country"] = country
+	// Extract User-Agent, device & country
+	addRequestMeta(req.Meta, r)
+	if isBotRequest(r, h.bots) {
+		req.Meta[domain.MetaIsBot] = "true"
+	} else {
+		// Clients cannot flag themselves, nor clear the flag
+		delete(req.Meta, domain.MetaIsBot)
 	}
 
 	err := h.service.TrackEvent(r.Context(), domain.EventTypeScroll, req.UserID, nil, req.VisitorID, req.Meta)
@@ -25,16 +25,29 @@ func (m *mockAnalyticsRepo) GetSummary(ctx context.Context, userID string, linkI
 	return nil, nil
 }
 
+func (m *mockAnalyticsRepo) GetVariantStats(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error) {
+	return nil, nil
+}
+
+func (m *mockAnalyticsRepo) GetTimeSeries(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error) {
+	return domain.NewTimeSeries(query), nil
+}
+
+func (m *mockAnalyticsRepo) GetTopLinks(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error) {
+	return nil, nil
+}
+
 func TestRecordScroll(t *testing.T) {
 	repo := &mockAnalyticsRepo{}
 	svc := service.NewAnalyticsService(repo)
-	handler := NewAnalyticsHandler(svc)
+	handler := NewAnalyticsHandler(svc, nil)
 
 	payload := map[string]interface{}{
 		"visitor_id": "visitor-123",
 		"depth":      75,
 		"meta": map[string]string{
-			"page": "/user/profile",
+			"page":   "/user/profile",
+			"is_bot": "true", // only the server classifies clients
 		},
 	}
 	body, _ := json.Marshal(payload)
@@ -42,6 +55,7 @@ func TestRecordScroll(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/api/analytics/scroll", bytes.NewBuffer(body))
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Mobile)")
 	req.Header.Set("CF-IPCountry", "US")
+	req.Header.Set("Referer", "https://www.Instagram.com/p/123")
 
 	rr := httptest.NewRecorder()
 	handler.RecordScroll(rr, req)
@@ -68,4 +82,10 @@ func TestRecordScroll(t *testing.T) {
 	if event.Meta["device_type"] != "mobile" {
 		t.Errorf("expected device type mobile, got %v", event.Meta["device_type"])
 	}
+	if event.IsBot {
+		t.Error("expected a client-sent bot flag to be ignored")
+	}
+	if event.Meta["referrer"] != "instagram.com" {
+		t.Errorf("expected referrer instagram.com, got %v", event.Meta["referrer"])
+	}
 }
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// defaultAnalyticsRange is the range picker preset shown when none is chosen.
+const defaultAnalyticsRange = "7d"
+
+// analyticsRangePresets maps range picker presets to the number of buckets shown.
+var analyticsRangePresets = map[string]struct {
+	count  int
+	bucket domain.TimeBucket
+}{
+	"24h": {24, domain.BucketHour},
+	"7d":  {7, domain.BucketDay},
+	"30d": {30, domain.BucketDay},
+	"90d": {13, domain.BucketWeek},
+}
+
+// analyticsRange reads the dashboard range picker from the query string and
+// returns the selected preset ("custom" for explicit dates) and its time range.
+// Custom ranges take inclusive "from"/"to" dates (YYYY-MM-DD, UTC) and an optional
+// "bucket"; without one the bucket is picked from the length of the range.
+// Invalid input falls back to the default preset.
+func analyticsRange(r *http.Request, now time.Time) (string, time.Time, time.Time, domain.TimeBucket) {
+	q := r.URL.Query()
+	key := q.Get("range")
+
+	if key == "custom" {
+		from, errFrom := time.Parse("2006-01-02", q.Get("from"))
+		to, errTo := time.Parse("2006-01-02", q.Get("to"))
+		if errFrom == nil && errTo == nil && !to.Before(from) {
+			to = to.AddDate(0, 0, 1)
+			bucket := domain.TimeBucket(q.Get("bucket"))
+			if !bucket.IsValid() {
+				switch span := to.Sub(from); {
+				case span <= 2*24*time.Hour:
+					bucket = domain.BucketHour
+				case span <= 92*24*time.Hour:
+					bucket = domain.BucketDay
+				default:
+					bucket = domain.BucketWeek
+				}
+			}
+			return key, from, to, bucket
+		}
+		key = ""
+	}
+
+	preset, ok := analyticsRangePresets[key]
+	if !ok {
+		key = defaultAnalyticsRange
+		preset = analyticsRangePresets[key]
+	}
+
+	// The range ends with the bucket containing now
+	to := preset.bucket.Next(preset.bucket.Truncate(now))
+	from := to.Add(-time.Duration(preset.count) * preset.bucket.Duration())
+	return key, from, to, preset.bucket
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+func TestAnalyticsRange(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		query  string
+		key    string
+		from   time.Time
+		to     time.Time
+		bucket domain.TimeBucket
+	}{
+		{"", "7d", day(-2), day(5), domain.BucketDay},
+		{"range=24h", "24h", now.Truncate(time.Hour).Add(-23 * time.Hour), now.Truncate(time.Hour).Add(time.Hour), domain.BucketHour},
+		{"range=custom&from=2026-03-01&to=2026-03-01", "custom", day(1), day(2), domain.BucketHour},
+		{"range=custom&from=2026-02-01&to=2026-03-01", "custom", day(1).AddDate(0, -1, 0), day(2), domain.BucketDay},
+		{"range=custom&from=2026-01-01&to=2026-03-01&bucket=week", "custom", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), day(2), domain.BucketWeek},
+		{"range=custom&from=2026-03-05&to=2026-03-01", "7d", day(-2), day(5), domain.BucketDay},
+		{"range=forever", "7d", day(-2), day(5), domain.BucketDay},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/dashboard?tab=analytics&"+tt.query, nil)
+		key, from, to, bucket := analyticsRange(r, now)
+		if key != tt.key || !from.Equal(tt.from) || !to.Equal(tt.to) || bucket != tt.bucket {
+			t.Errorf("%q: got %s %v..%v per %s, want %s %v..%v per %s", tt.query, key, from, to, bucket, tt.key, tt.from, tt.to, tt.bucket)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/ports"
@@ -53,6 +54,14 @@ func (m *MockSessionManager) ClearSession(ctx context.Context, w http.ResponseWr
 	return m.Error
 }
 
+func (m *MockSessionManager) SetSignedValue(w http.ResponseWriter, name, value string, ttl time.Duration) error {
+	return m.Error
+}
+
+func (m *MockSessionManager) GetSignedValue(r *http.Request, name string) (string, error) {
+	return "", m.Error
+}
+
 func TestAuthHandler_Logout(t *testing.T) {
 	// Setup
 	repo := &MockUserRepo{}
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -96,3 +97,22 @@ func TestCSRFMiddleware_BlocksMismatchedToken(t *testing.T) {
 		t.Errorf("Expected 403 Forbidden for mismatched token, got %d", postRec.Code)
 	}
 }
+
+func TestCSRFMiddleware_AllowsFormFieldToken(t *testing.T) {
+	handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}), false)
+
+	// Plain HTML forms (data-turbo="false") send the token as a field
+	postReq := httptest.NewRequest(http.MethodPost, "/some-endpoint", strings.NewReader("csrf_token=correct-token"))
+	postReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	postReq.AddCookie(&http.Cookie{Name: "csrf_token", Value: "correct-token"})
+	postRec := httptest.NewRecorder()
+
+	handler.ServeHTTP(postRec, postReq)
+
+	if postRec.Code != http.StatusOK {
+		t.Errorf("Expected 200 OK for POST with valid form token, got %d", postRec.Code)
+	}
+}
@@ -81,6 +81,8 @@ func (h *ErrorHandler) renderErrorPage(w http.ResponseWriter, r *http.Request, c
 		viewerrors.Error404().Render(ctx, w)
 	case 409:
 		viewerrors.Error409().Render(ctx, w)
+	case 410:
+		viewerrors.Error410().Render(ctx, w)
 	case 429:
 		viewerrors.Error429().Render(ctx, w)
 	case 500:
@@ -110,6 +112,13 @@ func RespondNotFound(w http.ResponseWriter, r *http.Request, resource string) {
 	viewerrors.Error404().Render(r.Context(), w)
 }
 
+// RespondGone renders a 410 error page.
+func RespondGone(w http.ResponseWriter, r *http.Request, resource string) {
+	LogError(r, 410, fmt.Sprintf("%s is no longer available", resource), "")
+	w.WriteHeader(http.StatusGone)
+	viewerrors.Error410().Render(r.Context(), w)
+}
+
 // RespondUnauthorized renders a 401 error page.
 func RespondUnauthorized(w http.ResponseWriter, r *http.Request, message string) {
 	if message == "" {
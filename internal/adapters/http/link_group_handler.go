@@ -0,0 +1,249 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/sanitizer"
+	"github.com/elchemista/driplnk/internal/ports"
+	"github.com/elchemista/driplnk/internal/service"
+	"github.com/elchemista/driplnk/views/dashboard"
+)
+
+type LinkGroupHandler struct {
+	groupSvc *service.LinkGroupService
+	linkSvc  *service.LinkService
+	sessions ports.SessionManager
+	userRepo domain.UserRepository
+}
+
+func NewLinkGroupHandler(
+	groupSvc *service.LinkGroupService,
+	linkSvc *service.LinkService,
+	sessions ports.SessionManager,
+	userRepo domain.UserRepository,
+) *LinkGroupHandler {
+	return &LinkGroupHandler{
+		groupSvc: groupSvc,
+		linkSvc:  linkSvc,
+		sessions: sessions,
+		userRepo: userRepo,
+	}
+}
+
+// getCurrentUser retrieves the authenticated user from session.
+func (h *LinkGroupHandler) getCurrentUser(r *http.Request) (*domain.User, error) {
+	sessionUserID, err := h.sessions.GetSession(r)
+	if err != nil || sessionUserID == "" {
+		return nil, fmt.Errorf("no session")
+	}
+	return h.userRepo.GetByID(r.Context(), domain.UserID(sessionUserID))
+}
+
+// CreateGroup handles POST /dashboard/groups
+func (h *LinkGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.formUser(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := h.groupSvc.CreateGroup(r.Context(), user.ID,
+		sanitizer.Normalize(r.FormValue("title")),
+		sanitizer.Normalize(r.FormValue("description")),
+		r.FormValue("collapsed") == "true",
+	)
+	if err != nil {
+		h.respondGroupError(w, r, "Failed to create section", err)
+		return
+	}
+
+	h.respondGroups(w, r, user.ID, "Section created!")
+}
+
+// UpdateGroup handles POST /dashboard/groups/{id}
+func (h *LinkGroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.formUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := domain.LinkGroupID(r.PathValue("id"))
+	_, err := h.groupSvc.UpdateGroup(r.Context(), groupID, user.ID,
+		sanitizer.Normalize(r.FormValue("title")),
+		sanitizer.Normalize(r.FormValue("description")),
+		r.FormValue("collapsed") == "true",
+	)
+	if err != nil {
+		h.respondGroupError(w, r, "Failed to update section", err)
+		return
+	}
+
+	h.respondGroups(w, r, user.ID, "Section updated!")
+}
+
+// DeleteGroup handles POST /dashboard/groups/{id}/delete
+func (h *LinkGroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.formUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := domain.LinkGroupID(r.PathValue("id"))
+	if err := h.groupSvc.DeleteGroup(r.Context(), groupID, user.ID); err != nil {
+		h.respondGroupError(w, r, "Failed to delete section", err)
+		return
+	}
+
+	h.respondGroups(w, r, user.ID, "Section deleted!")
+}
+
+// MoveLink handles POST /dashboard/groups/move
+// It moves the link in "link_id" to the end of the section in "group_id"
+// (empty for no section).
+func (h *LinkGroupHandler) MoveLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.formUser(w, r)
+	if !ok {
+		return
+	}
+
+	linkID := domain.LinkID(r.FormValue("link_id"))
+	groupID := domain.LinkGroupID(r.FormValue("group_id"))
+	if err := h.groupSvc.MoveLink(r.Context(), linkID, user.ID, groupID); err != nil {
+		h.respondGroupError(w, r, "Failed to move link", err)
+		return
+	}
+
+	h.respondGroups(w, r, user.ID, "Link moved!")
+}
+
+// ReorderGroups handles POST /dashboard/groups/reorder
+func (h *LinkGroupHandler) ReorderGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		OrderedIDs []string `json:"ordered_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	groupIDs := make([]domain.LinkGroupID, len(payload.OrderedIDs))
+	for i, id := range payload.OrderedIDs {
+		groupIDs[i] = domain.LinkGroupID(id)
+	}
+
+	if err := h.groupSvc.ReorderGroups(r.Context(), user.ID, groupIDs); err != nil {
+		log.Printf("[ERR] Failed to reorder groups: %v", err)
+		http.Error(w, "Failed to reorder", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true}`))
+}
+
+// ArrangeLinks handles POST /dashboard/links/arrange
+// The payload lists every link in display order with the group it belongs to:
+// {"placements": [{"link_id": "...", "group_id": "..."}]}
+func (h *LinkGroupHandler) ArrangeLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		Placements []domain.LinkPlacement `json:"placements"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.groupSvc.ArrangeLinks(r.Context(), user.ID, payload.Placements); err != nil {
+		log.Printf("[ERR] Failed to arrange links: %v", err)
+		if errors.Is(err, domain.ErrBadRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to arrange links", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true}`))
+}
+
+// formUser checks the method and session of a dashboard form post and parses the form.
+func (h *LinkGroupHandler) formUser(w http.ResponseWriter, r *http.Request) (*domain.User, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return nil, false
+	}
+	return user, true
+}
+
+func (h *LinkGroupHandler) respondGroupError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	log.Printf("[ERR] %s: %v", message, err)
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrBadRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	}
+	respondError(w, r, message, status)
+}
+
+// respondGroups re-renders the sections card for Turbo and redirects otherwise.
+func (h *LinkGroupHandler) respondGroups(w http.ResponseWriter, r *http.Request, userID domain.UserID, message string) {
+	if IsTurboRequest(r) {
+		links, err := h.linkSvc.ListAllLinks(r.Context(), userID)
+		if err != nil {
+			log.Printf("[ERR] Failed to list links: %v", err)
+		}
+		groups, err := h.groupSvc.ListGroups(r.Context(), userID)
+		if err != nil {
+			log.Printf("[ERR] Failed to list groups: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkGroupsStream(links, groups, message).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
@@ -0,0 +1,93 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	handler "github.com/elchemista/driplnk/internal/adapters/http"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkGroupHandler(t *testing.T) {
+	mockGroups := mocks.NewMockLinkGroupRepository()
+	mockLinks := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockLinks, nil, nil, nil, nil, nil)
+	groupService := service.NewLinkGroupService(mockGroups, mockLinks)
+	h := handler.NewLinkGroupHandler(groupService, linkService, mockSessionManager, mockUserRepo)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1"})
+	mockLinks.AddLink(&domain.Link{ID: "l1", UserID: "user-1", Title: "Single", URL: "https://example.com"})
+
+	postForm := func(path string, form url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	t.Run("Create over Turbo", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+		req := postForm("/dashboard/groups", url.Values{"title": {"Music"}, "collapsed": {"true"}})
+		req.Header.Set("Accept", "text/vnd.turbo-stream.html")
+		w := httptest.NewRecorder()
+
+		h.CreateGroup(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `target="link-groups"`)
+		groups, _ := groupService.ListGroups(context.Background(), "user-1")
+		assert.Len(t, groups, 1)
+		assert.True(t, groups[0].Collapsed)
+	})
+
+	t.Run("Move link into group", func(t *testing.T) {
+		groups, _ := groupService.ListGroups(context.Background(), "user-1")
+		w := httptest.NewRecorder()
+
+		h.MoveLink(w, postForm("/dashboard/groups/move", url.Values{"link_id": {"l1"}, "group_id": {string(groups[0].ID)}}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		link, _ := mockLinks.GetByID(context.Background(), "l1")
+		assert.Equal(t, groups[0].ID, link.GroupID)
+	})
+
+	t.Run("Arrange rejects unknown group", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/arrange", strings.NewReader(`{"placements":[{"link_id":"l1","group_id":"nope"}]}`))
+		w := httptest.NewRecorder()
+
+		h.ArrangeLinks(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Update other user's group", func(t *testing.T) {
+		mockGroups.AddGroup(&domain.LinkGroup{ID: "foreign", UserID: "user-2", Title: "Theirs"})
+		req := postForm("/dashboard/groups/foreign", url.Values{"title": {"Mine"}})
+		req.SetPathValue("id", "foreign")
+		w := httptest.NewRecorder()
+
+		h.UpdateGroup(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		group, _ := mockGroups.GetByID(context.Background(), "foreign")
+		assert.Equal(t, "Theirs", group.Title)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("")
+		w := httptest.NewRecorder()
+
+		h.CreateGroup(w, postForm("/dashboard/groups", url.Values{"title": {"Music"}}))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
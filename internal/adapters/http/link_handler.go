@@ -3,9 +3,11 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,19 +17,16 @@ import (
 	"github.com/elchemista/driplnk/internal/ports"
 	"github.com/elchemista/driplnk/internal/service"
 	"github.com/elchemista/driplnk/views/dashboard"
+	"github.com/elchemista/driplnk/views/profile"
 )
 
-// containsMobile checks if a User-Agent string indicates a mobile device.
-func containsMobile(ua string) bool {
-	lower := strings.ToLower(ua)
-	return strings.Contains(lower, "mobile") || strings.Contains(lower, "android")
-}
-
 type LinkHandler struct {
 	linkSvc      *service.LinkService
 	analyticsSvc *service.AnalyticsService
 	sessions     ports.SessionManager
 	userRepo     domain.UserRepository
+	affiliates   domain.AffiliateTagger
+	bots         domain.BotClassifier // optional: flags clicks of automated clients
 }
 
 func NewLinkHandler(
@@ -35,12 +34,16 @@ func NewLinkHandler(
 	analyticsSvc *service.AnalyticsService,
 	sessions ports.SessionManager,
 	userRepo domain.UserRepository,
+	affiliates domain.AffiliateTagger,
+	bots domain.BotClassifier,
 ) *LinkHandler {
 	return &LinkHandler{
 		linkSvc:      linkSvc,
 		analyticsSvc: analyticsSvc,
 		sessions:     sessions,
 		userRepo:     userRepo,
+		affiliates:   affiliates,
+		bots:         bots,
 	}
 }
 
@@ -53,28 +56,139 @@ func (h *LinkHandler) getCurrentUser(r *http.Request) (*domain.User, error) {
 	return h.userRepo.GetByID(r.Context(), domain.UserID(sessionUserID))
 }
 
-// HandleRedirect handles the /go/{id} endpoint.
-// It tracks the click and redirects to the target URL.
+// HandleRedirect handles the /go/{id} and /{handle}/{slug} endpoints.
+// The link is resolved by ID or vanity slug; it tracks the click and redirects to the target URL.
 func (h *LinkHandler) HandleRedirect(w http.ResponseWriter, r *http.Request) {
-	linkIDStr := r.PathValue("id")
-	if linkIDStr == "" {
+	ref := r.PathValue("id")
+	if ref == "" {
+		ref = r.PathValue("slug")
+	}
+	if ref == "" {
 		http.NotFound(w, r)
 		return
 	}
-	linkID := domain.LinkID(linkIDStr)
 
 	ctx := r.Context()
-	link, err := h.linkSvc.GetLink(ctx, linkID)
+	link, err := h.linkSvc.ResolveLink(ctx, ref)
 	if err != nil || link == nil {
 		http.NotFound(w, r)
 		return
 	}
 
+	// Per-profile paths only resolve links owned by that handle
+	var owner *domain.User
+	if handle := r.PathValue("handle"); handle != "" {
+		owner, err = h.userRepo.GetByHandle(ctx, handle)
+		if err != nil || owner == nil || owner.ID != link.UserID {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
 	if !link.IsActive {
 		http.NotFound(w, r)
 		return
 	}
 
+	now := time.Now()
+	if link.IsPending(now) {
+		http.NotFound(w, r)
+		return
+	}
+	if link.IsExpired(now) {
+		h.respondUnavailable(w, r, link)
+		return
+	}
+
+	if link.IsProtected() && !h.isUnlocked(r, link) {
+		renderLinkGate(w, r, link, "", http.StatusOK)
+		return
+	}
+
+	// The owner's affiliate tags and UTM defaults apply to the destination
+	if owner == nil {
+		if owner, err = h.userRepo.GetByID(ctx, link.UserID); err != nil {
+			owner = nil
+		}
+	}
+
+	visitorID := "unknown"
+	if link.MaxVisitors > 0 {
+		// Unique visitor limits need to recognise returning visitors
+		visitorID = visitorIDFor(w, r)
+	} else if c, err := r.Cookie(visitorCookie); err == nil {
+		visitorID = c.Value
+	}
+
+	// Geo/device rules win over A/B rotation, which wins over the default URL
+	target := link.URL
+	rule := link.MatchRule(requestCountry(r), requestDeviceType(r))
+	var variant *domain.LinkVariant
+	if rule != nil {
+		target = rule.URL
+	} else {
+		// A/B rotation: sticky per visitor cookie, random for anonymous visitors
+		stickyID := visitorID
+		if stickyID == "unknown" {
+			stickyID = ""
+		}
+		variant = link.VariantFor(stickyID)
+		if variant != nil {
+			target = variant.URL
+		}
+	}
+
+	// Product links carry the owner's affiliate tag; crawlers must not follow paid links
+	affiliateNetwork := ""
+	if link.IsAffiliate() {
+		w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+		if tagged := h.affiliateURL(owner, link, target); tagged != target {
+			target = tagged
+			affiliateNetwork = link.Product.AffiliateNetwork
+		}
+	}
+
+	// Campaign attribution for the destination's analytics; the stored URL is left as is
+	var utm domain.UTMParams
+	if owner != nil {
+		utm = owner.UTM
+	}
+	target = utm.Override(link.UTM).Apply(target)
+
+	// Re-checked on every click so blocklist updates also cover existing links
+	if err := h.linkSvc.CheckURL(target); err != nil {
+		log.Printf("[WARN] Blocked redirect for link %s: %v", link.ID, err)
+		RespondForbidden(w, r, unsafeLinkMessage)
+		return
+	}
+
+	// Counted last so that only clicks which are actually redirected use up a limited link.
+	// Bots (e.g. chat apps previewing a shared link) are redirected without being counted.
+	bot := isBotRequest(r, h.bots)
+	allowed := !link.IsExhausted()
+	if !bot {
+		countedID := visitorID
+		if countedID == "unknown" {
+			countedID = ""
+		}
+		allowed, err = h.linkSvc.CountClick(ctx, link.ID, countedID)
+		if err != nil {
+			log.Printf("[ERR] Failed to count click for link %s: %v", link.ID, err)
+			allowed = !link.IsCapped() // never let a limited link overrun its limit
+		}
+	}
+	if !allowed {
+		h.respondUnavailable(w, r, link)
+		return
+	}
+
+	// Deep links try the destination's native app first on phones
+	appURL := ""
+	mobileOS := requestMobileOS(r)
+	if link.DeepLink && mobileOS != "" {
+		appURL = h.linkSvc.AppLink(target, mobileOS)
+	}
+
 	// Async tracking
 	go func() {
 		trackCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -82,28 +196,23 @@ func (h *LinkHandler) HandleRedirect(w http.ResponseWriter, r *http.Request) {
 
 		meta := make(map[string]string)
 		meta["path"] = r.URL.Path
-
-		ua := r.Header.Get("User-Agent")
-		if ua != "" {
-			meta["user_agent"] = ua
-			if containsMobile(ua) {
-				meta["device_type"] = "mobile"
-			} else {
-				meta["device_type"] = "desktop"
-			}
+		if variant != nil {
+			meta["variant"] = variant.Name
 		}
-
-		country := r.Header.Get("CF-IPCountry")
-		if country == "" {
-			country = r.Header.Get("X-AppEngine-Country")
+		if rule != nil {
+			meta["rule"] = string(rule.Field)
+		}
+		if affiliateNetwork != "" {
+			meta["affiliate"] = affiliateNetwork
 		}
-		if country != "" {
-			meta["country"] = country
+		if appURL != "" {
+			meta["deep_link"] = string(mobileOS)
 		}
 
-		visitorID := "unknown"
-		if c, err := r.Cookie("drip_visitor"); err == nil {
-			visitorID = c.Value
+		addRequestMeta(meta, r)
+		addLandingMeta(meta, r)
+		if bot {
+			meta[domain.MetaIsBot] = "true"
 		}
 
 		userID := string(link.UserID)
@@ -114,7 +223,179 @@ func (h *LinkHandler) HandleRedirect(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	http.Redirect(w, r, link.URL, http.StatusTemporaryRedirect)
+	if appURL != "" {
+		renderAppRedirect(w, r, link, appURL, target)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusTemporaryRedirect)
+}
+
+// respondUnavailable sends visitors of an expired or used-up link to its
+// fallback URL, or shows a "no longer available" page when it has none.
+func (h *LinkHandler) respondUnavailable(w http.ResponseWriter, r *http.Request, link *domain.Link) {
+	if link.FallbackURL == "" {
+		RespondGone(w, r, "Link")
+		return
+	}
+	if err := h.linkSvc.CheckURL(link.FallbackURL); err != nil {
+		log.Printf("[WARN] Blocked fallback redirect for link %s: %v", link.ID, err)
+		RespondForbidden(w, r, unsafeLinkMessage)
+		return
+	}
+	http.Redirect(w, r, link.FallbackURL, http.StatusTemporaryRedirect)
+}
+
+// unsafeLinkMessage is shown when a link's destination fails the safety policy.
+const unsafeLinkMessage = "This link has been disabled because its destination is considered unsafe."
+
+// affiliateURL adds the link owner's tag for the product's affiliate network to target.
+// Any failure falls back to the untagged URL so visitors are never blocked.
+func (h *LinkHandler) affiliateURL(owner *domain.User, link *domain.Link, target string) string {
+	if h.affiliates == nil || owner == nil {
+		return target
+	}
+	tag := owner.AffiliateTags[link.Product.AffiliateNetwork]
+	if tag == "" {
+		return target
+	}
+	tagged, err := h.affiliates.Tag(target, link.Product.AffiliateNetwork, tag)
+	if err != nil {
+		log.Printf("[WARN] Failed to add affiliate tag to link %s: %v", link.ID, err)
+		return target
+	}
+	return tagged
+}
+
+// linkUnlockTTL is how long a visitor stays unlocked after passing a link gate.
+const linkUnlockTTL = 24 * time.Hour
+
+// unlockCookieName returns the signed cookie remembering that a visitor passed a link's gate.
+func unlockCookieName(linkID domain.LinkID) string {
+	return "drip_unlock_" + string(linkID)
+}
+
+// isUnlocked reports whether the visitor has passed the current gate of link.
+func (h *LinkHandler) isUnlocked(r *http.Request, link *domain.Link) bool {
+	token, err := h.sessions.GetSignedValue(r, unlockCookieName(link.ID))
+	return err == nil && token == link.UnlockToken()
+}
+
+// renderLinkGate renders the password / sensitive content interstitial.
+func renderLinkGate(w http.ResponseWriter, r *http.Request, link *domain.Link, errMsg string, status int) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	profile.LinkGate(link, errMsg).Render(r.Context(), w)
+}
+
+// renderAppRedirect renders the page that opens appURL in the native app and
+// falls back to webURL.
+func renderAppRedirect(w http.ResponseWriter, r *http.Request, link *domain.Link, appURL, webURL string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	profile.AppRedirect(link, appURL, webURL).Render(r.Context(), w)
+}
+
+// UnlockLink handles POST /go/{id}/unlock
+// It checks the password or sensitive content confirmation, remembers the unlock
+// in a signed cookie and sends the visitor back through the redirect.
+func (h *LinkHandler) UnlockLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	link, err := h.linkSvc.ResolveLink(r.Context(), r.PathValue("id"))
+	if err != nil || link == nil || !link.IsActive {
+		http.NotFound(w, r)
+		return
+	}
+
+	redirectPath := fmt.Sprintf("/go/%s", link.ID)
+	if !link.IsProtected() {
+		http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	switch link.Protection {
+	case domain.ProtectionPassword:
+		if !h.linkSvc.CheckLinkPassword(link, r.FormValue("password")) {
+			renderLinkGate(w, r, link, "Wrong password, please try again.", http.StatusUnauthorized)
+			return
+		}
+	case domain.ProtectionSensitive:
+		if r.FormValue("confirm") != "yes" {
+			renderLinkGate(w, r, link, "", http.StatusOK)
+			return
+		}
+	}
+
+	if err := h.sessions.SetSignedValue(w, unlockCookieName(link.ID), link.UnlockToken(), linkUnlockTTL); err != nil {
+		log.Printf("[ERR] Failed to set unlock cookie for link %s: %v", link.ID, err)
+		http.Error(w, "Failed to unlock link", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+}
+
+// ProtectLink handles POST /dashboard/links/{id}/protection
+func (h *LinkHandler) ProtectLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	protection := domain.LinkProtection(r.FormValue("protection"))
+	input := struct {
+		Protection string `validate:"omitempty,oneof=password sensitive"`
+	}{
+		Protection: string(protection),
+	}
+	if err := validator.ValidateStruct(input); err != nil {
+		respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Passwords are not normalized: leading/trailing spaces are part of the secret
+	link, err := h.linkSvc.ProtectLink(r.Context(), linkID, user.ID, protection, r.FormValue("password"))
+	if err != nil {
+		log.Printf("[ERR] Failed to update link protection: %v", err)
+		respondError(w, r, "Failed to update protection: "+err.Error(), linkErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
 }
 
 // CreateLink handles POST /dashboard/links
@@ -143,7 +424,7 @@ func (h *LinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 	input := struct {
 		Title string `validate:"required,max=100"`
 		URL   string `validate:"required,url"`
-		Type  string `validate:"omitempty,oneof=standard social product"`
+		Type  string `validate:"omitempty,oneof=standard social product embed"`
 	}{
 		Title: title,
 		URL:   url,
@@ -158,6 +439,14 @@ func (h *LinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 	link, err := h.linkSvc.CreateLink(r.Context(), user.ID, title, url, linkType)
 	if err != nil {
 		log.Printf("[ERR] Failed to create link: %v", err)
+		if errors.Is(err, domain.ErrConflict) {
+			respondError(w, r, "You already have a link to this URL", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrUnsafeURL) {
+			respondError(w, r, "This URL is not allowed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
 		respondError(w, r, "Failed to create link", http.StatusInternalServerError)
 		return
 	}
@@ -219,6 +508,14 @@ func (h *LinkHandler) UpdateLink(w http.ResponseWriter, r *http.Request) {
 	link, err := h.linkSvc.UpdateLink(r.Context(), linkID, user.ID, title, url, linkType, isActive)
 	if err != nil {
 		log.Printf("[ERR] Failed to update link: %v", err)
+		if errors.Is(err, domain.ErrConflict) {
+			respondError(w, r, "You already have a link to this URL", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrUnsafeURL) {
+			respondError(w, r, "This URL is not allowed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
 		respondError(w, r, "Failed to update link", http.StatusInternalServerError)
 		return
 	}
@@ -279,6 +576,9 @@ func (h *LinkHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
 	if IsTurboRequest(r) {
 		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
 		fmt.Fprintf(w, `<turbo-stream action="remove" target="link-%s"></turbo-stream>`, linkID)
+		// The link shows up in the trash, where it can be restored
+		trash, revisions := h.trashAndRevisions(r, user.ID)
+		dashboard.TrashStream(trash, revisions, "Link moved to trash").Render(r.Context(), w)
 		return
 	}
 
@@ -326,8 +626,28 @@ func (h *LinkHandler) RefreshLinkMetadata(w http.ResponseWriter, r *http.Request
 	TurboAwareRedirect(w, r, "/dashboard?tab=links")
 }
 
-// ReorderLinks handles POST /dashboard/links/reorder
-func (h *LinkHandler) ReorderLinks(w http.ResponseWriter, r *http.Request) {
+// scheduleTimeLayout matches the value format of <input type="datetime-local">.
+const scheduleTimeLayout = "2006-01-02T15:04"
+
+// parseScheduleTime parses an optional schedule form value.
+// Empty values yield nil, meaning that side of the window is open.
+func parseScheduleTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(scheduleTimeLayout, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", value)
+	}
+	return &t, nil
+}
+
+// ScheduleLink handles POST /dashboard/links/{id}/schedule
+func (h *LinkHandler) ScheduleLink(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -339,28 +659,772 @@ func (h *LinkHandler) ReorderLinks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var payload struct {
-		OrderedIDs []string `json:"ordered_ids"`
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	linkIDs := make([]domain.LinkID, len(payload.OrderedIDs))
-	for i, id := range payload.OrderedIDs {
-		linkIDs[i] = domain.LinkID(id)
+	startsAt, err := parseScheduleTime(r.FormValue("starts_at"))
+	if err != nil {
+		respondError(w, r, "Invalid start time", http.StatusBadRequest)
+		return
+	}
+	endsAt, err := parseScheduleTime(r.FormValue("ends_at"))
+	if err != nil {
+		respondError(w, r, "Invalid end time", http.StatusBadRequest)
+		return
 	}
 
-	if err := h.linkSvc.ReorderLinks(r.Context(), user.ID, linkIDs); err != nil {
-		log.Printf("[ERR] Failed to reorder links: %v", err)
-		http.Error(w, "Failed to reorder", http.StatusInternalServerError)
+	fallbackURL := sanitizer.SanitizeURL(r.FormValue("fallback_url"))
+	input := struct {
+		FallbackURL string `validate:"omitempty,url"`
+	}{
+		FallbackURL: fallbackURL,
+	}
+	if err := validator.ValidateStruct(input); err != nil {
+		respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"success": true}`))
+	link, err := h.linkSvc.ScheduleLink(r.Context(), linkID, user.ID, startsAt, endsAt, fallbackURL)
+	if err != nil {
+		log.Printf("[ERR] Failed to schedule link: %v", err)
+		respondError(w, r, "Failed to schedule link", linkErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// LimitLink handles POST /dashboard/links/{id}/limits
+// Empty or zero max_clicks / max_visitors remove that limit.
+func (h *LinkHandler) LimitLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	maxClicks, err := parseLimit(r.FormValue("max_clicks"))
+	if err != nil {
+		respondError(w, r, "Invalid click limit", http.StatusBadRequest)
+		return
+	}
+	maxVisitors, err := parseLimit(r.FormValue("max_visitors"))
+	if err != nil {
+		respondError(w, r, "Invalid visitor limit", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.linkSvc.LimitLink(r.Context(), linkID, user.ID, maxClicks, maxVisitors)
+	if err != nil {
+		respondLinkError(w, r, "Failed to update link limits", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// parseLimit parses a click or visitor limit form value; empty means no limit.
+func parseLimit(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// UpdateLinkSlug handles POST /dashboard/links/{id}/slug
+func (h *LinkHandler) UpdateLinkSlug(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	slug := sanitizer.Normalize(r.FormValue("slug"))
+
+	link, err := h.linkSvc.SetLinkSlug(r.Context(), linkID, user.ID, slug)
+	if err != nil {
+		log.Printf("[ERR] Failed to update link slug: %v", err)
+		switch {
+		case errors.Is(err, domain.ErrConflict):
+			respondError(w, r, "That short link is already taken", http.StatusConflict)
+		case errors.Is(err, domain.ErrBadRequest):
+			respondError(w, r, "Short links may only contain lowercase letters, digits and hyphens", http.StatusBadRequest)
+		default:
+			respondError(w, r, "Failed to update short link", linkErrorStatus(err, http.StatusInternalServerError))
+		}
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkVariants handles POST /dashboard/links/{id}/variants
+// The form repeats variant_url and variant_weight once per variant; rows without a URL are ignored.
+func (h *LinkHandler) UpdateLinkVariants(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	urls := r.Form["variant_url"]
+	weights := r.Form["variant_weight"]
+
+	var variants []domain.LinkVariant
+	for i, raw := range urls {
+		variantURL := sanitizer.SanitizeURL(raw)
+		if variantURL == "" {
+			continue
+		}
+
+		weight := 1
+		if i < len(weights) && strings.TrimSpace(weights[i]) != "" {
+			weight, err = strconv.Atoi(strings.TrimSpace(weights[i]))
+			if err != nil {
+				respondError(w, r, "Variant weight must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+
+		variant := domain.LinkVariant{URL: variantURL, Weight: weight}
+		if err := validator.ValidateStruct(variant); err != nil {
+			respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		variants = append(variants, variant)
+	}
+
+	link, err := h.linkSvc.SetLinkVariants(r.Context(), linkID, user.ID, variants)
+	if err != nil {
+		log.Printf("[ERR] Failed to update link variants: %v", err)
+		respondError(w, r, "Failed to update variants: "+err.Error(), linkErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkRules handles POST /dashboard/links/{id}/rules
+// The form repeats rule_field, rule_values (comma separated) and rule_url once per rule;
+// rows without a URL are ignored.
+func (h *LinkHandler) UpdateLinkRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	fields := r.Form["rule_field"]
+	values := r.Form["rule_values"]
+	urls := r.Form["rule_url"]
+
+	var rules []domain.RedirectRule
+	for i, raw := range urls {
+		ruleURL := sanitizer.SanitizeURL(raw)
+		if ruleURL == "" {
+			continue
+		}
+
+		rule := domain.RedirectRule{URL: ruleURL}
+		if i < len(fields) {
+			rule.Field = domain.RedirectRuleField(fields[i])
+		}
+		if i < len(values) {
+			for _, v := range strings.Split(values[i], ",") {
+				if v = sanitizer.Normalize(v); v != "" {
+					rule.Values = append(rule.Values, v)
+				}
+			}
+		}
+
+		if err := validator.ValidateStruct(rule); err != nil {
+			respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	link, err := h.linkSvc.SetLinkRules(r.Context(), linkID, user.ID, rules)
+	if err != nil {
+		log.Printf("[ERR] Failed to update link rules: %v", err)
+		respondError(w, r, "Failed to update rules: "+err.Error(), linkErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkProduct handles POST /dashboard/links/{id}/product
+func (h *LinkHandler) UpdateLinkProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	product := &domain.Product{
+		Price:            sanitizer.Normalize(r.FormValue("price")),
+		SalePrice:        sanitizer.Normalize(r.FormValue("sale_price")),
+		Currency:         strings.ToUpper(sanitizer.Normalize(r.FormValue("currency"))),
+		SKU:              sanitizer.Normalize(r.FormValue("sku")),
+		AffiliateNetwork: sanitizer.Normalize(r.FormValue("affiliate_network")),
+	}
+	if err := validator.ValidateStruct(product); err != nil {
+		respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if product.AffiliateNetwork != "" && !h.knownAffiliateNetwork(product.AffiliateNetwork) {
+		respondError(w, r, fmt.Sprintf("Unknown affiliate network %q", product.AffiliateNetwork), http.StatusBadRequest)
+		return
+	}
+	if *product == (domain.Product{}) {
+		product = nil
+	}
+
+	link, err := h.linkSvc.SetLinkProduct(r.Context(), linkID, user.ID, product)
+	if err != nil {
+		log.Printf("[ERR] Failed to update link product: %v", err)
+		respondError(w, r, "Failed to update product: "+err.Error(), linkErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkUTM handles POST /dashboard/links/{id}/utm
+// Fields left empty fall back to the profile's default UTM parameters.
+func (h *LinkHandler) UpdateLinkUTM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	utm := utmFromForm(r)
+	if err := validator.ValidateStruct(utm); err != nil {
+		respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.linkSvc.SetLinkUTM(r.Context(), linkID, user.ID, &utm)
+	if err != nil {
+		log.Printf("[ERR] Failed to update link UTM parameters: %v", err)
+		respondError(w, r, "Failed to update UTM parameters", linkErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkDeepLink handles POST /dashboard/links/{id}/deep-link
+// The "enabled" form value turns the deep-link mode on ("true", "1" or "on") or off.
+func (h *LinkHandler) UpdateLinkDeepLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	enabled := r.FormValue("enabled")
+	link, err := h.linkSvc.SetLinkDeepLink(r.Context(), linkID, user.ID, enabled == "true" || enabled == "1" || enabled == "on")
+	if err != nil {
+		respondLinkError(w, r, "Failed to update deep link mode", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// UpdateLinkThumbnail handles POST /dashboard/links/{id}/thumbnail.
+// A "thumbnail" file upload replaces the link's preview image; remove=true goes
+// back to the image found on the destination page.
+func (h *LinkHandler) UpdateLinkThumbnail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	if linkID == "" {
+		http.Error(w, "Link ID required", http.StatusBadRequest)
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+	} else if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	var link *domain.Link
+	if remove := r.FormValue("remove"); remove == "true" || remove == "1" {
+		link, err = h.linkSvc.SetLinkThumbnail(r.Context(), linkID, user.ID, nil)
+	} else {
+		file, _, ferr := r.FormFile("thumbnail")
+		if ferr != nil {
+			respondError(w, r, "Choose an image to upload", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		link, err = h.linkSvc.SetLinkThumbnail(r.Context(), linkID, user.ID, file)
+	}
+	if err != nil {
+		respondLinkError(w, r, "Failed to update thumbnail", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkReplaceStream(link).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// knownAffiliateNetwork reports whether id is one of the configured affiliate networks.
+func (h *LinkHandler) knownAffiliateNetwork(id string) bool {
+	if h.affiliates == nil {
+		return false
+	}
+	for _, n := range h.affiliates.Networks() {
+		if n.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkVariantStats handles GET /dashboard/links/{id}/variants
+// It renders the lazy-loaded A/B results frame of a link.
+func (h *LinkHandler) LinkVariantStats(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	link, err := h.linkSvc.GetLink(r.Context(), domain.LinkID(r.PathValue("id")))
+	if err != nil || link == nil || link.UserID != user.ID {
+		RespondNotFound(w, r, "Link")
+		return
+	}
+
+	stats, err := h.analyticsSvc.GetVariantStats(r.Context(), string(user.ID), link)
+	if err != nil {
+		log.Printf("[ERR] Failed to load variant stats for link %s: %v", link.ID, err)
+		respondError(w, r, "Failed to load variant stats", http.StatusInternalServerError)
+		return
+	}
+
+	dashboard.VariantStatsFrame(link, stats).Render(r.Context(), w)
+}
+
+// recentRevisionsLimit is how many changes the recent changes card lists.
+const recentRevisionsLimit = 10
+
+// LinkHistory handles GET /dashboard/links/{id}/history
+// It renders the lazy-loaded revision list of a link.
+func (h *LinkHandler) LinkHistory(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID := domain.LinkID(r.PathValue("id"))
+	revisions, err := h.linkSvc.LinkHistory(r.Context(), linkID, user.ID)
+	if err != nil {
+		log.Printf("[ERR] Failed to load history for link %s: %v", linkID, err)
+		respondError(w, r, "Failed to load link history", http.StatusInternalServerError)
+		return
+	}
+
+	dashboard.LinkHistoryFrame(linkID, revisions).Render(r.Context(), w)
+}
+
+// RecentRevisions handles GET /dashboard/revisions
+// It renders the lazy-loaded list of the latest changes to the user's links.
+func (h *LinkHandler) RecentRevisions(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	revisions, err := h.linkSvc.RecentRevisions(r.Context(), user.ID, recentRevisionsLimit)
+	if err != nil {
+		log.Printf("[ERR] Failed to load recent revisions: %v", err)
+		respondError(w, r, "Failed to load recent changes", http.StatusInternalServerError)
+		return
+	}
+
+	dashboard.RecentRevisionsFrame(revisions).Render(r.Context(), w)
+}
+
+// RevertRevision handles POST /dashboard/revisions/{id}/revert
+// It restores the link as it was before the revision, undeleting it if needed.
+func (h *LinkHandler) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	revisionID := domain.LinkRevisionID(r.PathValue("id"))
+	link, undeleted, err := h.linkSvc.RevertLink(r.Context(), revisionID, user.ID)
+	if err != nil {
+		respondLinkError(w, r, "Failed to revert link", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		revisions, err := h.linkSvc.RecentRevisions(r.Context(), user.ID, recentRevisionsLimit)
+		if err != nil {
+			log.Printf("[ERR] Failed to load recent revisions: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkRevertStream(link, undeleted, revisions).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// Trash handles GET /dashboard/trash
+// It renders the lazy-loaded list of the user's deleted links.
+func (h *LinkHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	trash, err := h.linkSvc.ListTrash(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("[ERR] Failed to load trash: %v", err)
+		respondError(w, r, "Failed to load trash", http.StatusInternalServerError)
+		return
+	}
+
+	dashboard.TrashFrame(trash).Render(r.Context(), w)
+}
+
+// RestoreLink handles POST /dashboard/links/{id}/restore
+func (h *LinkHandler) RestoreLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	link, err := h.linkSvc.RestoreLink(r.Context(), domain.LinkID(r.PathValue("id")), user.ID)
+	if err != nil {
+		respondLinkError(w, r, "Failed to restore link", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		trash, revisions := h.trashAndRevisions(r, user.ID)
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.LinkRestoreStream(link, trash, revisions).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// PurgeLink handles POST /dashboard/links/{id}/purge
+// It permanently deletes a link from the trash.
+func (h *LinkHandler) PurgeLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.linkSvc.PurgeLink(r.Context(), domain.LinkID(r.PathValue("id")), user.ID); err != nil {
+		respondLinkError(w, r, "Failed to delete link", err)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		trash, revisions := h.trashAndRevisions(r, user.ID)
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.TrashStream(trash, revisions, "Link deleted permanently").Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// trashAndRevisions loads what the trash and recent changes cards show.
+// Errors are logged and leave the cards empty.
+func (h *LinkHandler) trashAndRevisions(r *http.Request, userID domain.UserID) ([]*domain.Link, []*domain.LinkRevision) {
+	trash, err := h.linkSvc.ListTrash(r.Context(), userID)
+	if err != nil {
+		log.Printf("[ERR] Failed to load trash: %v", err)
+	}
+	revisions, err := h.linkSvc.RecentRevisions(r.Context(), userID, recentRevisionsLimit)
+	if err != nil {
+		log.Printf("[ERR] Failed to load recent revisions: %v", err)
+	}
+	return trash, revisions
+}
+
+// ReorderLinks handles POST /dashboard/links/reorder
+func (h *LinkHandler) ReorderLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		OrderedIDs []string `json:"ordered_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	linkIDs := make([]domain.LinkID, len(payload.OrderedIDs))
+	for i, id := range payload.OrderedIDs {
+		linkIDs[i] = domain.LinkID(id)
+	}
+
+	if err := h.linkSvc.ReorderLinks(r.Context(), user.ID, linkIDs); err != nil {
+		log.Printf("[ERR] Failed to reorder links: %v", err)
+		http.Error(w, "Failed to reorder", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true}`))
+}
+
+// respondLinkError logs a failed link operation and maps domain errors to a status code.
+func respondLinkError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	log.Printf("[ERR] %s: %v", message, err)
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrBadRequest), errors.Is(err, domain.ErrUnsafeURL):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	}
+	respondError(w, r, message, status)
+}
+
+// linkErrorStatus returns the status code for ownership failures, or fallback
+// for any other error of a link setter.
+func linkErrorStatus(err error, fallback int) int {
+	if errors.Is(err, domain.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return fallback
 }
 
 // respondError sends an error response appropriate for the request type.
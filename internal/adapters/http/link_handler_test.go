@@ -1,14 +1,20 @@
 package http_test
 
 import (
+	"bytes"
 	"context"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/elchemista/driplnk/internal/adapters/affiliate"
 	handler "github.com/elchemista/driplnk/internal/adapters/http"
+	"github.com/elchemista/driplnk/internal/adapters/traffic"
+	"github.com/elchemista/driplnk/internal/config"
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/mocks"
 	"github.com/elchemista/driplnk/internal/service"
@@ -22,9 +28,9 @@ func TestLinkHandler_CreateLink(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository()
 	mockMetadata := mocks.NewMockMetadataFetcher()
 
-	linkService := service.NewLinkService(mockRepo, mockMetadata, nil)
+	linkService := service.NewLinkService(mockRepo, mockMetadata, nil, nil, nil, nil)
 	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
-	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		// Seed User and Session
@@ -70,9 +76,9 @@ func TestLinkHandler_HandleRedirect(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository()
 	mockMetadata := mocks.NewMockMetadataFetcher()
 
-	linkService := service.NewLinkService(mockRepo, mockMetadata, nil)
+	linkService := service.NewLinkService(mockRepo, mockMetadata, nil, nil, nil, nil)
 	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
-	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		linkID := domain.LinkID("link-123")
@@ -97,6 +103,165 @@ func TestLinkHandler_HandleRedirect(t *testing.T) {
 		// but we trust the service call happens.
 	})
 
+	t.Run("PendingLink", func(t *testing.T) {
+		startsAt := time.Now().Add(time.Hour)
+		mockRepo.AddLink(&domain.Link{
+			ID:       "link-pending",
+			UserID:   "user-1",
+			URL:      "https://destination.com",
+			IsActive: true,
+			StartsAt: &startsAt,
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/go/link-pending", nil)
+		req.SetPathValue("id", "link-pending")
+		w := httptest.NewRecorder()
+
+		h.HandleRedirect(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("ExpiredLinkWithFallback", func(t *testing.T) {
+		endsAt := time.Now().Add(-time.Hour)
+		mockRepo.AddLink(&domain.Link{
+			ID:          "link-expired-fallback",
+			UserID:      "user-1",
+			URL:         "https://destination.com",
+			IsActive:    true,
+			EndsAt:      &endsAt,
+			FallbackURL: "https://destination.com/ended",
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/go/link-expired-fallback", nil)
+		req.SetPathValue("id", "link-expired-fallback")
+		w := httptest.NewRecorder()
+
+		h.HandleRedirect(w, req)
+
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://destination.com/ended", w.Header().Get("Location"))
+	})
+
+	t.Run("ExpiredLinkWithoutFallback", func(t *testing.T) {
+		endsAt := time.Now().Add(-time.Hour)
+		mockRepo.AddLink(&domain.Link{
+			ID:       "link-expired",
+			UserID:   "user-1",
+			URL:      "https://destination.com",
+			IsActive: true,
+			EndsAt:   &endsAt,
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/go/link-expired", nil)
+		req.SetPathValue("id", "link-expired")
+		w := httptest.NewRecorder()
+
+		h.HandleRedirect(w, req)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), "Link Expired")
+	})
+
+	t.Run("Slug", func(t *testing.T) {
+		mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "alice"})
+		mockUserRepo.AddUser(&domain.User{ID: "user-2", Handle: "bob"})
+		mockRepo.AddLink(&domain.Link{
+			ID:       "link-slug",
+			UserID:   "user-1",
+			Slug:     "merch",
+			URL:      "https://shop.example.com",
+			IsActive: true,
+		})
+
+		// /go/{slug}
+		req := httptest.NewRequest(http.MethodGet, "/go/merch", nil)
+		req.SetPathValue("id", "merch")
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://shop.example.com", w.Header().Get("Location"))
+
+		// /{handle}/{slug}
+		req = httptest.NewRequest(http.MethodGet, "/alice/merch", nil)
+		req.SetPathValue("handle", "alice")
+		req.SetPathValue("slug", "merch")
+		w = httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+		// Another profile's handle does not resolve the slug
+		req = httptest.NewRequest(http.MethodGet, "/bob/merch", nil)
+		req.SetPathValue("handle", "bob")
+		req.SetPathValue("slug", "merch")
+		w = httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Variants", func(t *testing.T) {
+		link := &domain.Link{
+			ID:       "link-ab",
+			UserID:   "user-1",
+			URL:      "https://destination.com",
+			IsActive: true,
+			Variants: []domain.LinkVariant{
+				{Name: "A", URL: "https://a.example.com", Weight: 50},
+				{Name: "B", URL: "https://b.example.com", Weight: 50},
+			},
+		}
+		mockRepo.AddLink(link)
+
+		expected := link.VariantFor("visitor-42").URL
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/go/link-ab", nil)
+			req.SetPathValue("id", "link-ab")
+			req.AddCookie(&http.Cookie{Name: "drip_visitor", Value: "visitor-42"})
+			w := httptest.NewRecorder()
+
+			h.HandleRedirect(w, req)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			assert.Equal(t, expected, w.Header().Get("Location"))
+		}
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		mockRepo.AddLink(&domain.Link{
+			ID:       "link-rules",
+			UserID:   "user-1",
+			URL:      "https://destination.com",
+			IsActive: true,
+			Rules: []domain.RedirectRule{
+				{Field: domain.RuleFieldCountry, Values: []string{"DE", "AT"}, URL: "https://shop.example.de"},
+				{Field: domain.RuleFieldDeviceType, Values: []string{"mobile"}, URL: "https://apps.example.com"},
+			},
+		})
+
+		cases := []struct {
+			name     string
+			country  string
+			ua       string
+			location string
+		}{
+			{"country", "AT", "Mozilla/5.0 (Windows NT 10.0)", "https://shop.example.de"},
+			{"device", "US", "Mozilla/5.0 (iPhone) Mobile", "https://apps.example.com"},
+			{"default", "US", "Mozilla/5.0 (Windows NT 10.0)", "https://destination.com"},
+		}
+		for _, tc := range cases {
+			req := httptest.NewRequest(http.MethodGet, "/go/link-rules", nil)
+			req.SetPathValue("id", "link-rules")
+			req.Header.Set("CF-IPCountry", tc.country)
+			req.Header.Set("User-Agent", tc.ua)
+			w := httptest.NewRecorder()
+
+			h.HandleRedirect(w, req)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code, tc.name)
+			assert.Equal(t, tc.location, w.Header().Get("Location"), tc.name)
+		}
+	})
+
 	t.Run("NotFound", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/go/invalid", nil)
 		req.SetPathValue("id", "invalid")
@@ -114,8 +279,8 @@ func TestLinkHandler_DeleteLink(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository()
 	mockMetadata := mocks.NewMockMetadataFetcher()
 
-	linkService := service.NewLinkService(mockRepo, mockMetadata, nil)
-	h := handler.NewLinkHandler(linkService, nil, mockSessionManager, mockUserRepo)
+	linkService := service.NewLinkService(mockRepo, mockMetadata, nil, nil, nil, nil)
+	h := handler.NewLinkHandler(linkService, nil, mockSessionManager, mockUserRepo, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		user := &domain.User{ID: "user-1"}
@@ -134,7 +299,630 @@ func TestLinkHandler_DeleteLink(t *testing.T) {
 		assert.Equal(t, http.StatusSeeOther, w.Code)
 
 		// Verify
-		_, err := mockRepo.GetByID(context.Background(), "link-1")
-		assert.ErrorIs(t, err, domain.ErrNotFound)
+		link, err := mockRepo.GetByID(context.Background(), "link-1")
+		assert.NoError(t, err)
+		assert.True(t, link.IsTrashed())
 	})
+
+	t.Run("Restore", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-1/restore", nil)
+		req.Header.Set("Accept", "text/vnd.turbo-stream.html")
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+
+		h.RestoreLink(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `target="links-list"`)
+		assert.Contains(t, w.Body.String(), "The trash is empty.")
+
+		link, err := mockRepo.GetByID(context.Background(), "link-1")
+		assert.NoError(t, err)
+		assert.False(t, link.IsTrashed())
+	})
+
+	t.Run("PurgeLiveLink", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-1/purge", nil)
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+
+		h.PurgeLink(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestLinkHandler_RevertRevision(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockRevisions := mocks.NewMockLinkRevisionRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, mocks.NewMockMetadataFetcher(), nil, mockRevisions, nil, nil)
+	h := handler.NewLinkHandler(linkService, nil, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1"})
+	mockUserRepo.AddUser(&domain.User{ID: "user-2"})
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", Title: "Tour dates", URL: "https://example.com"})
+	if err := linkService.DeleteLink(context.Background(), "link-1", "user-1"); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+	revisions, _ := mockRevisions.ListByLink(context.Background(), "link-1")
+	revisionID := string(revisions[0].ID)
+
+	revert := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/revisions/"+revisionID+"/revert", nil)
+		req.Header.Set("Accept", "text/vnd.turbo-stream.html")
+		req.SetPathValue("id", revisionID)
+		w := httptest.NewRecorder()
+		h.RevertRevision(w, req)
+		return w
+	}
+
+	t.Run("OtherUser", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-2")
+		assert.Equal(t, http.StatusForbidden, revert().Code)
+	})
+
+	t.Run("Undelete", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+		w := revert()
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `target="links-list"`)
+		assert.Contains(t, w.Body.String(), "Link restored!")
+
+		link, err := mockRepo.GetByID(context.Background(), "link-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Tour dates", link.Title)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/dashboard/links/link-1/history", nil)
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+
+		h.LinkHistory(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `id="link-history-link-1"`)
+		assert.Contains(t, w.Body.String(), "revert")
+		assert.Contains(t, w.Body.String(), "delete")
+	})
+}
+
+func TestLinkHandler_ProtectedLink(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockRepo.AddLink(&domain.Link{ID: "link-secret", UserID: "user-1", Title: "Secret", URL: "https://secret.example.com", IsActive: true})
+	_, err := linkService.ProtectLink(context.Background(), "link-secret", "user-1", domain.ProtectionPassword, "open-sesame")
+	assert.NoError(t, err)
+
+	redirect := func(cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go/link-secret", nil)
+		req.SetPathValue("id", "link-secret")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		return w
+	}
+	unlock := func(password string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Add("password", password)
+		req := httptest.NewRequest(http.MethodPost, "/go/link-secret/unlock", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-secret")
+		w := httptest.NewRecorder()
+		h.UnlockLink(w, req)
+		return w
+	}
+
+	t.Run("ShowsGate", func(t *testing.T) {
+		w := redirect()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "password protected")
+		assert.Empty(t, w.Header().Get("Location"))
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		w := unlock("nope")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Wrong password")
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("CorrectPasswordUnlocks", func(t *testing.T) {
+		w := unlock("open-sesame")
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/go/link-secret", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		assert.Len(t, cookies, 1)
+
+		w = redirect(cookies...)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://secret.example.com", w.Header().Get("Location"))
+	})
+
+	t.Run("ForgedCookieRejected", func(t *testing.T) {
+		w := redirect(&http.Cookie{Name: "drip_unlock_link-secret", Value: "anything"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "password protected")
+	})
+}
+
+func TestLinkHandler_AffiliateRedirect(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	affiliates := affiliate.NewAffiliateAdapter([]config.AffiliateNetworkConfig{
+		{ID: "amazon", Name: "Amazon Associates", HostPattern: `(^|\.)amazon\.com$`, Param: "tag"},
+	})
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, affiliates, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", AffiliateTags: map[string]string{"amazon": "drip-20"}})
+	mockRepo.AddLink(&domain.Link{
+		ID:       "link-product",
+		UserID:   "user-1",
+		URL:      "https://www.amazon.com/dp/B000",
+		Type:     domain.LinkTypeProduct,
+		IsActive: true,
+		Product:  &domain.Product{Price: "10", Currency: "USD", AffiliateNetwork: "amazon"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/go/link-product", nil)
+	req.SetPathValue("id", "link-product")
+	w := httptest.NewRecorder()
+
+	h.HandleRedirect(w, req)
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://www.amazon.com/dp/B000?tag=drip-20", w.Header().Get("Location"))
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+
+	t.Run("UpdateProduct", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+
+		form := url.Values{}
+		form.Add("price", "12.50")
+		form.Add("currency", "usd")
+		form.Add("affiliate_network", "unknown")
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-product/product", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-product")
+		w := httptest.NewRecorder()
+
+		h.UpdateLinkProduct(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		form.Set("affiliate_network", "amazon")
+		req = httptest.NewRequest(http.MethodPost, "/dashboard/links/link-product/product", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-product")
+		w = httptest.NewRecorder()
+
+		h.UpdateLinkProduct(w, req)
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+
+		link, _ := mockRepo.GetByID(context.Background(), "link-product")
+		assert.Equal(t, "12.50", link.Product.Price)
+		assert.Equal(t, "USD", link.Product.Currency)
+	})
+}
+
+func TestLinkHandler_UTMRedirect(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator", UTM: domain.UTMParams{Source: "driplnk", Medium: "social"}})
+	mockRepo.AddLink(&domain.Link{ID: "link-plain", UserID: "user-1", URL: "https://shop.com/item#reviews", IsActive: true})
+	mockRepo.AddLink(&domain.Link{
+		ID:       "link-tagged",
+		UserID:   "user-1",
+		URL:      "https://shop.com/item?color=red&utm_medium=email",
+		IsActive: true,
+		UTM:      &domain.UTMParams{Campaign: "spring sale"},
+	})
+	mockRepo.AddLink(&domain.Link{ID: "link-mail", UserID: "user-1", URL: "mailto:hi@shop.com", IsActive: true})
+
+	redirect := func(id string) string {
+		req := httptest.NewRequest(http.MethodGet, "/go/"+id, nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		return w.Header().Get("Location")
+	}
+
+	assert.Equal(t, "https://shop.com/item?utm_medium=social&utm_source=driplnk#reviews", redirect("link-plain"))
+	// Parameters already in the URL win; the link's campaign is added to the profile defaults
+	assert.Equal(t, "https://shop.com/item?color=red&utm_medium=email&utm_campaign=spring+sale&utm_source=driplnk", redirect("link-tagged"))
+	assert.Equal(t, "mailto:hi@shop.com", redirect("link-mail"))
+
+	stored, _ := mockRepo.GetByID(context.Background(), "link-plain")
+	assert.Equal(t, "https://shop.com/item#reviews", stored.URL)
+
+	t.Run("UpdateLinkUTM", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+
+		form := url.Values{}
+		form.Add("utm_source", " newsletter ")
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-plain/utm", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-plain")
+		w := httptest.NewRecorder()
+
+		h.UpdateLinkUTM(w, req)
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+
+		link, _ := mockRepo.GetByID(context.Background(), "link-plain")
+		assert.Equal(t, &domain.UTMParams{Source: "newsletter"}, link.UTM)
+		assert.Equal(t, "https://shop.com/item?utm_medium=social&utm_source=newsletter#reviews", redirect("link-plain"))
+
+		// Clearing every field goes back to the profile defaults
+		req = httptest.NewRequest(http.MethodPost, "/dashboard/links/link-plain/utm", strings.NewReader(""))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-plain")
+		w = httptest.NewRecorder()
+
+		h.UpdateLinkUTM(w, req)
+		link, _ = mockRepo.GetByID(context.Background(), "link-plain")
+		assert.Nil(t, link.UTM)
+
+		// Another user's link is forbidden, not a server error
+		mockUserRepo.AddUser(&domain.User{ID: "user-2", Handle: "other"})
+		mockSessionManager.SetCurrentUser("user-2")
+		req = httptest.NewRequest(http.MethodPost, "/dashboard/links/link-plain/utm", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-plain")
+		w = httptest.NewRecorder()
+
+		h.UpdateLinkUTM(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestLinkHandler_UnsafeRedirect(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+	safety := mocks.NewMockLinkSafetyChecker()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, safety, nil)
+	analyticsService := service.NewAnalyticsService(mocks.NewMockAnalyticsRepository())
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator"})
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", URL: "https://shady.example/offer", IsActive: true})
+
+	redirect := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go/link-1", nil)
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusTemporaryRedirect, redirect().Code)
+
+	// A blocklist update applies to the existing link on its next click
+	safety.Block("shady.example")
+	w := redirect()
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	t.Run("CreateLink", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+
+		form := url.Values{"title": {"Offer"}, "url": {"https://shady.example/other"}, "type": {"standard"}}
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.CreateLink(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestLinkHandler_LimitedRedirect(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mocks.NewMockAnalyticsRepository())
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator"})
+	mockRepo.AddLink(&domain.Link{ID: "link-clicks", UserID: "user-1", URL: "https://drop.example", IsActive: true, MaxClicks: 1})
+	mockRepo.AddLink(&domain.Link{
+		ID:          "link-visitors",
+		UserID:      "user-1",
+		URL:         "https://giveaway.example",
+		IsActive:    true,
+		MaxVisitors: 1,
+		FallbackURL: "https://creator.example/sold-out",
+	})
+
+	redirect := func(id string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go/"+id, nil)
+		req.SetPathValue("id", id)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		return w
+	}
+
+	t.Run("ClickLimit", func(t *testing.T) {
+		assert.Equal(t, http.StatusTemporaryRedirect, redirect("link-clicks").Code)
+		assert.Equal(t, http.StatusGone, redirect("link-clicks").Code)
+
+		link, _ := mockRepo.GetByID(context.Background(), "link-clicks")
+		assert.Equal(t, uint64(1), link.ClickCount)
+	})
+
+	t.Run("VisitorLimit", func(t *testing.T) {
+		first := redirect("link-visitors")
+		assert.Equal(t, http.StatusTemporaryRedirect, first.Code)
+		assert.Equal(t, "https://giveaway.example", first.Header().Get("Location"))
+
+		// The first visitor gets a cookie and may come back
+		cookies := first.Result().Cookies()
+		assert.Len(t, cookies, 1)
+		again := redirect("link-visitors", cookies...)
+		assert.Equal(t, "https://giveaway.example", again.Header().Get("Location"))
+
+		// A new visitor goes to the fallback
+		other := redirect("link-visitors")
+		assert.Equal(t, http.StatusTemporaryRedirect, other.Code)
+		assert.Equal(t, "https://creator.example/sold-out", other.Header().Get("Location"))
+	})
+
+	t.Run("LimitLink", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+
+		form := url.Values{"max_clicks": {"5"}, "max_visitors": {""}}
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-clicks/limits", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-clicks")
+		w := httptest.NewRecorder()
+		h.LimitLink(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		link, _ := mockRepo.GetByID(context.Background(), "link-clicks")
+		assert.Equal(t, uint64(5), link.MaxClicks)
+		assert.Equal(t, uint64(1), link.ClickCount)
+
+		form.Set("max_clicks", "-1")
+		req = httptest.NewRequest(http.MethodPost, "/dashboard/links/link-clicks/limits", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "link-clicks")
+		w = httptest.NewRecorder()
+		h.LimitLink(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestLinkHandler_DeepLinkRedirect(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+	resolver := mocks.NewMockSocialResolver()
+	resolver.AddPlatform("youtube.com", &domain.SocialPlatform{
+		Name:     "YouTube",
+		AppLinks: domain.AppLinkTemplates{IOS: "youtube://{host}/{path}{query}"},
+	})
+
+	linkService := service.NewLinkService(mockRepo, nil, resolver, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mocks.NewMockAnalyticsRepository())
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator"})
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", Title: "My channel", URL: "https://www.youtube.com/watch?v=abc", IsActive: true, Type: domain.LinkTypeSocial})
+
+	redirect := func(userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go/link-1", nil)
+		req.SetPathValue("id", "link-1")
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		return w
+	}
+	iPhone := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+
+	// Deep-link mode is opt-in
+	assert.Equal(t, http.StatusTemporaryRedirect, redirect(iPhone).Code)
+
+	mockSessionManager.SetCurrentUser("user-1")
+	form := url.Values{"enabled": {"true"}}
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-1/deep-link", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", "link-1")
+	h.UpdateLinkDeepLink(httptest.NewRecorder(), req)
+
+	w := redirect(iPhone)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `data-app-url="youtube://www.youtube.com/watch?v=abc"`)
+	assert.Contains(t, w.Body.String(), `data-web-url="https://www.youtube.com/watch?v=abc"`)
+
+	// Desktops and phones without an app link for the platform go straight to the web
+	desktop := redirect("Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15")
+	assert.Equal(t, http.StatusTemporaryRedirect, desktop.Code)
+	android := redirect("Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Mobile Safari/537.36")
+	assert.Equal(t, http.StatusTemporaryRedirect, android.Code)
+	assert.Equal(t, "https://www.youtube.com/watch?v=abc", android.Header().Get("Location"))
+}
+
+func TestLinkHandler_UpdateLinkThumbnail(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+	thumbnails := mocks.NewMockThumbnailStore()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, thumbnails)
+	analyticsService := service.NewAnalyticsService(mocks.NewMockAnalyticsRepository())
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator"})
+	mockSessionManager.SetCurrentUser("user-1")
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", Title: "Blog", URL: "https://blog.example.com", IsActive: true,
+		Metadata: map[string]string{"og:image": "https://blog.example.com/og.png", domain.MetaThumbnail: "https://cdn.example.com/cached.webp"}})
+
+	upload := func(remove bool) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		if remove {
+			mw.WriteField("remove", "true")
+		} else {
+			fw, _ := mw.CreateFormFile("thumbnail", "cover.png")
+			fw.Write([]byte("image"))
+		}
+		mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-1/thumbnail", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+		h.UpdateLinkThumbnail(w, req)
+		return w
+	}
+
+	w := upload(false)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	link, _ := mockRepo.GetByID(context.Background(), "link-1")
+	assert.Len(t, thumbnails.StoreCalls, 1)
+	assert.NotEmpty(t, link.Metadata[domain.MetaCustomThumbnail])
+	assert.Equal(t, link.Metadata[domain.MetaCustomThumbnail], link.ThumbnailURL())
+
+	w = upload(true)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	link, _ = mockRepo.GetByID(context.Background(), "link-1")
+	assert.Equal(t, "https://cdn.example.com/cached.webp", link.ThumbnailURL())
+
+	// A form without a file is rejected
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/links/link-1/thumbnail", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", "link-1")
+	w = httptest.NewRecorder()
+	h.UpdateLinkThumbnail(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLinkHandler_BotClicks(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	bots := traffic.NewBotAdapter(config.BotDetectionConfig{UserAgentPatterns: []string{"slackbot"}})
+	h := handler.NewLinkHandler(linkService, analyticsService, mocks.NewMockSessionManager(), mockUserRepo, nil, bots)
+
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", URL: "https://shop.com", IsActive: true, MaxClicks: 1})
+
+	redirect := func(userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go/link-1", nil)
+		req.SetPathValue("id", "link-1")
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Accept-Language", "en")
+		w := httptest.NewRecorder()
+		h.HandleRedirect(w, req)
+		return w
+	}
+	const slack = "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"
+
+	// A chat app previewing the link is redirected but does not use up the link
+	w := redirect(slack)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	link, _ := mockRepo.GetByID(context.Background(), "link-1")
+	assert.Equal(t, uint64(0), link.ClickCount)
+	assert.Eventually(t, func() bool {
+		events := mockAnalyticsRepo.GetEvents()
+		return len(events) == 1 && events[0].IsBot && events[0].Meta[domain.MetaIsBot] == ""
+	}, time.Second, 10*time.Millisecond)
+
+	// The visitor still gets the one click
+	w = redirect("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148")
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	link, _ = mockRepo.GetByID(context.Background(), "link-1")
+	assert.Equal(t, uint64(1), link.ClickCount)
+
+	// Bots do not get past a used-up link either
+	assert.Equal(t, http.StatusGone, redirect(slack).Code)
+
+	assert.Eventually(t, func() bool {
+		summary, _ := analyticsService.GetSummary(context.Background(), "user-1", nil)
+		return summary.TotalClicks == 1 && summary.BotClicks == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLinkHandler_LandingReferrer(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockUserRepo := mocks.NewMockUserRepository()
+	mockSessions := mocks.NewMockSessionManager()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	groupService := service.NewLinkGroupService(mocks.NewMockLinkGroupRepository(), mockRepo)
+	pages := handler.NewPageHandler(mockUserRepo, mockSessions, linkService, groupService, analyticsService)
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessions, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "creator"})
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", URL: "https://shop.com", IsActive: true})
+
+	// viewProfile returns the landing cookies set by a profile view
+	viewProfile := func(referer string) []*http.Cookie {
+		req := httptest.NewRequest(http.MethodGet, "/u/creator", nil)
+		req.SetPathValue("handle", "creator")
+		if referer != "" {
+			req.Header.Set("Referer", referer)
+		}
+		w := httptest.NewRecorder()
+		pages.Profile(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		return w.Result().Cookies()
+	}
+	// click returns the referrer recorded for a click made on the profile page
+	click := func(cookies []*http.Cookie) string {
+		before := len(mockAnalyticsRepo.GetEvents())
+		req := httptest.NewRequest(http.MethodGet, "/go/link-1", nil)
+		req.SetPathValue("id", "link-1")
+		req.Header.Set("Referer", "http://example.com/creator")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		h.HandleRedirect(httptest.NewRecorder(), req)
+		assert.Eventually(t, func() bool { return len(mockAnalyticsRepo.GetEvents()) == before+1 }, time.Second, 10*time.Millisecond)
+		return mockAnalyticsRepo.GetEvents()[before].Meta["referrer"]
+	}
+
+	fromInstagram := viewProfile("https://l.instagram.com/?u=x")
+	assert.Equal(t, "l.instagram.com", click(fromInstagram))
+	assert.Empty(t, viewProfile("http://example.com/other"), "views from our own pages keep the earlier landing")
+	assert.Equal(t, "", click(viewProfile("")), "clicks from direct visits have no referrer")
+	assert.Equal(t, domain.ReferrerInternal, click(nil))
+	assert.Equal(t, domain.ReferrerInternal, click([]*http.Cookie{{Name: "drip_landing", Value: "<script>"}}))
 }
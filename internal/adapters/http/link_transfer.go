@@ -0,0 +1,295 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/sanitizer"
+	"github.com/elchemista/driplnk/views/dashboard"
+)
+
+// maxImportFileSize bounds uploaded import files.
+const maxImportFileSize = 2 << 20
+
+// linkCSVHeader is the column order of exported CSV files. Imports match columns by name.
+var linkCSVHeader = []string{"title", "url", "type", "order", "is_active", "slug", "metadata"}
+
+// ExportLinks handles GET /dashboard/links/export?format=csv|json
+func (h *LinkHandler) ExportLinks(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	records, err := h.linkSvc.ExportLinks(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("[ERR] Failed to export links: %v", err)
+		respondError(w, r, "Failed to export links", http.StatusInternalServerError)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	filename := fmt.Sprintf("driplnk-links-%s-%s.%s", user.Handle, time.Now().Format("2006-01-02"), format)
+
+	switch format {
+	case "json":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			log.Printf("[ERR] Failed to write links JSON: %v", err)
+		}
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if err := writeLinksCSV(w, records); err != nil {
+			log.Printf("[ERR] Failed to write links CSV: %v", err)
+		}
+	default:
+		http.Error(w, "Unsupported format", http.StatusBadRequest)
+	}
+}
+
+// ImportLinks handles POST /dashboard/links/import
+// It accepts a multipart "file" in CSV or JSON (as produced by ExportLinks) and a
+// "mode" of merge or replace.
+func (h *LinkHandler) ImportLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+(64<<10))
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		respondError(w, r, "Import file is too large or invalid", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, r, "Please choose a CSV or JSON file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(w, r, "Failed to read import file", http.StatusBadRequest)
+		return
+	}
+
+	var records []domain.LinkRecord
+	if isJSONImport(header.Filename, data) {
+		records, err = parseLinksJSON(data)
+	} else {
+		records, err = parseLinksCSV(data)
+	}
+	if err != nil {
+		respondError(w, r, "Invalid import file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mode := domain.LinkImportMode(r.FormValue("mode"))
+	result, err := h.linkSvc.ImportLinks(r.Context(), user.ID, records, mode)
+	if err != nil {
+		log.Printf("[ERR] Failed to import links: %v", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrBadRequest) {
+			status = http.StatusBadRequest
+		}
+		respondError(w, r, "Failed to import links: "+err.Error(), status)
+		return
+	}
+	log.Printf("[INFO] Imported %d links for user %s (%d skipped, %d errors)", result.Created, user.ID, len(result.Skipped), len(result.Errors))
+
+	if IsTurboRequest(r) {
+		links, err := h.linkSvc.ListAllLinks(r.Context(), user.ID)
+		if err != nil {
+			log.Printf("[ERR] Failed to list links after import: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		dashboard.ImportResultStream(result, mode, links).Render(r.Context(), w)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=links")
+}
+
+// isJSONImport detects JSON imports by extension, falling back to sniffing the content.
+func isJSONImport(filename string, data []byte) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return true
+	case ".csv":
+		return false
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// importRecord mirrors domain.LinkRecord but tells a missing is_active apart from false.
+type importRecord struct {
+	Title    string            `json:"title"`
+	URL      string            `json:"url"`
+	Type     domain.LinkType   `json:"type"`
+	Order    int               `json:"order"`
+	IsActive *bool             `json:"is_active"`
+	Slug     string            `json:"slug"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (ir importRecord) toLinkRecord() domain.LinkRecord {
+	active := ir.IsActive == nil || *ir.IsActive
+	return domain.LinkRecord{
+		Title:    sanitizer.Normalize(ir.Title),
+		URL:      sanitizer.SanitizeURL(ir.URL),
+		Type:     domain.LinkType(strings.ToLower(sanitizer.Normalize(string(ir.Type)))),
+		Order:    ir.Order,
+		IsActive: active,
+		Slug:     strings.ToLower(sanitizer.Normalize(ir.Slug)),
+		Metadata: ir.Metadata,
+	}
+}
+
+func parseLinksJSON(data []byte) ([]domain.LinkRecord, error) {
+	var rows []importRecord
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of links: %w", err)
+	}
+	records := make([]domain.LinkRecord, len(rows))
+	for i, row := range rows {
+		records[i] = row.toLinkRecord()
+	}
+	return records, nil
+}
+
+func parseLinksCSV(data []byte) ([]domain.LinkRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("CSV header needs a url column")
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV header needs a title column")
+	}
+
+	var records []domain.LinkRecord
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(fields) {
+				return unescapeCSVCell(fields[i])
+			}
+			return ""
+		}
+
+		ir := importRecord{
+			Title: get("title"),
+			URL:   get("url"),
+			Type:  domain.LinkType(get("type")),
+			Slug:  get("slug"),
+		}
+		if v := strings.TrimSpace(get("order")); v != "" {
+			if ir.Order, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("row %d: invalid order %q", row, v)
+			}
+		}
+		if v := strings.TrimSpace(get("is_active")); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid is_active %q", row, v)
+			}
+			ir.IsActive = &active
+		}
+		if v := strings.TrimSpace(get("metadata")); v != "" {
+			if err := json.Unmarshal([]byte(v), &ir.Metadata); err != nil {
+				return nil, fmt.Errorf("row %d: metadata must be a JSON object", row)
+			}
+		}
+		records = append(records, ir.toLinkRecord())
+	}
+	return records, nil
+}
+
+func writeLinksCSV(w io.Writer, records []domain.LinkRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(linkCSVHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		metadata := ""
+		if len(record.Metadata) > 0 {
+			b, err := json.Marshal(record.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(b)
+		}
+		if err := writer.Write([]string{
+			escapeCSVCell(record.Title),
+			escapeCSVCell(record.URL),
+			string(record.Type),
+			strconv.Itoa(record.Order),
+			strconv.FormatBool(record.IsActive),
+			record.Slug,
+			metadata,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCSVCell prevents spreadsheet formula injection by quoting cells that
+// start with a formula character; unescapeCSVCell reverses it on import.
+func escapeCSVCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func unescapeCSVCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
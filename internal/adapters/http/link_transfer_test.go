@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handler "github.com/elchemista/driplnk/internal/adapters/http"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func importRequest(t *testing.T, filename, content, mode string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(content))
+	mw.WriteField("mode", mode)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/links/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestLinkHandler_ExportImportLinks(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockSessionManager := mocks.NewMockSessionManager()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mocks.NewMockAnalyticsRepository())
+	h := handler.NewLinkHandler(linkService, analyticsService, mockSessionManager, mockUserRepo, nil, nil)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "alice"})
+	mockUserRepo.AddUser(&domain.User{ID: "user-2", Handle: "bob"})
+	mockRepo.AddLink(&domain.Link{ID: "l1", UserID: "user-1", Title: "Shop", URL: "https://shop.example.com", Type: domain.LinkTypeStandard, IsActive: true, Slug: "shop"})
+	mockRepo.AddLink(&domain.Link{ID: "l2", UserID: "user-1", Title: "=HYPERLINK(\"x\")", URL: "https://blog.example.com", Type: domain.LinkTypeStandard, Order: 1})
+
+	var exported string
+	t.Run("Export CSV", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-1")
+		req := httptest.NewRequest(http.MethodGet, "/dashboard/links/export?format=csv", nil)
+		w := httptest.NewRecorder()
+
+		h.ExportLinks(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+		rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "title", rows[0][0])
+		assert.Equal(t, "'=HYPERLINK(\"x\")", rows[2][0], "formula cells must be escaped")
+		exported = w.Body.String()
+	})
+
+	t.Run("Import CSV round trip", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-2")
+		w := httptest.NewRecorder()
+
+		h.ImportLinks(w, importRequest(t, "links.csv", exported, "merge"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		links, _ := mockRepo.ListByUser(context.Background(), "user-2")
+		assert.Len(t, links, 2)
+		assert.Equal(t, "https://shop.example.com", links[0].URL)
+		assert.True(t, links[0].IsActive)
+		assert.Equal(t, "=HYPERLINK(\"x\")", links[1].Title)
+		assert.False(t, links[1].IsActive)
+	})
+
+	t.Run("Import JSON reports rows over Turbo", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("user-2")
+		req := importRequest(t, "links.json", `[{"title":"New","url":"https://new.example.com"},{"title":"Dup","url":"https://shop.example.com"},{"title":"","url":"nope"}]`, "merge")
+		req.Header.Set("Accept", "text/vnd.turbo-stream.html")
+		w := httptest.NewRecorder()
+
+		h.ImportLinks(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Imported 1 link(s)")
+		assert.Contains(t, w.Body.String(), "Row 3")
+		links, _ := mockRepo.ListByUser(context.Background(), "user-2")
+		assert.Len(t, links, 3)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockSessionManager.SetCurrentUser("")
+		w := httptest.NewRecorder()
+
+		h.ImportLinks(w, importRequest(t, "links.csv", exported, "merge"))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
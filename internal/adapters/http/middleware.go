@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"log"
 	"net/http"
-	"strings"
 
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/service"
@@ -14,10 +13,12 @@ import (
 
 type AnalyticsMiddleware struct {
 	service *service.AnalyticsService
+	sources domain.TrafficSourceResolver // optional: names the traffic source of views
+	bots    domain.BotClassifier         // optional: flags views of automated clients
 }
 
-func NewAnalyticsMiddleware(s *service.AnalyticsService) *AnalyticsMiddleware {
-	return &AnalyticsMiddleware{service: s}
+func NewAnalyticsMiddleware(s *service.AnalyticsService, sources domain.TrafficSourceResolver, bots domain.BotClassifier) *AnalyticsMiddleware {
+	return &AnalyticsMiddleware{service: s, sources: sources, bots: bots}
 }
 
 // TrackView is a middleware that records a page view event asynchronously.
@@ -100,23 +101,16 @@ func (m *AnalyticsMiddleware) TrackView(next http.HandlerFunc) http.HandlerFunc
 			meta := make(map[string]string)
 			meta["path"] = r.URL.Path
 
-			// User Agent & IP
-			ua := r.Header.Get("User-Agent")
-			if ua != "" {
-				meta["user_agent"] = ua
-				if strings.Contains(strings.ToLower(ua), "mobile") || strings.Contains(strings.ToLower(ua), "android") {
-					meta["device_type"] = "mobile"
-				} else {
-					meta["device_type"] = "desktop"
-				}
-			}
+			// User Agent, device & country
+			addRequestMeta(meta, r)
 
-			country := r.Header.Get("CF-IPCountry")
-			if country == "" {
-				country = r.Header.Get("X-AppEngine-Country")
+			// Campaign parameters and the platform that sent the visitor
+			addCampaignMeta(meta, r)
+			if m.sources != nil && meta["source"] != domain.SourceQR {
+				meta["source"] = m.sources.ResolveSource(meta["referrer"], meta["utm_source"])
 			}
-			if country != "" {
-				meta["country"] = country
+			if isBotRequest(r, m.bots) {
+				meta[domain.MetaIsBot] = "true"
 			}
 
 			// Attempt to find Target User ID from Context
@@ -197,8 +191,11 @@ func CSRFMiddleware(next http.Handler, secure bool) http.Handler {
 
 		// 3. Enforce CSRF check for unsafe methods
 		if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodDelete || r.Method == http.MethodPatch {
-			// Verify header matches cookie
+			// Verify header matches cookie; plain (non-Turbo) forms send it as a field
 			headerToken := r.Header.Get("X-CSRF-Token")
+			if headerToken == "" {
+				headerToken = r.PostFormValue("csrf_token")
+			}
 			if headerToken == "" || headerToken != token {
 				http.Error(w, "Forbidden: Invalid CSRF Token", http.StatusForbidden)
 				return
@@ -0,0 +1,57 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	handler "github.com/elchemista/driplnk/internal/adapters/http"
+	"github.com/elchemista/driplnk/internal/adapters/traffic"
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsMiddleware_TrackViewSources(t *testing.T) {
+	sources := traffic.NewSourceAdapter([]config.TrafficSourceConfig{
+		{Name: "instagram", Domains: []string{"instagram.com"}, UTMSources: []string{"ig"}},
+	})
+	profile := func(w http.ResponseWriter, r *http.Request) {
+		*r = *r.WithContext(context.WithValue(r.Context(), domain.CtxKeyTargetUserID, "user-1"))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		referer  string
+		source   string
+		campaign string
+	}{
+		{"referrer", "/alice", "https://l.instagram.com/?u=x", "instagram", ""},
+		{"utm parameters", "/alice?utm_source=ig&utm_medium=story&utm_campaign=spring", "", "instagram", "spring"},
+		{"direct", "/alice", "", domain.SourceDirect, ""},
+		{"qr scan", "/alice?source=qr", "https://instagram.com/", domain.SourceQR, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAnalyticsRepository()
+			m := handler.NewAnalyticsMiddleware(service.NewAnalyticsService(repo), sources, nil)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			m.TrackView(profile)(httptest.NewRecorder(), req)
+
+			assert.Eventually(t, func() bool { return len(repo.GetEvents()) == 1 }, time.Second, 10*time.Millisecond)
+			event := repo.GetEvents()[0]
+			assert.Equal(t, tt.source, event.Meta["source"])
+			assert.Equal(t, tt.campaign, event.Meta["utm_campaign"])
+		})
+	}
+}
@@ -2,7 +2,10 @@ package http
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/ports"
@@ -16,6 +19,7 @@ type PageHandler struct {
 	users        domain.UserRepository
 	sessions     ports.SessionManager
 	linkSvc      *service.LinkService
+	groupSvc     *service.LinkGroupService
 	analyticsSvc *service.AnalyticsService
 }
 
@@ -23,12 +27,14 @@ func NewPageHandler(
 	users domain.UserRepository,
 	sessions ports.SessionManager,
 	linkSvc *service.LinkService,
+	groupSvc *service.LinkGroupService,
 	analyticsSvc *service.AnalyticsService,
 ) *PageHandler {
 	return &PageHandler{
 		users:        users,
 		sessions:     sessions,
 		linkSvc:      linkSvc,
+		groupSvc:     groupSvc,
 		analyticsSvc: analyticsSvc,
 	}
 }
@@ -68,11 +74,15 @@ func (h *PageHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		tab = "profile"
 	}
 
-	// Fetch user's links
-	links, err := h.linkSvc.ListLinks(r.Context(), user.ID)
+	// Fetch user's links (including scheduled/expired ones)
+	links, err := h.linkSvc.ListAllLinks(r.Context(), user.ID)
 	if err != nil {
 		links = []*domain.Link{} // Empty on error
 	}
+	groups, err := h.groupSvc.ListGroups(r.Context(), user.ID)
+	if err != nil {
+		groups = []*domain.LinkGroup{}
+	}
 
 	// Fetch analytics summary
 	userIDStr := string(user.ID)
@@ -86,14 +96,103 @@ func (h *PageHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Trends and rankings are only loaded for the analytics tab
+	var analytics *dashboard.AnalyticsView
+	if tab == "analytics" {
+		analytics = h.analyticsView(r, user, links)
+	}
+
 	ctx := context.WithValue(r.Context(), domain.CtxKeyUser, user)
 	*r = *r.WithContext(ctx)
 
-	if err := RenderComponent(ctx, w, r, dashboard.Page(user, tab, links, summary), dashboard.Frame(user, tab, links, summary)); err != nil {
+	if err := RenderComponent(ctx, w, r, dashboard.Page(user, tab, links, groups, summary, analytics), dashboard.Frame(user, tab, links, groups, summary, analytics)); err != nil {
 		http.Error(w, "failed to render dashboard", http.StatusInternalServerError)
 	}
 }
 
+// topLinksLimit is the number of links ranked in the analytics tab.
+const topLinksLimit = 10
+
+// analyticsView loads the time series selected in the analytics range picker and the
+// user's most clicked links. The link filter is ignored unless it names one of the user's links.
+func (h *PageHandler) analyticsView(r *http.Request, user *domain.User, links []*domain.Link) *dashboard.AnalyticsView {
+	key, from, to, bucket := analyticsRange(r, time.Now())
+	view := &dashboard.AnalyticsView{Range: key, From: from, To: to, Bucket: bucket}
+
+	query := domain.TimeSeriesQuery{UserID: string(user.ID), From: from, To: to, Bucket: bucket}
+	if linkID := r.URL.Query().Get("link"); linkID != "" {
+		for _, link := range links {
+			if string(link.ID) == linkID {
+				view.LinkID = linkID
+				query.LinkID = &linkID
+				break
+			}
+		}
+	}
+
+	if series, err := h.analyticsSvc.GetTimeSeries(r.Context(), query); err != nil {
+		log.Printf("[ERR] Failed to load analytics time series: %v", err)
+		view.Error = trendError(err)
+	} else {
+		view.Series = series
+	}
+
+	top, err := h.analyticsSvc.GetTopLinks(r.Context(), string(user.ID), topLinksLimit)
+	if err != nil {
+		log.Printf("[ERR] Failed to load top links: %v", err)
+	}
+	view.TopLinks = top
+	return view
+}
+
+// LinkStats handles GET /dashboard/links/{id}/stats, the analytics of one link.
+// It takes the same range picker parameters as the analytics tab.
+func (h *PageHandler) LinkStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.currentUser(r)
+	if err != nil || user == nil {
+		TurboAwareRedirect(w, r, "/login")
+		return
+	}
+
+	link, err := h.linkSvc.GetLink(r.Context(), domain.LinkID(r.PathValue("id")))
+	if err != nil || link.UserID != user.ID {
+		respondError(w, r, "Link not found", http.StatusNotFound)
+		return
+	}
+
+	key, from, to, bucket := analyticsRange(r, time.Now())
+	linkID := string(link.ID)
+	view := &dashboard.AnalyticsView{Range: key, From: from, To: to, Bucket: bucket, LinkID: linkID}
+
+	stats, err := h.analyticsSvc.GetLinkStats(r.Context(), domain.TimeSeriesQuery{UserID: string(user.ID), LinkID: &linkID, From: from, To: to, Bucket: bucket})
+	if err != nil {
+		log.Printf("[ERR] Failed to load stats for link %s: %v", link.ID, err)
+		view.Error = trendError(err)
+	} else {
+		view.Series = stats.Series
+	}
+
+	ctx := context.WithValue(r.Context(), domain.CtxKeyUser, user)
+	*r = *r.WithContext(ctx)
+
+	if err := RenderComponent(ctx, w, r, dashboard.LinkStatsPage(user, link, stats, view), dashboard.LinkStatsFrame(user, link, stats, view)); err != nil {
+		http.Error(w, "failed to render link stats", http.StatusInternalServerError)
+	}
+}
+
+// trendError explains to the user why a time series could not be loaded.
+func trendError(err error) string {
+	if errors.Is(err, domain.ErrBadRequest) {
+		return "This range is too long for the chosen bucket size."
+	}
+	return "Failed to load the trend."
+}
+
 // Profile renders a public profile page for a given handle.
 func (h *PageHandler) Profile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -118,11 +217,16 @@ func (h *PageHandler) Profile(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		links = []*domain.Link{}
 	}
+	groups, err := h.groupSvc.ListGroups(r.Context(), user.ID)
+	if err != nil {
+		groups = []*domain.LinkGroup{}
+	}
 
 	ctx := context.WithValue(r.Context(), domain.CtxKeyTargetUserID, string(user.ID))
 	*r = *r.WithContext(ctx)
+	rememberLanding(w, r)
 
-	if err := RenderComponent(ctx, w, r, profile.Page(user, links), profile.Frame(user, links)); err != nil {
+	if err := RenderComponent(ctx, w, r, profile.Page(user, links, groups), profile.Frame(user, links, groups)); err != nil {
 		http.Error(w, "failed to render profile", http.StatusInternalServerError)
 	}
 }
@@ -145,11 +249,16 @@ func (h *PageHandler) ProfileByPath(w http.ResponseWriter, r *http.Request, hand
 	if err != nil {
 		links = []*domain.Link{}
 	}
+	groups, err := h.groupSvc.ListGroups(r.Context(), user.ID)
+	if err != nil {
+		groups = []*domain.LinkGroup{}
+	}
 
 	ctx := context.WithValue(r.Context(), domain.CtxKeyTargetUserID, string(user.ID))
 	*r = *r.WithContext(ctx)
+	rememberLanding(w, r)
 
-	if err := RenderComponent(ctx, w, r, profile.Page(user, links), profile.Frame(user, links)); err != nil {
+	if err := RenderComponent(ctx, w, r, profile.Page(user, links, groups), profile.Frame(user, links, groups)); err != nil {
 		http.Error(w, "failed to render profile", http.StatusInternalServerError)
 	}
 }
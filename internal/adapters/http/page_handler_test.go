@@ -1,9 +1,12 @@
 package http_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	handler "github.com/elchemista/driplnk/internal/adapters/http"
 	"github.com/elchemista/driplnk/internal/domain"
@@ -19,10 +22,12 @@ func TestPageHandler_Dashboard(t *testing.T) {
 	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
 	mockMetadata := mocks.NewMockMetadataFetcher()
 
-	linkService := service.NewLinkService(mockRepo, mockMetadata, nil)
+	linkService := service.NewLinkService(mockRepo, mockMetadata, nil, nil, nil, nil)
 	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
 
-	h := handler.NewPageHandler(mockUsers, mockSessions, linkService, analyticsService)
+	groupService := service.NewLinkGroupService(mocks.NewMockLinkGroupRepository(), mockRepo)
+
+	h := handler.NewPageHandler(mockUsers, mockSessions, linkService, groupService, analyticsService)
 
 	t.Run("Success", func(t *testing.T) {
 		user := &domain.User{ID: "user-1", Handle: "testuser", Theme: domain.Theme{}}
@@ -38,6 +43,31 @@ func TestPageHandler_Dashboard(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Dashboard")
 	})
 
+	t.Run("AnalyticsTrend", func(t *testing.T) {
+		mockSessions.SetCurrentUser("user-1")
+		userID := "user-1"
+		mockAnalyticsRepo.SaveEvent(context.Background(), &domain.AnalyticsEvent{EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", CreatedAt: time.Now()})
+
+		req := httptest.NewRequest(http.MethodGet, "/dashboard?tab=analytics&range=24h", nil)
+		w := httptest.NewRecorder()
+		h.Dashboard(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `<option value="24h" selected>`)
+		assert.Contains(t, body, "<polyline")
+		assert.Contains(t, body, ": 1 views, 0 clicks, 1 unique visitors")
+		assert.Contains(t, body, "Top links")
+
+		// Hourly buckets over a year are refused
+		req = httptest.NewRequest(http.MethodGet, "/dashboard?tab=analytics&range=custom&from=2025-01-01&to=2025-12-31&bucket=hour", nil)
+		w = httptest.NewRecorder()
+		h.Dashboard(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "This range is too long for the chosen bucket size.")
+	})
+
 	t.Run("RedirectLogin", func(t *testing.T) {
 		mockSessions.SetCurrentUser("") // Not logged in
 
@@ -51,6 +81,55 @@ func TestPageHandler_Dashboard(t *testing.T) {
 	})
 }
 
+func TestPageHandler_LinkStats(t *testing.T) {
+	mockUsers := mocks.NewMockUserRepository()
+	mockSessions := mocks.NewMockSessionManager()
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	groupService := service.NewLinkGroupService(mocks.NewMockLinkGroupRepository(), mockRepo)
+	h := handler.NewPageHandler(mockUsers, mockSessions, linkService, groupService, analyticsService)
+
+	mockUsers.AddUser(&domain.User{ID: "user-1", Handle: "alice"})
+	mockUsers.AddUser(&domain.User{ID: "user-2", Handle: "bob"})
+	mockRepo.AddLink(&domain.Link{ID: "l1", UserID: "user-1", Title: "Shop", URL: "https://shop.example.com"})
+
+	userID, linkID := "user-1", "l1"
+	mockAnalyticsRepo.SaveEvent(context.Background(), &domain.AnalyticsEvent{EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", CreatedAt: time.Now()})
+	mockAnalyticsRepo.SaveEvent(context.Background(), &domain.AnalyticsEvent{EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "v1", CreatedAt: time.Now(), Meta: map[string]string{"referrer": domain.ReferrerInternal}})
+
+	statsRequest := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/dashboard/links/"+id+"/stats?range=24h", nil)
+		req.SetPathValue("id", id)
+		return req
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSessions.SetCurrentUser("user-1")
+		w := httptest.NewRecorder()
+
+		h.LinkStats(w, statsRequest("l1"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "Shop")
+		assert.Contains(t, body, "100.0%")
+		assert.Contains(t, body, "Your profile")
+		assert.Contains(t, body, `action="/dashboard/links/l1/stats"`)
+	})
+
+	t.Run("OtherUsersLink", func(t *testing.T) {
+		mockSessions.SetCurrentUser("user-2")
+		w := httptest.NewRecorder()
+
+		h.LinkStats(w, statsRequest("l1"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestPageHandler_Profile(t *testing.T) {
 	mockUsers := mocks.NewMockUserRepository()
 	mockSessions := mocks.NewMockSessionManager()
@@ -58,10 +137,12 @@ func TestPageHandler_Profile(t *testing.T) {
 	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
 	mockMetadata := mocks.NewMockMetadataFetcher()
 
-	linkService := service.NewLinkService(mockRepo, mockMetadata, nil)
+	linkService := service.NewLinkService(mockRepo, mockMetadata, nil, nil, nil, nil)
 	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
 
-	h := handler.NewPageHandler(mockUsers, mockSessions, linkService, analyticsService)
+	groupService := service.NewLinkGroupService(mocks.NewMockLinkGroupRepository(), mockRepo)
+
+	h := handler.NewPageHandler(mockUsers, mockSessions, linkService, groupService, analyticsService)
 
 	t.Run("Success", func(t *testing.T) {
 		user := &domain.User{ID: "user-1", Handle: "testuser", Theme: domain.Theme{}}
@@ -76,6 +157,25 @@ func TestPageHandler_Profile(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("RendersSections", func(t *testing.T) {
+		group, err := groupService.CreateGroup(context.Background(), "user-1", "Merch", "Shirts and vinyl", true)
+		assert.NoError(t, err)
+		mockRepo.AddLink(&domain.Link{ID: "l1", UserID: "user-1", Title: "Loose link", URL: "https://a.example.com", Type: domain.LinkTypeStandard, IsActive: true})
+		mockRepo.AddLink(&domain.Link{ID: "l2", UserID: "user-1", Title: "Tour shirt", URL: "https://b.example.com", Type: domain.LinkTypeStandard, IsActive: true, Order: 1, GroupID: group.ID})
+
+		req := httptest.NewRequest(http.MethodGet, "/u/testuser", nil)
+		req.SetPathValue("handle", "testuser")
+		w := httptest.NewRecorder()
+
+		h.Profile(w, req)
+
+		body := w.Body.String()
+		assert.Contains(t, body, "Shirts and vinyl")
+		assert.NotContains(t, body, "group-"+string(group.ID)+`" class="collapse collapse-arrow" open`, "collapsed groups start folded")
+		assert.Less(t, strings.Index(body, "Loose link"), strings.Index(body, "Merch"), "ungrouped links come first")
+		assert.Greater(t, strings.Index(body, "Tour shirt"), strings.Index(body, "Merch"))
+	})
+
 	t.Run("NotFound", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/u/unknown", nil)
 		req.SetPathValue("handle", "unknown")
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/chai2010/webp" // uploaded avatars are stored as WebP
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/qr"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+// maxAvatarBytes bounds the avatar downloaded for the centre of a QR code.
+const maxAvatarBytes = 5 << 20
+
+// QRHandler serves QR codes for profiles and links. The encoded URLs carry
+// source=qr so scans are reported separately in analytics.
+type QRHandler struct {
+	users   domain.UserRepository
+	linkSvc *service.LinkService
+	client  *http.Client // fetches avatars drawn in the centre
+}
+
+func NewQRHandler(users domain.UserRepository, linkSvc *service.LinkService) *QRHandler {
+	return &QRHandler{
+		users:   users,
+		linkSvc: linkSvc,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// ProfileQR handles GET /{handle}/qr.png and /{handle}/qr.svg
+func (h *QRHandler) ProfileQR(w http.ResponseWriter, r *http.Request) {
+	user, err := h.users.GetByHandle(r.Context(), r.PathValue("handle"))
+	if err != nil || user == nil {
+		RespondNotFound(w, r, "Profile")
+		return
+	}
+
+	target := fmt.Sprintf("%s/%s?source=%s", requestBaseURL(r), user.Handle, domain.SourceQR)
+	h.render(w, r, user, target, user.Handle+"-qr")
+}
+
+// LinkQR handles GET /go/{id}/qr.svg and /go/{id}/qr.png
+// The code points to the link's short URL, using its vanity slug when it has one.
+func (h *QRHandler) LinkQR(w http.ResponseWriter, r *http.Request) {
+	link, err := h.linkSvc.ResolveLink(r.Context(), r.PathValue("id"))
+	if err != nil || link == nil {
+		RespondNotFound(w, r, "Link")
+		return
+	}
+	owner, err := h.users.GetByID(r.Context(), link.UserID)
+	if err != nil || owner == nil {
+		RespondNotFound(w, r, "Link")
+		return
+	}
+
+	ref := string(link.ID)
+	if link.Slug != "" {
+		ref = link.Slug
+	}
+	target := fmt.Sprintf("%s/go/%s?source=%s", requestBaseURL(r), ref, domain.SourceQR)
+	h.render(w, r, owner, target, ref+"-qr")
+}
+
+// render writes the QR code for target in the format given by the request path's extension.
+func (h *QRHandler) render(w http.ResponseWriter, r *http.Request, owner *domain.User, target, filename string) {
+	opts, err := h.qrOptions(r, owner)
+	if err != nil {
+		RespondBadRequest(w, r, err.Error())
+		return
+	}
+
+	var data []byte
+	var contentType string
+	switch ext := path.Ext(r.URL.Path); ext {
+	case ".png":
+		data, err = qr.PNG(target, opts)
+		contentType = "image/png"
+	case ".svg":
+		data, err = qr.SVG(target, opts)
+		contentType = "image/svg+xml"
+	default:
+		RespondNotFound(w, r, "QR code")
+		return
+	}
+	if err != nil {
+		RespondInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s%s"`, filename, path.Ext(r.URL.Path)))
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(data)
+}
+
+// qrOptions reads the size, level, fg, bg and avatar query parameters.
+// Colours default to the owner's theme primary colour on white.
+func (h *QRHandler) qrOptions(r *http.Request, owner *domain.User) (qr.Options, error) {
+	q := r.URL.Query()
+	var opts qr.Options
+
+	if size := q.Get("size"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < qr.MinSize || n > qr.MaxSize {
+			return opts, fmt.Errorf("size must be between %d and %d pixels", qr.MinSize, qr.MaxSize)
+		}
+		opts.Size = n
+	}
+	if level := q.Get("level"); level != "" {
+		l, err := qr.ParseLevel(level)
+		if err != nil {
+			return opts, fmt.Errorf("level must be L, M, Q or H")
+		}
+		opts.Level = l
+	}
+
+	if fg := q.Get("fg"); fg != "" {
+		c, err := qr.ParseHexColor(fg)
+		if err != nil {
+			return opts, fmt.Errorf("fg must be a hex colour")
+		}
+		opts.Foreground = c
+	} else if c, err := qr.ParseHexColor(owner.Theme.PrimaryColor); err == nil {
+		opts.Foreground = c
+	}
+	if bg := q.Get("bg"); bg != "" {
+		c, err := qr.ParseHexColor(bg)
+		if err != nil {
+			return opts, fmt.Errorf("bg must be a hex colour")
+		}
+		opts.Background = c
+	}
+
+	if avatar, _ := strconv.ParseBool(q.Get("avatar")); avatar && owner.AvatarURL != "" {
+		logo, err := h.fetchImage(r.Context(), owner.AvatarURL)
+		if err != nil {
+			// The code still scans without the avatar
+			log.Printf("[WARN] Failed to load avatar for QR code of %s: %v", owner.Handle, err)
+		} else {
+			opts.Logo = logo
+		}
+	}
+	return opts, nil
+}
+
+// fetchImage downloads and decodes a PNG, JPEG or WebP image.
+func (h *QRHandler) fetchImage(ctx context.Context, rawURL string) (image.Image, error) {
+	if !strings.HasPrefix(rawURL, "https://") && !strings.HasPrefix(rawURL, "http://") {
+		return nil, fmt.Errorf("unsupported image URL %q", rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	img, _, err := image.Decode(io.LimitReader(resp.Body, maxAvatarBytes))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	return img, nil
+}
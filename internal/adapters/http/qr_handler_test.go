@@ -0,0 +1,115 @@
+package http_test
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	handler "github.com/elchemista/driplnk/internal/adapters/http"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQRHandler(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	h := handler.NewQRHandler(mockUserRepo, linkService)
+
+	mockUserRepo.AddUser(&domain.User{ID: "user-1", Handle: "alice", Theme: domain.Theme{PrimaryColor: "#6366F1"}})
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", Slug: "shop", URL: "https://shop.com", IsActive: true})
+
+	t.Run("ProfilePNG", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/alice/qr.png?size=256", nil)
+		req.SetPathValue("handle", "alice")
+		w := httptest.NewRecorder()
+
+		h.ProfileQR(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 256, img.Bounds().Dx())
+		}
+	})
+
+	t.Run("ProfileSVGUsesThemeColour", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/alice/qr.svg", nil)
+		req.SetPathValue("handle", "alice")
+		w := httptest.NewRecorder()
+
+		h.ProfileQR(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `fill="#6366f1"`)
+	})
+
+	t.Run("LinkSVG", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/go/link-1/qr.svg?fg=000&bg=ffeedd&level=h", nil)
+		req.SetPathValue("id", "link-1")
+		w := httptest.NewRecorder()
+
+		h.LinkQR(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `fill="#ffeedd"`)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "shop-qr.svg")
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, query := range []string{"size=10", "level=Z", "fg=blue"} {
+			req := httptest.NewRequest(http.MethodGet, "/alice/qr.svg?"+query, nil)
+			req.SetPathValue("handle", "alice")
+			w := httptest.NewRecorder()
+
+			h.ProfileQR(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/nobody/qr.svg", nil)
+		req.SetPathValue("handle", "nobody")
+		w := httptest.NewRecorder()
+		h.ProfileQR(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/go/missing/qr.svg", nil)
+		req.SetPathValue("id", "missing")
+		w = httptest.NewRecorder()
+		h.LinkQR(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestLinkHandler_HandleRedirectTracksQRScans(t *testing.T) {
+	mockRepo := mocks.NewMockLinkRepository()
+	mockAnalyticsRepo := mocks.NewMockAnalyticsRepository()
+	mockUserRepo := mocks.NewMockUserRepository()
+
+	linkService := service.NewLinkService(mockRepo, nil, nil, nil, nil, nil)
+	analyticsService := service.NewAnalyticsService(mockAnalyticsRepo)
+	h := handler.NewLinkHandler(linkService, analyticsService, mocks.NewMockSessionManager(), mockUserRepo, nil, nil)
+
+	mockRepo.AddLink(&domain.Link{ID: "link-1", UserID: "user-1", URL: "https://shop.com", IsActive: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/go/link-1?source=qr", nil)
+	req.SetPathValue("id", "link-1")
+	w := httptest.NewRecorder()
+
+	h.HandleRedirect(w, req)
+
+	assert.Equal(t, "https://shop.com", w.Header().Get("Location"))
+	assert.Eventually(t, func() bool {
+		events := mockAnalyticsRepo.GetEvents()
+		return len(events) == 1 && events[0].Meta["source"] == domain.SourceQR
+	}, time.Second, 10*time.Millisecond)
+}
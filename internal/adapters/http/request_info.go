@@ -0,0 +1,214 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/google/uuid"
+)
+
+// containsMobile checks if a User-Agent string indicates a mobile device.
+func containsMobile(ua string) bool {
+	lower := strings.ToLower(ua)
+	return strings.Contains(lower, "mobile") || strings.Contains(lower, "android")
+}
+
+// requestDeviceType returns "mobile" or "desktop" for the request's User-Agent,
+// or an empty string if the request has none.
+func requestDeviceType(r *http.Request) string {
+	ua := r.Header.Get("User-Agent")
+	if ua == "" {
+		return ""
+	}
+	if containsMobile(ua) {
+		return "mobile"
+	}
+	return "desktop"
+}
+
+// requestMobileOS returns the phone OS of the request's User-Agent, or an empty
+// string for desktops and unknown devices. iPads asking for desktop sites pass as Macs.
+func requestMobileOS(r *http.Request) domain.MobileOS {
+	ua := strings.ToLower(r.Header.Get("User-Agent"))
+	switch {
+	case strings.Contains(ua, "android"):
+		return domain.MobileOSAndroid
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return domain.MobileOSIOS
+	}
+	return ""
+}
+
+// requestCountry returns the visitor country set by the edge proxy (Cloudflare or App Engine).
+func requestCountry(r *http.Request) string {
+	country := r.Header.Get("CF-IPCountry")
+	if country == "" {
+		country = r.Header.Get("X-AppEngine-Country")
+	}
+	return country
+}
+
+// addRequestMeta enriches analytics meta with the user agent, device type and country of r,
+// and marks visits that came from a QR code.
+func addRequestMeta(meta map[string]string, r *http.Request) {
+	if r.URL.Query().Get("source") == domain.SourceQR {
+		meta["source"] = domain.SourceQR
+	}
+	if ua := r.Header.Get("User-Agent"); ua != "" {
+		meta["user_agent"] = ua
+		meta["device_type"] = requestDeviceType(r)
+	}
+	if country := requestCountry(r); country != "" {
+		meta["country"] = country
+	}
+	if referrer := requestReferrer(r); referrer != "" {
+		meta["referrer"] = referrer
+	}
+}
+
+// requestReferrer returns the host of the page that sent the visitor, without "www.".
+// Our own pages are reported as domain.ReferrerInternal.
+func requestReferrer(r *http.Request) string {
+	ref, err := url.Parse(r.Referer())
+	if err != nil || ref.Host == "" {
+		return ""
+	}
+	if strings.EqualFold(ref.Host, r.Host) {
+		return domain.ReferrerInternal
+	}
+	return strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+}
+
+// landingCookie keeps the referrer of the visitor's last profile view, so clicks
+// made on the profile page are attributed to the site that sent the visitor
+// rather than to the profile itself.
+const landingCookie = "drip_landing"
+
+// maxLandingLen caps the landing referrer read back from the cookie, the longest host name.
+const maxLandingLen = 253
+
+// rememberLanding stores the referrer of a profile view for its later clicks.
+// Views referred by our own pages keep the earlier landing; direct visits are
+// stored as domain.SourceDirect.
+func rememberLanding(w http.ResponseWriter, r *http.Request) {
+	referrer := requestReferrer(r)
+	if referrer == domain.ReferrerInternal {
+		return
+	}
+	if referrer == "" {
+		referrer = domain.SourceDirect
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     landingCookie,
+		Value:    referrer,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// addLandingMeta replaces the internal referrer of a click with the referrer of
+// the profile view it was made from. Clicks on a directly visited profile get no referrer.
+func addLandingMeta(meta map[string]string, r *http.Request) {
+	if meta["referrer"] != domain.ReferrerInternal {
+		return
+	}
+	c, err := r.Cookie(landingCookie)
+	if err != nil || !validLanding(c.Value) {
+		return
+	}
+	if c.Value == domain.SourceDirect {
+		delete(meta, "referrer")
+		return
+	}
+	meta["referrer"] = c.Value
+}
+
+// validLanding reports whether a landing cookie value, which visitors control, is a host name.
+func validLanding(value string) bool {
+	if value == "" || len(value) > maxLandingLen {
+		return false
+	}
+	for _, c := range value {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '.' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestSignals describes the client of r for bot classification.
+func requestSignals(r *http.Request) domain.ClientSignals {
+	purpose := r.Header.Get("Sec-Purpose")
+	if purpose == "" {
+		purpose = r.Header.Get("Purpose")
+	}
+	return domain.ClientSignals{
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		Method:         r.Method,
+		Purpose:        purpose,
+	}
+}
+
+// isBotRequest reports whether bots classifies the client of r as automated.
+// Without a classifier every request counts.
+func isBotRequest(r *http.Request, bots domain.BotClassifier) bool {
+	return bots != nil && bots.IsBot(requestSignals(r))
+}
+
+// campaignParams are the incoming utm_* query parameters kept in analytics meta.
+var campaignParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_content", "utm_term"}
+
+// maxCampaignParamLen caps stored utm_* values, which visitors control.
+const maxCampaignParamLen = 100
+
+// addCampaignMeta copies the request's utm_* query parameters into analytics meta.
+func addCampaignMeta(meta map[string]string, r *http.Request) {
+	q := r.URL.Query()
+	for _, key := range campaignParams {
+		value := strings.TrimSpace(q.Get(key))
+		if len(value) > maxCampaignParamLen {
+			value = strings.ToValidUTF8(value[:maxCampaignParamLen], "")
+		}
+		if value != "" {
+			meta[key] = value
+		}
+	}
+}
+
+// requestBaseURL returns the scheme and host the request was made to, honouring the
+// X-Forwarded-Proto header set by a TLS-terminating proxy.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// visitorCookie identifies a browser across visits for unique visitor counts.
+const visitorCookie = "drip_visitor"
+
+// visitorIDFor returns the visitor cookie of the request, issuing a new one when
+// the browser has none yet.
+func visitorIDFor(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(visitorCookie); err == nil && c.Value != "" {
+		return c.Value
+	}
+	id := uuid.New().String()
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookie,
+		Value:    id,
+		Path:     "/",
+		Expires:  time.Now().AddDate(1, 0, 0),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
@@ -90,3 +90,38 @@ func (m *CookieSessionManager) ClearSession(ctx context.Context, w http.Response
 	http.SetCookie(w, cookie)
 	return nil
 }
+
+func (m *CookieSessionManager) SetSignedValue(w http.ResponseWriter, name, value string, ttl time.Duration) error {
+	encoded, err := m.sc.Encode(name, value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     m.path,
+		Domain:   m.domain,
+		Expires:  time.Now().Add(ttl),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func (m *CookieSessionManager) GetSignedValue(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	if err := m.sc.Decode(name, cookie.Value, &value); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return value, nil
+}
@@ -213,6 +213,139 @@ func (h *UserHandler) UpdateSEO(w http.ResponseWriter, r *http.Request) {
 	TurboAwareRedirect(w, r, "/dashboard?tab=profile")
 }
 
+// affiliateNetworkPattern matches network IDs as used in config/affiliates.json.
+var affiliateNetworkPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// maxAffiliateTagLength bounds a single affiliate tag.
+const maxAffiliateTagLength = 100
+
+// UpdateAffiliateTags handles POST /dashboard/affiliates
+// The form repeats affiliate_network and affiliate_tag once per network;
+// rows with an empty tag remove that network's tag.
+func (h *UserHandler) UpdateAffiliateTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	networks := r.Form["affiliate_network"]
+	tags := r.Form["affiliate_tag"]
+
+	affiliateTags := make(map[string]string)
+	for i, raw := range networks {
+		network := strings.ToLower(sanitizer.Normalize(raw))
+		tag := ""
+		if i < len(tags) {
+			tag = sanitizer.Normalize(tags[i])
+		}
+		if network == "" || tag == "" {
+			continue
+		}
+		if !affiliateNetworkPattern.MatchString(network) {
+			respondError(w, r, fmt.Sprintf("Invalid affiliate network %q", network), http.StatusBadRequest)
+			return
+		}
+		if len(tag) > maxAffiliateTagLength || strings.ContainsAny(tag, " &?#=") {
+			respondError(w, r, fmt.Sprintf("Invalid affiliate tag for %s", network), http.StatusBadRequest)
+			return
+		}
+		affiliateTags[network] = tag
+	}
+
+	user.AffiliateTags = affiliateTags
+	user.UpdatedAt = time.Now()
+
+	if err := h.users.Save(r.Context(), user); err != nil {
+		log.Printf("[ERR] Failed to update affiliate tags: %v", err)
+		respondError(w, r, "Failed to save affiliate tags", http.StatusInternalServerError)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		fmt.Fprintf(w, `<turbo-stream action="append" target="flash-messages">
+  <template>
+    <div class="alert alert-success shadow-lg mb-4" data-controller="flash">
+      <span>Affiliate tags updated!</span>
+    </div>
+  </template>
+</turbo-stream>`)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=profile")
+}
+
+// utmFromForm reads the utm_source, utm_medium and utm_campaign fields of a form.
+func utmFromForm(r *http.Request) domain.UTMParams {
+	return domain.UTMParams{
+		Source:   sanitizer.Normalize(r.FormValue("utm_source")),
+		Medium:   sanitizer.Normalize(r.FormValue("utm_medium")),
+		Campaign: sanitizer.Normalize(r.FormValue("utm_campaign")),
+	}
+}
+
+// UpdateUTM handles POST /dashboard/utm
+// It sets the UTM parameters added to every outbound click from the profile.
+// Empty fields are not added.
+func (h *UserHandler) UpdateUTM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := h.getCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	utm := utmFromForm(r)
+	if err := validator.ValidateStruct(utm); err != nil {
+		respondError(w, r, "Validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user.UTM = utm
+	user.UpdatedAt = time.Now()
+
+	if err := h.users.Save(r.Context(), user); err != nil {
+		log.Printf("[ERR] Failed to update UTM parameters: %v", err)
+		respondError(w, r, "Failed to save UTM parameters", http.StatusInternalServerError)
+		return
+	}
+
+	if IsTurboRequest(r) {
+		w.Header().Set("Content-Type", "text/vnd.turbo-stream.html; charset=utf-8")
+		fmt.Fprintf(w, `<turbo-stream action="append" target="flash-messages">
+  <template>
+    <div class="alert alert-success shadow-lg mb-4" data-controller="flash">
+      <span>UTM parameters updated!</span>
+    </div>
+  </template>
+</turbo-stream>`)
+		return
+	}
+
+	TurboAwareRedirect(w, r, "/dashboard?tab=profile")
+}
+
 // UpdateTheme handles POST /dashboard/theme
 func (h *UserHandler) UpdateTheme(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -99,3 +99,68 @@ func TestUserHandler_UpdateTheme(t *testing.T) {
 		assert.Equal(t, "grid", updated.Theme.LayoutStyle)
 	})
 }
+
+func TestUserHandler_UpdateAffiliateTags(t *testing.T) {
+	mockUsers := mocks.NewMockUserRepository()
+	mockSessions := mocks.NewMockSessionManager()
+
+	h := handler.NewUserHandler(mockUsers, mockSessions, nil)
+
+	mockUsers.AddUser(&domain.User{
+		ID:            "user-1",
+		Handle:        "original",
+		Email:         "test@example.com",
+		AffiliateTags: map[string]string{"ebay": "old"},
+	})
+	mockSessions.SetCurrentUser("user-1")
+
+	form := url.Values{}
+	form.Add("affiliate_network", "Amazon")
+	form.Add("affiliate_tag", "drip-20")
+	form.Add("affiliate_network", "ebay")
+	form.Add("affiliate_tag", "") // removes the tag
+
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/affiliates", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	h.UpdateAffiliateTags(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+
+	updated, _ := mockUsers.GetByID(context.Background(), "user-1")
+	assert.Equal(t, map[string]string{"amazon": "drip-20"}, updated.AffiliateTags)
+}
+
+func TestUserHandler_UpdateUTM(t *testing.T) {
+	mockUsers := mocks.NewMockUserRepository()
+	mockSessions := mocks.NewMockSessionManager()
+
+	h := handler.NewUserHandler(mockUsers, mockSessions, nil)
+
+	mockUsers.AddUser(&domain.User{ID: "user-1", Handle: "original", Email: "test@example.com"})
+	mockSessions.SetCurrentUser("user-1")
+
+	form := url.Values{}
+	form.Add("utm_source", " driplnk ")
+	form.Add("utm_medium", "social")
+	form.Add("utm_campaign", strings.Repeat("x", 101))
+
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/utm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	h.UpdateUTM(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	form.Set("utm_campaign", "")
+	req = httptest.NewRequest(http.MethodPost, "/dashboard/utm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+
+	h.UpdateUTM(w, req)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+
+	updated, _ := mockUsers.GetByID(context.Background(), "user-1")
+	assert.Equal(t, domain.UTMParams{Source: "driplnk", Medium: "social"}, updated.UTM)
+}
@@ -23,6 +23,10 @@ func (r *PebbleLinkRepository) GetByID(ctx context.Context, id domain.LinkID) (*
 	return r.repo.GetLinkByID(ctx, id)
 }
 
+func (r *PebbleLinkRepository) GetBySlug(ctx context.Context, slug string) (*domain.Link, error) {
+	return r.repo.GetLinkBySlug(ctx, slug)
+}
+
 func (r *PebbleLinkRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
 	return r.repo.ListLinksByUser(ctx, userID)
 }
@@ -31,8 +35,12 @@ func (r *PebbleLinkRepository) Delete(ctx context.Context, id domain.LinkID) err
 	return r.repo.DeleteLink(ctx, id)
 }
 
-func (r *PebbleLinkRepository) Reorder(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error {
-	return r.repo.Reorder(ctx, userID, linkIDs)
+func (r *PebbleLinkRepository) Reorder(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
+	return r.repo.Reorder(ctx, userID, placements)
+}
+
+func (r *PebbleLinkRepository) CountClick(ctx context.Context, id domain.LinkID, visitorID string) (bool, error) {
+	return r.repo.CountClick(ctx, id, visitorID)
 }
 
 // PostgresLinkRepository wraps PostgresRepository to implement domain.LinkRepository.
@@ -52,6 +60,10 @@ func (r *PostgresLinkRepository) GetByID(ctx context.Context, id domain.LinkID)
 	return r.repo.GetLinkByID(ctx, id)
 }
 
+func (r *PostgresLinkRepository) GetBySlug(ctx context.Context, slug string) (*domain.Link, error) {
+	return r.repo.GetLinkBySlug(ctx, slug)
+}
+
 func (r *PostgresLinkRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
 	return r.repo.ListLinksByUser(ctx, userID)
 }
@@ -60,6 +72,118 @@ func (r *PostgresLinkRepository) Delete(ctx context.Context, id domain.LinkID) e
 	return r.repo.Delete(ctx, id)
 }
 
-func (r *PostgresLinkRepository) Reorder(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error {
-	return r.repo.Reorder(ctx, userID, linkIDs)
+func (r *PostgresLinkRepository) Reorder(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
+	return r.repo.Reorder(ctx, userID, placements)
+}
+
+func (r *PostgresLinkRepository) CountClick(ctx context.Context, id domain.LinkID, visitorID string) (bool, error) {
+	return r.repo.CountClick(ctx, id, visitorID)
+}
+
+// PebbleLinkGroupRepository wraps PebbleRepository to implement domain.LinkGroupRepository.
+type PebbleLinkGroupRepository struct {
+	repo *PebbleRepository
+}
+
+func NewPebbleLinkGroupRepository(repo *PebbleRepository) *PebbleLinkGroupRepository {
+	return &PebbleLinkGroupRepository{repo: repo}
+}
+
+func (r *PebbleLinkGroupRepository) Save(ctx context.Context, group *domain.LinkGroup) error {
+	return r.repo.SaveGroup(ctx, group)
+}
+
+func (r *PebbleLinkGroupRepository) GetByID(ctx context.Context, id domain.LinkGroupID) (*domain.LinkGroup, error) {
+	return r.repo.GetGroupByID(ctx, id)
+}
+
+func (r *PebbleLinkGroupRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	return r.repo.ListGroupsByUser(ctx, userID)
+}
+
+func (r *PebbleLinkGroupRepository) Delete(ctx context.Context, id domain.LinkGroupID) error {
+	return r.repo.DeleteGroup(ctx, id)
+}
+
+func (r *PebbleLinkGroupRepository) Reorder(ctx context.Context, userID domain.UserID, groupIDs []domain.LinkGroupID) error {
+	return r.repo.ReorderGroups(ctx, userID, groupIDs)
+}
+
+// PostgresLinkGroupRepository wraps PostgresRepository to implement domain.LinkGroupRepository.
+type PostgresLinkGroupRepository struct {
+	repo *PostgresRepository
+}
+
+func NewPostgresLinkGroupRepository(repo *PostgresRepository) *PostgresLinkGroupRepository {
+	return &PostgresLinkGroupRepository{repo: repo}
+}
+
+func (r *PostgresLinkGroupRepository) Save(ctx context.Context, group *domain.LinkGroup) error {
+	return r.repo.SaveGroup(ctx, group)
+}
+
+func (r *PostgresLinkGroupRepository) GetByID(ctx context.Context, id domain.LinkGroupID) (*domain.LinkGroup, error) {
+	return r.repo.GetGroupByID(ctx, id)
+}
+
+func (r *PostgresLinkGroupRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	return r.repo.ListGroupsByUser(ctx, userID)
+}
+
+func (r *PostgresLinkGroupRepository) Delete(ctx context.Context, id domain.LinkGroupID) error {
+	return r.repo.DeleteGroup(ctx, id)
+}
+
+func (r *PostgresLinkGroupRepository) Reorder(ctx context.Context, userID domain.UserID, groupIDs []domain.LinkGroupID) error {
+	return r.repo.ReorderGroups(ctx, userID, groupIDs)
+}
+
+// PebbleLinkRevisionRepository wraps PebbleRepository to implement domain.LinkRevisionRepository.
+type PebbleLinkRevisionRepository struct {
+	repo *PebbleRepository
+}
+
+func NewPebbleLinkRevisionRepository(repo *PebbleRepository) *PebbleLinkRevisionRepository {
+	return &PebbleLinkRevisionRepository{repo: repo}
+}
+
+func (r *PebbleLinkRevisionRepository) Save(ctx context.Context, revision *domain.LinkRevision) error {
+	return r.repo.SaveRevision(ctx, revision)
+}
+
+func (r *PebbleLinkRevisionRepository) GetByID(ctx context.Context, id domain.LinkRevisionID) (*domain.LinkRevision, error) {
+	return r.repo.GetRevisionByID(ctx, id)
+}
+
+func (r *PebbleLinkRevisionRepository) ListByLink(ctx context.Context, linkID domain.LinkID) ([]*domain.LinkRevision, error) {
+	return r.repo.ListRevisionsByLink(ctx, linkID)
+}
+
+func (r *PebbleLinkRevisionRepository) ListByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	return r.repo.ListRevisionsByUser(ctx, userID, limit)
+}
+
+// PostgresLinkRevisionRepository wraps PostgresRepository to implement domain.LinkRevisionRepository.
+type PostgresLinkRevisionRepository struct {
+	repo *PostgresRepository
+}
+
+func NewPostgresLinkRevisionRepository(repo *PostgresRepository) *PostgresLinkRevisionRepository {
+	return &PostgresLinkRevisionRepository{repo: repo}
+}
+
+func (r *PostgresLinkRevisionRepository) Save(ctx context.Context, revision *domain.LinkRevision) error {
+	return r.repo.SaveRevision(ctx, revision)
+}
+
+func (r *PostgresLinkRevisionRepository) GetByID(ctx context.Context, id domain.LinkRevisionID) (*domain.LinkRevision, error) {
+	return r.repo.GetRevisionByID(ctx, id)
+}
+
+func (r *PostgresLinkRevisionRepository) ListByLink(ctx context.Context, linkID domain.LinkID) ([]*domain.LinkRevision, error) {
+	return r.repo.ListRevisionsByLink(ctx, linkID)
+}
+
+func (r *PostgresLinkRevisionRepository) ListByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	return r.repo.ListRevisionsByUser(ctx, userID, limit)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/elchemista/driplnk/internal/domain"
@@ -17,6 +18,9 @@ func (r *PebbleRepository) SaveEvent(ctx context.Context, event *domain.Analytic
 		return err
 	}
 
+	r.visitorMu.Lock()
+	defer r.visitorMu.Unlock()
+
 	batch := r.db.NewBatch()
 	defer batch.Close()
 
@@ -43,14 +47,77 @@ func (r *PebbleRepository) SaveEvent(ctx context.Context, event *domain.Analytic
 		}
 	}
 
+	// 4. Unique visitor sketches: analytics:hll:...
+	if err := r.addVisitor(batch, event); err != nil {
+		return err
+	}
+
 	return batch.Commit(pebble.Sync)
 }
 
+// detachLinkEvents clears the link of every event recorded for a deleted link
+// and drops its link index and visitor sketches, so the events still count towards
+// the owner's totals.
+func (r *PebbleRepository) detachLinkEvents(batch *pebble.Batch, linkID domain.LinkID) error {
+	if err := dropLinkVisitorSketches(batch, linkID); err != nil {
+		return err
+	}
+
+	// Key: analytics:link:<link_id>:<event_id>
+	prefix := []byte(fmt.Sprintf("analytics:link:%s:", linkID))
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	defer iter.Close()
+
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		keyParts := strings.Split(string(iter.Key()), ":")
+		if len(keyParts) < 4 {
+			continue
+		}
+		if err := batch.Delete(append([]byte(nil), iter.Key()...), pebble.Sync); err != nil {
+			return err
+		}
+
+		eventKey := []byte(fmt.Sprintf("analytics:event:%s", keyParts[3]))
+		val, closer, err := r.db.Get(eventKey)
+		if err != nil {
+			continue
+		}
+		var event domain.AnalyticsEvent
+		err = json.Unmarshal(val, &event)
+		closer.Close()
+		if err != nil {
+			continue
+		}
+
+		event.LinkID = nil
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(eventKey, data, pebble.Sync); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetSummary returns aggregated stats for a user (and optionally a specific link).
 func (r *PebbleRepository) GetSummary(ctx context.Context, userID string, linkID *string) (*domain.AnalyticsSummary, error) {
 	summary := &domain.AnalyticsSummary{
-		ByCountry: make(map[string]int64),
-		ByDevice:  make(map[string]int64),
+		ByCountry:  make(map[string]int64),
+		ByDevice:   make(map[string]int64),
+		ByReferrer: make(map[string]int64),
+		BySource:   make(map[string]int64),
+		ByCampaign: make(map[string]int64),
+	}
+
+	// Countries, devices, referrers, sources and campaigns of profile views, or of
+	// clicks when looking at a single link
+	referrerType := domain.EventTypeView
+	if linkID != nil {
+		referrerType = domain.EventTypeClick
 	}
 
 	var prefix []byte
@@ -101,22 +168,231 @@ func (r *PebbleRepository) GetSummary(ctx context.Context, userID string, linkID
 			continue
 		}
 
+		// Bots are only counted apart
+		if event.IsBot {
+			switch event.EventType {
+			case domain.EventTypeView:
+				summary.BotViews++
+			case domain.EventTypeClick:
+				summary.BotClicks++
+			}
+			continue
+		}
+
 		// Aggregate
+		qr := event.Meta["source"] == domain.SourceQR
 		switch event.EventType {
 		case domain.EventTypeView:
 			summary.TotalViews++
+			if qr {
+				summary.QRViews++
+			}
 		case domain.EventTypeClick:
 			summary.TotalClicks++
+			if qr {
+				summary.QRClicks++
+			}
 		}
 
-		if event.Country != "" {
-			summary.ByCountry[event.Country]++
+		if event.EventType == referrerType {
+			if event.Country != "" {
+				summary.ByCountry[event.Country]++
+			}
+			if deviceType, ok := event.Meta["device_type"]; ok {
+				summary.ByDevice[deviceType]++
+			}
+			if referrer := event.Meta["referrer"]; referrer != "" {
+				summary.ByReferrer[referrer]++
+			}
+			if source := event.Meta["source"]; source != "" {
+				summary.BySource[source]++
+			}
+			if campaign := event.Meta["utm_campaign"]; campaign != "" {
+				summary.ByCampaign[campaign]++
+			}
 		}
+	}
 
-		if deviceType, ok := event.Meta["device_type"]; ok {
-			summary.ByDevice[deviceType]++
-		}
+	if linkID != nil {
+		summary.UniqueVisitors = r.countVisitors(visitorSketchKey("link", *linkID, "all"))
+	} else {
+		summary.UniqueVisitors = r.countVisitors(visitorSketchKey("user", userID, "all"))
 	}
 
 	return summary, nil
 }
+
+// GetVariantStats returns clicks and CTR per A/B variant of a link.
+func (r *PebbleRepository) GetVariantStats(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error) {
+	viewsByVisitor := make(map[string]int64)
+	clicksByVariant := make(map[string]int64)
+
+	// Key: analytics:user:<user_id>:<event_id>
+	prefix := []byte(fmt.Sprintf("analytics:user:%s:", userID))
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	defer iter.Close()
+
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		keyParts := strings.Split(string(iter.Key()), ":")
+		if len(keyParts) < 4 {
+			continue
+		}
+
+		eventKey := []byte(fmt.Sprintf("analytics:event:%s", keyParts[3]))
+		val, closer, err := r.db.Get(eventKey)
+		if err != nil {
+			continue
+		}
+
+		var event domain.AnalyticsEvent
+		if err := json.Unmarshal(val, &event); err != nil {
+			closer.Close()
+			continue
+		}
+		closer.Close()
+
+		if event.IsBot {
+			continue
+		}
+
+		switch event.EventType {
+		case domain.EventTypeView:
+			viewsByVisitor[event.VisitorID]++
+		case domain.EventTypeClick:
+			if event.LinkID != nil && *event.LinkID == string(link.ID) && event.Meta["variant"] != "" {
+				clicksByVariant[event.Meta["variant"]]++
+			}
+		}
+	}
+
+	return domain.BuildVariantStats(link, viewsByVisitor, clicksByVariant), nil
+}
+
+// GetTimeSeries returns views, clicks and unique visitors per bucket of the query's range.
+// Events are not indexed by time, so the user (or link) index is scanned and filtered
+// for views and clicks; unique visitors come from the visitor sketches.
+func (r *PebbleRepository) GetTimeSeries(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error) {
+	series := domain.NewTimeSeries(query)
+
+	var prefix []byte
+	scope, scopeID := "user", query.UserID
+	if query.LinkID != nil {
+		// Key: analytics:link:<link_id>:<event_id>
+		prefix = []byte(fmt.Sprintf("analytics:link:%s:", *query.LinkID))
+		scope, scopeID = "link", *query.LinkID
+	} else {
+		// Key: analytics:user:<user_id>:<event_id>
+		prefix = []byte(fmt.Sprintf("analytics:user:%s:", query.UserID))
+	}
+
+	// Visitors of the buckets the range only partly covers, which whole-bucket
+	// sketches would overcount
+	edges := make(map[time.Time]map[string]struct{})
+
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	defer iter.Close()
+
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		keyParts := strings.Split(string(iter.Key()), ":")
+		if len(keyParts) < 4 {
+			continue
+		}
+
+		eventKey := []byte(fmt.Sprintf("analytics:event:%s", keyParts[3]))
+		val, closer, err := r.db.Get(eventKey)
+		if err != nil {
+			continue
+		}
+
+		var event domain.AnalyticsEvent
+		if err := json.Unmarshal(val, &event); err != nil {
+			closer.Close()
+			continue
+		}
+		closer.Close()
+
+		// Filter by UserID if we are scanning via Link Index (to ensure ownership/correctness)
+		if query.LinkID != nil && event.UserID != nil && *event.UserID != query.UserID {
+			continue
+		}
+
+		if event.IsBot || event.CreatedAt.Before(query.From) || !event.CreatedAt.Before(query.To) {
+			continue
+		}
+		point := series.Point(event.CreatedAt)
+		if point == nil {
+			continue
+		}
+		switch event.EventType {
+		case domain.EventTypeView:
+			point.Views++
+		case domain.EventTypeClick:
+			point.Clicks++
+		default:
+			continue
+		}
+		if series.Partial(point.Start) {
+			if edges[point.Start] == nil {
+				edges[point.Start] = make(map[string]struct{})
+			}
+			edges[point.Start][event.VisitorID] = struct{}{}
+		}
+	}
+
+	r.fillUniqueVisitors(series, scope, scopeID, edges)
+	return series, nil
+}
+
+// GetTopLinks returns the user's most clicked links, most clicks first.
+func (r *PebbleRepository) GetTopLinks(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error) {
+	clicks := make(map[string]int64)
+
+	// Key: analytics:user:<user_id>:<event_id>
+	prefix := []byte(fmt.Sprintf("analytics:user:%s:", userID))
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	defer iter.Close()
+
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		keyParts := strings.Split(string(iter.Key()), ":")
+		if len(keyParts) < 4 {
+			continue
+		}
+
+		eventKey := []byte(fmt.Sprintf("analytics:event:%s", keyParts[3]))
+		val, closer, err := r.db.Get(eventKey)
+		if err != nil {
+			continue
+		}
+
+		var event domain.AnalyticsEvent
+		if err := json.Unmarshal(val, &event); err != nil {
+			closer.Close()
+			continue
+		}
+		closer.Close()
+
+		if event.EventType != domain.EventTypeClick || event.LinkID == nil || event.IsBot {
+			continue
+		}
+		clicks[*event.LinkID]++
+	}
+
+	top := make([]domain.LinkClicks, 0, len(clicks))
+	for linkID, count := range clicks {
+		top = append(top, domain.LinkClicks{LinkID: linkID, Clicks: count})
+	}
+	domain.SortLinkClicks(top)
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	for i := range top {
+		top[i].UniqueVisitors = r.countVisitors(visitorSketchKey("link", top[i].LinkID, "all"))
+	}
+	return top, nil
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/elchemista/driplnk/internal/domain"
@@ -18,6 +21,13 @@ var (
 
 type PebbleRepository struct {
 	db *pebble.DB
+
+	// linkMu serializes link writes, which read the previous record to keep indexes consistent.
+	linkMu sync.Mutex
+	// groupMu does the same for link group writes.
+	groupMu sync.Mutex
+	// visitorMu serializes updates of the unique visitor sketches.
+	visitorMu sync.Mutex
 }
 
 func NewPebbleRepository(cfg *PebbleConfig) (*PebbleRepository, error) {
@@ -29,8 +39,13 @@ func NewPebbleRepository(cfg *PebbleConfig) (*PebbleRepository, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open pebble db: %w", err)
 	}
+	repo := &PebbleRepository{db: db}
+	if err := repo.buildVisitorSketches(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to build visitor sketches: %w", err)
+	}
 	log.Println("[INFO] PebbleDB Adapter initialized successfully")
-	return &PebbleRepository{db: db}, nil
+	return repo, nil
 }
 
 func (r *PebbleRepository) Close() error {
@@ -151,14 +166,66 @@ func (r *PebbleRepository) ListAll(ctx context.Context) ([]*domain.User, error)
 
 // --- Link Repository ---
 
-func (r *PebbleRepository) SaveLink(ctx context.Context, link *domain.Link) error {
+// linkIndexKey builds the order-aware user links index key.
+// Key: user:links:<userID>:<order>:<linkID>
+// The order is zero-padded so that lexicographic key order matches link order.
+func linkIndexKey(userID domain.UserID, order int, linkID domain.LinkID) []byte {
+	return []byte(fmt.Sprintf("user:links:%s:%010d:%s", userID, order, linkID))
+}
+
+// legacyLinkIndexKey is the unordered index key written by earlier versions.
+// Key: user:links:<userID>:<linkID>
+func legacyLinkIndexKey(userID domain.UserID, linkID domain.LinkID) []byte {
+	return []byte(fmt.Sprintf("user:links:%s:%s", userID, linkID))
+}
+
+// linkSlugKey builds the slug index key.
+// Key: link:slug:<slug> -> linkID
+func linkSlugKey(slug string) []byte {
+	return []byte(fmt.Sprintf("link:slug:%s", slug))
+}
+
+// linkPasswordKey stores the password hash of a protected link.
+// It is kept outside the JSON record so the hash never leaks through serialization.
+// Key: link:password:<linkID> -> bcrypt hash
+func linkPasswordKey(linkID domain.LinkID) []byte {
+	return []byte(fmt.Sprintf("link:password:%s", linkID))
+}
+
+// linkVisitorKey marks a visitor as counted towards a link's unique visitors.
+// Key: link:visitor:<linkID>:<visitorID>
+func linkVisitorKey(linkID domain.LinkID, visitorID string) []byte {
+	return []byte(fmt.Sprintf("link:visitor:%s:%s", linkID, visitorID))
+}
+
+// setLinkInBatch writes the link record and its index key, removing the index
+// entries of the previous version of the link (if any).
+func setLinkInBatch(batch *pebble.Batch, link *domain.Link, previous *domain.Link) error {
 	data, err := json.Marshal(link)
 	if err != nil {
 		return err
 	}
 
-	batch := r.db.NewBatch()
-	defer batch.Close()
+	if previous != nil {
+		if err := batch.Delete(linkIndexKey(previous.UserID, previous.Order, previous.ID), pebble.Sync); err != nil {
+			return err
+		}
+		if err := batch.Delete(legacyLinkIndexKey(previous.UserID, previous.ID), pebble.Sync); err != nil {
+			return err
+		}
+		if previous.Slug != "" && previous.Slug != link.Slug {
+			if err := batch.Delete(linkSlugKey(previous.Slug), pebble.Sync); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Slug index
+	if link.Slug != "" {
+		if err := batch.Set(linkSlugKey(link.Slug), []byte(link.ID), pebble.Sync); err != nil {
+			return err
+		}
+	}
 
 	// Main record
 	key := []byte(fmt.Sprintf("link:%s", link.ID))
@@ -166,12 +233,48 @@ func (r *PebbleRepository) SaveLink(ctx context.Context, link *domain.Link) erro
 		return err
 	}
 
-	// User links index (for sorting/listing)
-	// Key: user:links:<userID>:<order>:<linkID>
-	// Note: Reordering might require deleting old index keys. For MV implementation, strict ordering management might be tricky with KV.
-	// Simpler index: user:links:<userID>:<linkID> -> empty
-	indexKey := []byte(fmt.Sprintf("user:links:%s:%s", link.UserID, link.ID))
-	if err := batch.Set(indexKey, []byte{}, pebble.Sync); err != nil {
+	// Password hash (never part of the JSON record)
+	if link.PasswordHash != "" {
+		if err := batch.Set(linkPasswordKey(link.ID), []byte(link.PasswordHash), pebble.Sync); err != nil {
+			return err
+		}
+	} else if err := batch.Delete(linkPasswordKey(link.ID), pebble.Sync); err != nil {
+		return err
+	}
+
+	// User links index (for sorted listing)
+	return batch.Set(linkIndexKey(link.UserID, link.Order, link.ID), []byte{}, pebble.Sync)
+}
+
+func (r *PebbleRepository) SaveLink(ctx context.Context, link *domain.Link) error {
+	r.linkMu.Lock()
+	defer r.linkMu.Unlock()
+
+	previous, err := r.GetLinkByID(ctx, link.ID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	// Counters only move through CountClick
+	if previous != nil {
+		link.ClickCount = previous.ClickCount
+		link.UniqueVisitors = previous.UniqueVisitors
+	}
+
+	// Slug collision detection
+	if link.Slug != "" {
+		owner, err := r.GetLinkBySlug(ctx, link.Slug)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if owner != nil && owner.ID != link.ID {
+			return fmt.Errorf("slug %q is already in use: %w", link.Slug, domain.ErrConflict)
+		}
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	if err := setLinkInBatch(batch, link, previous); err != nil {
 		return err
 	}
 
@@ -193,9 +296,34 @@ func (r *PebbleRepository) GetLinkByID(ctx context.Context, id domain.LinkID) (*
 	if err := json.Unmarshal(val, &link); err != nil {
 		return nil, err
 	}
+
+	hash, hashCloser, err := r.db.Get(linkPasswordKey(id))
+	if err == nil {
+		link.PasswordHash = string(hash)
+		hashCloser.Close()
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return nil, err
+	}
+
 	return &link, nil
 }
 
+// GetLinkBySlug resolves a link through the slug index.
+func (r *PebbleRepository) GetLinkBySlug(ctx context.Context, slug string) (*domain.Link, error) {
+	val, closer, err := r.db.Get(linkSlugKey(slug))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	linkID := domain.LinkID(val)
+	closer.Close()
+
+	return r.GetLinkByID(ctx, linkID)
+}
+
+// ListLinksByUser returns the user's links sorted by Order.
 func (r *PebbleRepository) ListLinksByUser(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
 	prefix := []byte(fmt.Sprintf("user:links:%s:", userID))
 	iter, _ := r.db.NewIter(&pebble.IterOptions{
@@ -204,31 +332,38 @@ func (r *PebbleRepository) ListLinksByUser(ctx context.Context, userID domain.Us
 	defer iter.Close()
 
 	var links []*domain.Link
+	seen := make(map[domain.LinkID]bool)
 
-	for iter.SeekGE(prefix); iter.Valid() && string(iter.Key())[0:len(prefix)] == string(prefix); iter.Next() {
-		// Extract LinkID from key or load it
-		// The key is user:links:<userID>:<linkID>
-		// We can parse the LinkID from the key
-		var linkIDStr string
-		fmt.Sscanf(string(iter.Key()), "user:links:%s:%s", new(string), &linkIDStr)
-
-		// This scanf might be flaky if IDs have colons, better to split string
-		// Manual split:
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		// Ordered keys are user:links:<userID>:<order>:<linkID>,
+		// legacy keys are user:links:<userID>:<linkID>.
 		parts := splitKey(string(iter.Key()))
 		if len(parts) < 4 {
 			continue
 		}
-		linkID := domain.LinkID(parts[3])
+		linkID := domain.LinkID(parts[len(parts)-1])
+		if seen[linkID] {
+			continue
+		}
 
 		link, err := r.GetLinkByID(ctx, linkID)
 		if err == nil {
+			seen[linkID] = true
 			links = append(links, link)
 		}
 	}
+
+	// Legacy keys are not sorted by order, so sort the final result.
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].Order < links[j].Order
+	})
 	return links, nil
 }
 
 func (r *PebbleRepository) DeleteLink(ctx context.Context, id domain.LinkID) error {
+	r.linkMu.Lock()
+	defer r.linkMu.Unlock()
+
 	link, err := r.GetLinkByID(ctx, id)
 	if err != nil {
 		return err // Or return nil if already gone
@@ -241,13 +376,78 @@ func (r *PebbleRepository) DeleteLink(ctx context.Context, id domain.LinkID) err
 	key := []byte(fmt.Sprintf("link:%s", id))
 	batch.Delete(key, pebble.Sync)
 
-	// Delete Index
-	indexKey := []byte(fmt.Sprintf("user:links:%s:%s", link.UserID, id))
-	batch.Delete(indexKey, pebble.Sync)
+	// Delete Index (ordered and legacy)
+	batch.Delete(linkIndexKey(link.UserID, link.Order, id), pebble.Sync)
+	batch.Delete(legacyLinkIndexKey(link.UserID, id), pebble.Sync)
+	if link.Slug != "" {
+		batch.Delete(linkSlugKey(link.Slug), pebble.Sync)
+	}
+	batch.Delete(linkPasswordKey(id), pebble.Sync)
+	visitors := linkVisitorKey(id, "")
+	batch.DeleteRange(visitors, append(visitors[:len(visitors):len(visitors)], 0xff), pebble.Sync)
+
+	// Keep the events in the owner's totals, like the Postgres implementation
+	if err := r.detachLinkEvents(batch, id); err != nil {
+		return err
+	}
 
 	return batch.Commit(pebble.Sync)
 }
 
+// CountClick adds a click under the link write lock, so concurrent clicks
+// cannot go over the link's limits or be lost by a concurrent SaveLink.
+func (r *PebbleRepository) CountClick(ctx context.Context, id domain.LinkID, visitorID string) (bool, error) {
+	r.linkMu.Lock()
+	defer r.linkMu.Unlock()
+
+	link, err := r.GetLinkByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	newVisitor := true
+	if visitorID != "" {
+		_, closer, err := r.db.Get(linkVisitorKey(id, visitorID))
+		if err == nil {
+			closer.Close()
+			newVisitor = false
+		} else if !errors.Is(err, pebble.ErrNotFound) {
+			return false, err
+		}
+	}
+	if link.MaxClicks > 0 && link.ClickCount >= link.MaxClicks {
+		return false, nil
+	}
+	if newVisitor && link.MaxVisitors > 0 && link.UniqueVisitors >= link.MaxVisitors {
+		return false, nil
+	}
+
+	link.ClickCount++
+	if newVisitor {
+		link.UniqueVisitors++
+	}
+	data, err := json.Marshal(link)
+	if err != nil {
+		return false, err
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set([]byte(fmt.Sprintf("link:%s", id)), data, pebble.Sync); err != nil {
+		return false, err
+	}
+	if newVisitor && visitorID != "" {
+		if err := batch.Set(linkVisitorKey(id, visitorID), []byte{}, pebble.Sync); err != nil {
+			return false, err
+		}
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Helper to split keys
 func splitKey(key string) []string {
 	// Simple implementation
@@ -263,7 +463,184 @@ func splitKey(key string) []string {
 	return parts
 }
 
-// Reorder is complex in KV, skipping for initial scaffolding
-func (r *PebbleRepository) Reorder(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error {
-	return nil
+// Reorder assigns each link the position of its placement, moves it into the
+// placement's group and rewrites the ordered index in a single atomic batch.
+// Like the Postgres implementation, IDs that do not belong to the user are
+// skipped and unlisted links keep their order and group. A link listed twice
+// ends up at its last placement.
+func (r *PebbleRepository) Reorder(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
+	r.linkMu.Lock()
+	defer r.linkMu.Unlock()
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	// Links already placed in this batch, whose index key is not in the DB yet
+	placed := make(map[domain.LinkID]*domain.Link)
+
+	for i, placement := range placements {
+		link, ok := placed[placement.LinkID]
+		if !ok {
+			var err error
+			link, err = r.GetLinkByID(ctx, placement.LinkID)
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					continue
+				}
+				return err
+			}
+			if link.UserID != userID {
+				continue
+			}
+			placed[placement.LinkID] = link
+		}
+
+		previous := *link
+		link.Order = i
+		link.GroupID = placement.GroupID
+		if err := setLinkInBatch(batch, link, &previous); err != nil {
+			return err
+		}
+	}
+
+	return batch.Commit(pebble.Sync)
+}
+
+// --- Link Group Repository ---
+
+// groupKey builds the main record key of a link group.
+// Key: group:<groupID>
+func groupKey(id domain.LinkGroupID) []byte {
+	return []byte(fmt.Sprintf("group:%s", id))
+}
+
+// groupIndexKey builds the order-aware user groups index key.
+// Key: user:groups:<userID>:<order>:<groupID>
+func groupIndexKey(userID domain.UserID, order int, id domain.LinkGroupID) []byte {
+	return []byte(fmt.Sprintf("user:groups:%s:%010d:%s", userID, order, id))
+}
+
+// setGroupInBatch writes the group record and its index key, removing the
+// index entry of the previous version of the group (if any).
+func setGroupInBatch(batch *pebble.Batch, group *domain.LinkGroup, previous *domain.LinkGroup) error {
+	data, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		if err := batch.Delete(groupIndexKey(previous.UserID, previous.Order, previous.ID), pebble.Sync); err != nil {
+			return err
+		}
+	}
+	if err := batch.Set(groupKey(group.ID), data, pebble.Sync); err != nil {
+		return err
+	}
+	return batch.Set(groupIndexKey(group.UserID, group.Order, group.ID), []byte{}, pebble.Sync)
+}
+
+func (r *PebbleRepository) SaveGroup(ctx context.Context, group *domain.LinkGroup) error {
+	r.groupMu.Lock()
+	defer r.groupMu.Unlock()
+
+	previous, err := r.GetGroupByID(ctx, group.ID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	if err := setGroupInBatch(batch, group, previous); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (r *PebbleRepository) GetGroupByID(ctx context.Context, id domain.LinkGroupID) (*domain.LinkGroup, error) {
+	val, closer, err := r.db.Get(groupKey(id))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	var group domain.LinkGroup
+	if err := json.Unmarshal(val, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// ListGroupsByUser returns the user's groups sorted by Order.
+func (r *PebbleRepository) ListGroupsByUser(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	prefix := []byte(fmt.Sprintf("user:groups:%s:", userID))
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	defer iter.Close()
+
+	var groups []*domain.LinkGroup
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		parts := splitKey(string(iter.Key()))
+		if len(parts) != 5 {
+			continue
+		}
+		group, err := r.GetGroupByID(ctx, domain.LinkGroupID(parts[4]))
+		if err == nil {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *PebbleRepository) DeleteGroup(ctx context.Context, id domain.LinkGroupID) error {
+	r.groupMu.Lock()
+	defer r.groupMu.Unlock()
+
+	group, err := r.GetGroupByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	batch.Delete(groupKey(id), pebble.Sync)
+	batch.Delete(groupIndexKey(group.UserID, group.Order, id), pebble.Sync)
+
+	return batch.Commit(pebble.Sync)
+}
+
+// ReorderGroups assigns each group its position in groupIDs in a single atomic
+// batch. IDs that do not belong to the user are skipped.
+func (r *PebbleRepository) ReorderGroups(ctx context.Context, userID domain.UserID, groupIDs []domain.LinkGroupID) error {
+	r.groupMu.Lock()
+	defer r.groupMu.Unlock()
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	for i, id := range groupIDs {
+		group, err := r.GetGroupByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		if group.UserID != userID {
+			continue
+		}
+
+		previous := *group
+		group.Order = i
+		if err := setGroupInBatch(batch, group, &previous); err != nil {
+			return err
+		}
+	}
+
+	return batch.Commit(pebble.Sync)
 }
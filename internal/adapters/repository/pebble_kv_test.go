@@ -0,0 +1,784 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/elchemista/driplnk/internal/adapters/repository"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+func newTestPebble(t *testing.T) *repository.PebbleRepository {
+	t.Helper()
+	repo, err := repository.NewPebbleRepository(&repository.PebbleConfig{Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to open pebble: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func linkIDs(links []*domain.Link) []domain.LinkID {
+	ids := make([]domain.LinkID, len(links))
+	for i, l := range links {
+		ids[i] = l.ID
+	}
+	return ids
+}
+
+// placementsOf places the links in the given order without a group.
+func placementsOf(ids ...domain.LinkID) []domain.LinkPlacement {
+	placements := make([]domain.LinkPlacement, len(ids))
+	for i, id := range ids {
+		placements[i] = domain.LinkPlacement{LinkID: id}
+	}
+	return placements
+}
+
+func TestPebbleRepository_ListLinksByUser_SortedByOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	// Insert out of order; IDs sort differently from Order on purpose.
+	for _, l := range []*domain.Link{
+		{ID: "a", UserID: "u1", Order: 2},
+		{ID: "b", UserID: "u1", Order: 0},
+		{ID: "c", UserID: "u1", Order: 1},
+		{ID: "d", UserID: "u2", Order: 0},
+	} {
+		if err := repo.SaveLink(ctx, l); err != nil {
+			t.Fatalf("SaveLink failed: %v", err)
+		}
+	}
+
+	links, err := repo.ListLinksByUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListLinksByUser failed: %v", err)
+	}
+	got := linkIDs(links)
+	want := []domain.LinkID{"b", "c", "a"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPebbleRepository_Reorder(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	for _, l := range []*domain.Link{
+		{ID: "a", UserID: "u1", Order: 0},
+		{ID: "b", UserID: "u1", Order: 1},
+		{ID: "c", UserID: "u1", Order: 2},
+		{ID: "x", UserID: "u2", Order: 0},
+	} {
+		if err := repo.SaveLink(ctx, l); err != nil {
+			t.Fatalf("SaveLink failed: %v", err)
+		}
+	}
+
+	// "x" belongs to another user and "missing" does not exist: both are skipped.
+	if err := repo.Reorder(ctx, "u1", placementsOf("c", "x", "a", "missing", "b")); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+
+	links, err := repo.ListLinksByUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListLinksByUser failed: %v", err)
+	}
+	got := linkIDs(links)
+	want := []domain.LinkID{"c", "a", "b"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v (no stale index entries), got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	other, err := repo.GetLinkByID(ctx, "x")
+	if err != nil {
+		t.Fatalf("GetLinkByID failed: %v", err)
+	}
+	if other.Order != 0 {
+		t.Errorf("expected foreign link order untouched, got %d", other.Order)
+	}
+
+	if err := repo.DeleteLink(ctx, "a"); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+	links, _ = repo.ListLinksByUser(ctx, "u1")
+	if len(links) != 2 {
+		t.Errorf("expected 2 links after delete, got %d", len(links))
+	}
+}
+
+func TestPebbleRepository_SlugIndex(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	if err := repo.SaveLink(ctx, &domain.Link{ID: "a", UserID: "u1", Slug: "merch"}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+
+	link, err := repo.GetLinkBySlug(ctx, "merch")
+	if err != nil || link.ID != "a" {
+		t.Fatalf("expected slug to resolve to a, got %v (%v)", link, err)
+	}
+
+	// Another link cannot claim the same slug
+	err = repo.SaveLink(ctx, &domain.Link{ID: "b", UserID: "u2", Slug: "merch"})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	// Renaming frees the old slug
+	if err := repo.SaveLink(ctx, &domain.Link{ID: "a", UserID: "u1", Slug: "shop"}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	if _, err := repo.GetLinkBySlug(ctx, "merch"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected old slug to be released, got %v", err)
+	}
+
+	if err := repo.DeleteLink(ctx, "a"); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+	if _, err := repo.GetLinkBySlug(ctx, "shop"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected slug to be removed with the link, got %v", err)
+	}
+}
+
+func TestPebbleRepository_PasswordHashStoredSeparately(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	link := &domain.Link{ID: "a", UserID: "u1", Protection: domain.ProtectionPassword, PasswordHash: "$2a$10$hash"}
+	if err := repo.SaveLink(ctx, link); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+
+	got, err := repo.GetLinkByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetLinkByID failed: %v", err)
+	}
+	if got.PasswordHash != link.PasswordHash {
+		t.Errorf("expected password hash to round-trip, got %q", got.PasswordHash)
+	}
+
+	// Reorder must not drop the hash
+	if err := repo.Reorder(ctx, "u1", placementsOf("a")); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	got, _ = repo.GetLinkByID(ctx, "a")
+	if got.PasswordHash != link.PasswordHash {
+		t.Errorf("expected password hash to survive reorder, got %q", got.PasswordHash)
+	}
+
+	// Clearing protection removes the hash
+	got.Protection = domain.ProtectionNone
+	got.PasswordHash = ""
+	if err := repo.SaveLink(ctx, got); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	got, _ = repo.GetLinkByID(ctx, "a")
+	if got.PasswordHash != "" {
+		t.Errorf("expected password hash to be removed, got %q", got.PasswordHash)
+	}
+}
+
+func TestPebbleRepository_ReorderMovesLinksBetweenGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	for _, l := range []*domain.Link{
+		{ID: "a", UserID: "u1", Order: 0, GroupID: "g1"},
+		{ID: "b", UserID: "u1", Order: 1, GroupID: "g1"},
+		{ID: "c", UserID: "u1", Order: 2},
+	} {
+		if err := repo.SaveLink(ctx, l); err != nil {
+			t.Fatalf("SaveLink failed: %v", err)
+		}
+	}
+
+	err := repo.Reorder(ctx, "u1", []domain.LinkPlacement{
+		{LinkID: "c", GroupID: "g1"},
+		{LinkID: "a"},
+		{LinkID: "b", GroupID: "g2"},
+	})
+	if err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+
+	links, _ := repo.ListLinksByUser(ctx, "u1")
+	got := make([]string, len(links))
+	for i, l := range links {
+		got[i] = string(l.ID) + "/" + string(l.GroupID)
+	}
+	want := []string{"c/g1", "a/", "b/g2"}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPebbleRepository_ReorderDuplicateIDs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := repository.NewPebbleRepository(&repository.PebbleConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("failed to open pebble: %v", err)
+	}
+
+	for i, id := range []domain.LinkID{"a", "b"} {
+		if err := repo.SaveLink(ctx, &domain.Link{ID: id, UserID: "u1", Order: i}); err != nil {
+			t.Fatalf("SaveLink failed: %v", err)
+		}
+	}
+
+	err = repo.Reorder(ctx, "u1", []domain.LinkPlacement{{LinkID: "b"}, {LinkID: "a"}, {LinkID: "b"}})
+	if err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	links, _ := repo.ListLinksByUser(ctx, "u1")
+	if len(links) != 2 || links[0].ID != "a" || links[1].ID != "b" || links[1].Order != 2 {
+		t.Fatalf("expected b at its last placement after a, got %+v", links)
+	}
+	repo.Close()
+
+	// Every link keeps exactly one index key
+	db, err := pebble.Open(filepath.Join(dir, "driplnk.db"), &pebble.Options{})
+	if err != nil {
+		t.Fatalf("failed to open pebble: %v", err)
+	}
+	defer db.Close()
+	prefix := []byte("user:links:u1:")
+	iter, _ := db.NewIter(&pebble.IterOptions{LowerBound: prefix, UpperBound: []byte("user:links:u1;")})
+	var keys []string
+	for iter.First(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Close()
+	if len(keys) != 2 {
+		t.Errorf("expected 2 index keys, got %v", keys)
+	}
+}
+
+func TestPebbleRepository_Groups(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	for _, g := range []*domain.LinkGroup{
+		{ID: "g1", UserID: "u1", Title: "Music", Order: 0},
+		{ID: "g2", UserID: "u1", Title: "Merch", Order: 1, Collapsed: true},
+		{ID: "g3", UserID: "u1", Title: "Press", Order: 2},
+		{ID: "gx", UserID: "u2", Title: "Other", Order: 0},
+	} {
+		if err := repo.SaveGroup(ctx, g); err != nil {
+			t.Fatalf("SaveGroup failed: %v", err)
+		}
+	}
+
+	// "gx" belongs to another user and is skipped
+	if err := repo.ReorderGroups(ctx, "u1", []domain.LinkGroupID{"g3", "gx", "g1", "g2"}); err != nil {
+		t.Fatalf("ReorderGroups failed: %v", err)
+	}
+	if err := repo.DeleteGroup(ctx, "g1"); err != nil {
+		t.Fatalf("DeleteGroup failed: %v", err)
+	}
+
+	groups, err := repo.ListGroupsByUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListGroupsByUser failed: %v", err)
+	}
+	if len(groups) != 2 || groups[0].ID != "g3" || groups[1].ID != "g2" {
+		t.Fatalf("expected [g3 g2] without stale index entries, got %+v", groups)
+	}
+	if !groups[1].Collapsed || groups[1].Order != 3 {
+		t.Errorf("unexpected group %+v", groups[1])
+	}
+
+	other, _ := repo.GetGroupByID(ctx, "gx")
+	if other.Order != 0 {
+		t.Errorf("expected other user's group to keep its order, got %d", other.Order)
+	}
+}
+
+func TestPebbleRepository_Revisions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, rev := range []*domain.LinkRevision{
+		{ID: "r1", LinkID: "l1", UserID: "u1", Action: domain.RevisionUpdate, Before: &domain.Link{ID: "l1", Title: "Old"}, After: &domain.Link{ID: "l1", Title: "New"}},
+		{ID: "r2", LinkID: "l2", UserID: "u1", Action: domain.RevisionUpdate},
+		{ID: "r3", LinkID: "l1", UserID: "u1", Action: domain.RevisionDelete, Before: &domain.Link{ID: "l1", Title: "New"}},
+		{ID: "r4", LinkID: "lx", UserID: "u2", Action: domain.RevisionUpdate},
+	} {
+		rev.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := repo.SaveRevision(ctx, rev); err != nil {
+			t.Fatalf("SaveRevision failed: %v", err)
+		}
+	}
+
+	if err := repo.SaveRevision(ctx, &domain.LinkRevision{ID: "r1", LinkID: "l1", UserID: "u1"}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected revisions to be immutable, got %v", err)
+	}
+
+	history, err := repo.ListRevisionsByLink(ctx, "l1")
+	if err != nil {
+		t.Fatalf("ListRevisionsByLink failed: %v", err)
+	}
+	if len(history) != 2 || history[0].ID != "r3" || history[1].ID != "r1" {
+		t.Fatalf("expected [r3 r1], got %+v", history)
+	}
+	if history[0].After != nil || history[1].Before.Title != "Old" {
+		t.Errorf("unexpected snapshots %+v, %+v", history[0], history[1])
+	}
+
+	recent, err := repo.ListRevisionsByUser(ctx, "u1", 2)
+	if err != nil {
+		t.Fatalf("ListRevisionsByUser failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ID != "r3" || recent[1].ID != "r2" {
+		t.Errorf("expected the two newest revisions [r3 r2], got %+v", recent)
+	}
+
+	if _, err := repo.GetRevisionByID(ctx, "missing"); err == nil {
+		t.Error("expected an error for a missing revision")
+	}
+}
+
+func TestPebbleRepository_DeleteLinkDetachesEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	if err := repo.SaveLink(ctx, &domain.Link{ID: "l1", UserID: "u1", Title: "One", URL: "https://one.example"}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	userID, linkID := "u1", "l1"
+	if err := repo.SaveEvent(ctx, &domain.AnalyticsEvent{ID: "e1", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID}); err != nil {
+		t.Fatalf("SaveEvent failed: %v", err)
+	}
+
+	if err := repo.DeleteLink(ctx, "l1"); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+
+	profile, _ := repo.GetSummary(ctx, userID, nil)
+	if profile.TotalClicks != 1 {
+		t.Errorf("expected the click to stay in the owner's totals, got %d", profile.TotalClicks)
+	}
+	link, _ := repo.GetSummary(ctx, userID, &linkID)
+	if link.TotalClicks != 0 {
+		t.Errorf("expected no events left on the deleted link, got %d", link.TotalClicks)
+	}
+}
+
+func TestPebbleRepository_CountClick(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	if err := repo.SaveLink(ctx, &domain.Link{ID: "l1", UserID: "u1", Title: "Drop", URL: "https://drop.example", MaxClicks: 3, MaxVisitors: 2}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+
+	count := func(visitorID string) bool {
+		t.Helper()
+		ok, err := repo.CountClick(ctx, "l1", visitorID)
+		if err != nil {
+			t.Fatalf("CountClick failed: %v", err)
+		}
+		return ok
+	}
+
+	if !count("alice") || !count("alice") || !count("bob") {
+		t.Fatal("expected clicks within the limits to be counted")
+	}
+	if count("carol") {
+		t.Error("expected the click limit to refuse another click")
+	}
+
+	link, _ := repo.GetLinkByID(ctx, "l1")
+	if link.ClickCount != 3 || link.UniqueVisitors != 2 {
+		t.Errorf("expected 3 clicks from 2 visitors, got %d and %d", link.ClickCount, link.UniqueVisitors)
+	}
+
+	// Saving a stale copy must not reset the counters
+	stale := &domain.Link{ID: "l1", UserID: "u1", Title: "Drop", URL: "https://drop.example", MaxVisitors: 2}
+	if err := repo.SaveLink(ctx, stale); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	link, _ = repo.GetLinkByID(ctx, "l1")
+	if link.ClickCount != 3 || link.UniqueVisitors != 2 {
+		t.Errorf("expected SaveLink to keep the counters, got %d and %d", link.ClickCount, link.UniqueVisitors)
+	}
+
+	// Only the visitor limit is left: returning visitors still get through
+	if !count("bob") {
+		t.Error("expected a returning visitor to be counted")
+	}
+	if count("carol") {
+		t.Error("expected the visitor limit to refuse a new visitor")
+	}
+
+	if _, err := repo.CountClick(ctx, "missing", "alice"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	// Deleting the link forgets its visitors
+	if err := repo.DeleteLink(ctx, "l1"); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+	if err := repo.SaveLink(ctx, &domain.Link{ID: "l1", UserID: "u1", Title: "Drop", URL: "https://drop.example", MaxVisitors: 1}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	if !count("carol") || count("alice") {
+		t.Error("expected a recreated link to start with no visitors")
+	}
+}
+
+func TestPebbleRepository_GetSummaryCountsQRScans(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID, linkID := "u1", "l1"
+	events := []*domain.AnalyticsEvent{
+		{ID: "e1", EventType: domain.EventTypeView, UserID: &userID},
+		{ID: "e2", EventType: domain.EventTypeView, UserID: &userID, Meta: map[string]string{"source": domain.SourceQR}},
+		{ID: "e3", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, Meta: map[string]string{"source": domain.SourceQR}},
+		{ID: "e4", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID},
+	}
+	for _, event := range events {
+		if err := repo.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	summary, err := repo.GetSummary(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.TotalViews != 2 || summary.TotalClicks != 2 {
+		t.Errorf("expected 2 views and 2 clicks, got %d and %d", summary.TotalViews, summary.TotalClicks)
+	}
+	if summary.QRViews != 1 || summary.QRClicks != 1 {
+		t.Errorf("expected 1 QR view and 1 QR click, got %d and %d", summary.QRViews, summary.QRClicks)
+	}
+}
+
+func TestPebbleRepository_GetTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID, otherUser, linkID := "u1", "u2", "l1"
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // a Monday
+	events := []*domain.AnalyticsEvent{
+		{ID: "e1", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", CreatedAt: day.Add(1 * time.Hour)},
+		{ID: "e2", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", CreatedAt: day.Add(2 * time.Hour)},
+		{ID: "e3", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "v1", CreatedAt: day.Add(3 * time.Hour)},
+		{ID: "e4", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v2", CreatedAt: day.AddDate(0, 0, 2)},
+		{ID: "e5", EventType: domain.EventTypeScroll, UserID: &userID, VisitorID: "v2", CreatedAt: day.AddDate(0, 0, 2)},
+		{ID: "e6", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v3", CreatedAt: day.AddDate(0, 0, 9)}, // outside the range
+		{ID: "e7", EventType: domain.EventTypeView, UserID: &otherUser, VisitorID: "v4", CreatedAt: day},
+	}
+	for _, event := range events {
+		if err := repo.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	series, err := repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: day, To: day.AddDate(0, 0, 7), Bucket: domain.BucketDay})
+	if err != nil {
+		t.Fatalf("GetTimeSeries failed: %v", err)
+	}
+	if len(series.Points) != 7 {
+		t.Fatalf("expected 7 daily points, got %d", len(series.Points))
+	}
+	first := series.Points[0]
+	if first.Views != 2 || first.Clicks != 1 || first.UniqueVisitors != 1 {
+		t.Errorf("expected 2 views, 1 click and 1 visitor on day one, got %+v", first)
+	}
+	if p := series.Points[2]; p.Views != 1 || p.UniqueVisitors != 1 {
+		t.Errorf("expected 1 view on day three, got %+v", p)
+	}
+	if p := series.Points[1]; p.Views != 0 || p.Clicks != 0 {
+		t.Errorf("expected an empty day two, got %+v", p)
+	}
+	if series.UniqueVisitors != 2 {
+		t.Errorf("expected 2 unique visitors in the range, got %d", series.UniqueVisitors)
+	}
+
+	// Scoped to one link, hourly
+	series, err = repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, LinkID: &linkID, From: day, To: day.Add(24 * time.Hour), Bucket: domain.BucketHour})
+	if err != nil {
+		t.Fatalf("GetTimeSeries failed: %v", err)
+	}
+	if len(series.Points) != 24 || series.Points[3].Clicks != 1 {
+		t.Errorf("expected the click in the fourth hour, got %+v", series.Points)
+	}
+	if views, clicks := series.Totals(); views != 0 || clicks != 1 {
+		t.Errorf("expected only the link click, got %d views and %d clicks", views, clicks)
+	}
+}
+
+func TestPebbleRepository_TopLinksAndReferrers(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID, shop, blog := "u1", "shop", "blog"
+	events := []*domain.AnalyticsEvent{
+		{ID: "e1", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", Country: "US", Meta: map[string]string{"referrer": "instagram.com", "source": "instagram", "utm_campaign": "spring", "device_type": "desktop"}},
+		{ID: "e2", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &shop, VisitorID: "v1", Country: "IT", Meta: map[string]string{"referrer": domain.ReferrerInternal, "device_type": "mobile"}},
+		{ID: "e3", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &shop, VisitorID: "v1", Meta: map[string]string{"referrer": domain.ReferrerInternal}},
+		{ID: "e4", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &shop, VisitorID: "v2"},
+		{ID: "e5", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &blog, VisitorID: "v2"},
+		{ID: "e6", EventType: domain.EventTypeScroll, UserID: &userID, VisitorID: "v1", Country: "DE", Meta: map[string]string{"device_type": "mobile"}},
+	}
+	for _, event := range events {
+		if err := repo.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	top, err := repo.GetTopLinks(ctx, userID, 10)
+	if err != nil {
+		t.Fatalf("GetTopLinks failed: %v", err)
+	}
+	if len(top) != 2 || top[0].LinkID != shop || top[0].Clicks != 3 || top[0].UniqueVisitors != 2 {
+		t.Errorf("expected shop first with 3 clicks from 2 visitors, got %+v", top)
+	}
+	if top, _ := repo.GetTopLinks(ctx, userID, 1); len(top) != 1 {
+		t.Errorf("expected the limit to apply, got %+v", top)
+	}
+
+	// Profile splits only count views; link splits only count clicks
+	summary, _ := repo.GetSummary(ctx, userID, nil)
+	if len(summary.ByCountry) != 1 || summary.ByCountry["US"] != 1 || len(summary.ByDevice) != 1 || summary.ByDevice["desktop"] != 1 {
+		t.Errorf("expected countries and devices of the one view, got %v and %v", summary.ByCountry, summary.ByDevice)
+	}
+	if len(summary.ByReferrer) != 1 || summary.ByReferrer["instagram.com"] != 1 {
+		t.Errorf("expected one instagram view, got %v", summary.ByReferrer)
+	}
+	if len(summary.BySource) != 1 || summary.BySource["instagram"] != 1 || summary.ByCampaign["spring"] != 1 {
+		t.Errorf("expected one instagram view of the spring campaign, got %v and %v", summary.BySource, summary.ByCampaign)
+	}
+	summary, _ = repo.GetSummary(ctx, userID, &shop)
+	if summary.ByReferrer[domain.ReferrerInternal] != 2 {
+		t.Errorf("expected 2 internal clicks, got %v", summary.ByReferrer)
+	}
+	if len(summary.ByCountry) != 1 || summary.ByCountry["IT"] != 1 || len(summary.ByDevice) != 1 || summary.ByDevice["mobile"] != 1 {
+		t.Errorf("expected countries and devices of the shop clicks, got %v and %v", summary.ByCountry, summary.ByDevice)
+	}
+}
+
+func TestPebbleRepository_UniqueVisitors(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID, linkID := "u1", "l1"
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	// 1000 visitors view the profile twice on day one; every tenth comes back on day two and clicks
+	for i := 0; i < 1000; i++ {
+		visitor := fmt.Sprintf("visitor-%d", i)
+		for j := 0; j < 2; j++ {
+			event := &domain.AnalyticsEvent{ID: fmt.Sprintf("v%d-%d", i, j), EventType: domain.EventTypeView, UserID: &userID, VisitorID: visitor, CreatedAt: day.Add(time.Duration(i%24) * time.Hour)}
+			if err := repo.SaveEvent(ctx, event); err != nil {
+				t.Fatalf("SaveEvent failed: %v", err)
+			}
+		}
+		if i%10 == 0 {
+			event := &domain.AnalyticsEvent{ID: fmt.Sprintf("c%d", i), EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: visitor, CreatedAt: day.AddDate(0, 0, 1)}
+			if err := repo.SaveEvent(ctx, event); err != nil {
+				t.Fatalf("SaveEvent failed: %v", err)
+			}
+		}
+	}
+
+	// Sketches estimate; allow a few percent of error
+	within := func(got, want int64) bool {
+		diff := got - want
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff*100 <= want*3
+	}
+
+	summary, err := repo.GetSummary(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if !within(summary.UniqueVisitors, 1000) {
+		t.Errorf("expected about 1000 unique visitors, got %d", summary.UniqueVisitors)
+	}
+	summary, _ = repo.GetSummary(ctx, userID, &linkID)
+	if !within(summary.UniqueVisitors, 100) {
+		t.Errorf("expected about 100 unique link visitors, got %d", summary.UniqueVisitors)
+	}
+
+	series, err := repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: day, To: day.AddDate(0, 0, 7), Bucket: domain.BucketWeek})
+	if err != nil {
+		t.Fatalf("GetTimeSeries failed: %v", err)
+	}
+	if len(series.Points) != 1 || series.Points[0].Views != 2000 || series.Points[0].Clicks != 100 {
+		t.Fatalf("expected one week with 2000 views and 100 clicks, got %+v", series.Points)
+	}
+	if !within(series.Points[0].UniqueVisitors, 1000) || series.UniqueVisitors != series.Points[0].UniqueVisitors {
+		t.Errorf("expected about 1000 unique visitors in the week, got %d (range %d)", series.Points[0].UniqueVisitors, series.UniqueVisitors)
+	}
+
+	series, _ = repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: day, To: day.Add(24 * time.Hour), Bucket: domain.BucketHour})
+	if p := series.Points[5]; !within(p.UniqueVisitors, 42) {
+		t.Errorf("expected about 42 unique visitors in the sixth hour, got %d", p.UniqueVisitors)
+	}
+
+	top, _ := repo.GetTopLinks(ctx, userID, 10)
+	if len(top) != 1 || !within(top[0].UniqueVisitors, 100) {
+		t.Errorf("expected about 100 unique visitors on the link, got %+v", top)
+	}
+
+	// Deleting the link drops its sketches
+	if err := repo.SaveLink(ctx, &domain.Link{ID: domain.LinkID(linkID), UserID: domain.UserID(userID), Title: "Shop", URL: "https://shop.example.com"}); err != nil {
+		t.Fatalf("SaveLink failed: %v", err)
+	}
+	if err := repo.DeleteLink(ctx, domain.LinkID(linkID)); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+	if summary, _ := repo.GetSummary(ctx, userID, &linkID); summary.UniqueVisitors != 0 {
+		t.Errorf("expected no visitors for a deleted link, got %d", summary.UniqueVisitors)
+	}
+}
+
+func TestPebbleRepository_TimeSeriesPartialBuckets(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID := "u1"
+	hour := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i, e := range []struct {
+		visitor string
+		at      time.Duration
+	}{
+		{"a", 10 * time.Minute},
+		{"b", 40 * time.Minute},
+		{"b", 80 * time.Minute},
+		{"c", 110 * time.Minute},
+		{"a", 135 * time.Minute},
+	} {
+		event := &domain.AnalyticsEvent{ID: fmt.Sprintf("e%d", i), EventType: domain.EventTypeView, UserID: &userID, VisitorID: e.visitor, CreatedAt: hour.Add(e.at)}
+		if err := repo.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	// The range starts mid-hour: a's first view is outside it
+	series, err := repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: hour.Add(30 * time.Minute), To: hour.Add(3 * time.Hour), Bucket: domain.BucketHour})
+	if err != nil {
+		t.Fatalf("GetTimeSeries failed: %v", err)
+	}
+	want := []int64{1, 2, 1}
+	if len(series.Points) != len(want) {
+		t.Fatalf("expected %d points, got %+v", len(want), series.Points)
+	}
+	for i, p := range series.Points {
+		if p.UniqueVisitors != want[i] || p.UniqueVisitors > p.Views+p.Clicks {
+			t.Errorf("point %d: expected %d unique visitors, got %d with %d views", i, want[i], p.UniqueVisitors, p.Views)
+		}
+	}
+	if series.UniqueVisitors != 3 {
+		t.Errorf("expected 3 unique visitors in the range, got %d", series.UniqueVisitors)
+	}
+
+	// The range ends mid-hour: a's last view is outside it
+	series, _ = repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: hour, To: hour.Add(2*time.Hour + 10*time.Minute), Bucket: domain.BucketHour})
+	if last := series.Points[len(series.Points)-1]; last.UniqueVisitors != 0 {
+		t.Errorf("expected no visitors in the covered part of the last hour, got %d", last.UniqueVisitors)
+	}
+}
+
+func TestPebbleRepository_BuildsVisitorSketches(t *testing.T) {
+	dir := t.TempDir()
+	userID := "u1"
+
+	// Events written before visitors were counted have no sketches
+	db, err := pebble.Open(filepath.Join(dir, "driplnk.db"), &pebble.Options{})
+	if err != nil {
+		t.Fatalf("failed to open pebble: %v", err)
+	}
+	for i, visitor := range []string{"a", "b", "a"} {
+		data, _ := json.Marshal(&domain.AnalyticsEvent{ID: fmt.Sprintf("e%d", i), EventType: domain.EventTypeView, UserID: &userID, VisitorID: visitor})
+		db.Set([]byte(fmt.Sprintf("analytics:event:e%d", i)), data, pebble.Sync)
+		db.Set([]byte(fmt.Sprintf("analytics:user:%s:e%d", userID, i)), []byte{}, pebble.Sync)
+	}
+	db.Close()
+
+	repo, err := repository.NewPebbleRepository(&repository.PebbleConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("failed to open pebble: %v", err)
+	}
+	defer repo.Close()
+
+	summary, err := repo.GetSummary(context.Background(), userID, nil)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.TotalViews != 3 || summary.UniqueVisitors != 2 {
+		t.Errorf("expected 3 views from 2 visitors, got %d and %d", summary.TotalViews, summary.UniqueVisitors)
+	}
+}
+
+func TestPebbleRepository_BotEventsCountedApart(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestPebble(t)
+
+	userID, linkID := "u1", "l1"
+	now := time.Now().UTC()
+	events := []*domain.AnalyticsEvent{
+		{ID: "e1", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v1", CreatedAt: now},
+		{ID: "e2", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "v1", CreatedAt: now},
+		{ID: "e3", EventType: domain.EventTypeView, UserID: &userID, VisitorID: "slack", IsBot: true, CreatedAt: now, Meta: map[string]string{"source": "slack.com"}},
+		{ID: "e4", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "slack", IsBot: true, CreatedAt: now},
+		{ID: "e5", EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "whatsapp", IsBot: true, CreatedAt: now},
+	}
+	for _, event := range events {
+		if err := repo.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	summary, err := repo.GetSummary(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.TotalViews != 1 || summary.TotalClicks != 1 || summary.UniqueVisitors != 1 {
+		t.Errorf("expected bots left out of the totals, got %+v", summary)
+	}
+	if summary.BotViews != 1 || summary.BotClicks != 2 {
+		t.Errorf("expected 1 bot view and 2 bot clicks, got %d and %d", summary.BotViews, summary.BotClicks)
+	}
+	if len(summary.BySource) != 0 {
+		t.Errorf("expected bots left out of the sources, got %v", summary.BySource)
+	}
+
+	series, _ := repo.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: now.Add(-time.Hour), To: now.Add(time.Hour), Bucket: domain.BucketHour})
+	if views, clicks := series.Totals(); views != 1 || clicks != 1 || series.UniqueVisitors != 1 {
+		t.Errorf("expected bots left out of the series, got %d views, %d clicks and %d visitors", views, clicks, series.UniqueVisitors)
+	}
+
+	top, _ := repo.GetTopLinks(ctx, userID, 10)
+	if len(top) != 1 || top[0].Clicks != 1 || top[0].UniqueVisitors != 1 {
+		t.Errorf("expected bots left out of the top links, got %+v", top)
+	}
+}
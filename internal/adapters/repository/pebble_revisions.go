@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// --- Link Revision Repository ---
+//
+// Keys:
+//   revision:record:<revisionID>                      -> JSON revision
+//   revision:link:<linkID>:<unixNano>:<revisionID>    -> "" (per-link history)
+//   revision:user:<userID>:<unixNano>:<revisionID>    -> "" (per-owner history)
+// Timestamps are zero-padded so the indexes sort chronologically.
+
+func revisionKey(id domain.LinkRevisionID) []byte {
+	return []byte(fmt.Sprintf("revision:record:%s", id))
+}
+
+func revisionIndexKey(scope, ownerID string, rev *domain.LinkRevision) []byte {
+	return []byte(fmt.Sprintf("revision:%s:%s:%020d:%s", scope, ownerID, rev.CreatedAt.UnixNano(), rev.ID))
+}
+
+// SaveRevision appends a revision. Existing revisions are never overwritten.
+func (r *PebbleRepository) SaveRevision(ctx context.Context, rev *domain.LinkRevision) error {
+	if _, closer, err := r.db.Get(revisionKey(rev.ID)); err == nil {
+		closer.Close()
+		return fmt.Errorf("revision %s already exists: %w", rev.ID, domain.ErrConflict)
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+
+	data, err := json.Marshal(rev)
+	if err != nil {
+		return err
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(revisionKey(rev.ID), data, pebble.Sync); err != nil {
+		return err
+	}
+	if err := batch.Set(revisionIndexKey("link", string(rev.LinkID), rev), []byte{}, pebble.Sync); err != nil {
+		return err
+	}
+	if err := batch.Set(revisionIndexKey("user", string(rev.UserID), rev), []byte{}, pebble.Sync); err != nil {
+		return err
+	}
+
+	return batch.Commit(pebble.Sync)
+}
+
+func (r *PebbleRepository) GetRevisionByID(ctx context.Context, id domain.LinkRevisionID) (*domain.LinkRevision, error) {
+	val, closer, err := r.db.Get(revisionKey(id))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	var rev domain.LinkRevision
+	if err := json.Unmarshal(val, &rev); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *PebbleRepository) ListRevisionsByLink(ctx context.Context, linkID domain.LinkID) ([]*domain.LinkRevision, error) {
+	return r.listRevisions(ctx, fmt.Sprintf("revision:link:%s:", linkID), 0)
+}
+
+func (r *PebbleRepository) ListRevisionsByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	return r.listRevisions(ctx, fmt.Sprintf("revision:user:%s:", userID), limit)
+}
+
+// listRevisions walks an index backwards so the newest revisions come first.
+// A limit of 0 returns every revision.
+func (r *PebbleRepository) listRevisions(ctx context.Context, prefix string, limit int) ([]*domain.LinkRevision, error) {
+	iter, err := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefix),
+		UpperBound: []byte(prefix + "\xff"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var revisions []*domain.LinkRevision
+	for iter.Last(); iter.Valid(); iter.Prev() {
+		parts := splitKey(string(iter.Key()))
+		if len(parts) != 5 {
+			continue
+		}
+		rev, err := r.GetRevisionByID(ctx, domain.LinkRevisionID(parts[4]))
+		if err != nil {
+			continue // orphaned index entry
+		}
+		revisions = append(revisions, rev)
+		if limit > 0 && len(revisions) >= limit {
+			break
+		}
+	}
+	return revisions, nil
+}
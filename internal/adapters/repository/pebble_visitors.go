@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// Unique visitors are counted with HyperLogLog sketches kept next to the events,
+// so reading them never scans the events themselves. Every view and click is added
+// to the sketches of its user and link, all time and for its UTC hour and day.
+// Key: analytics:hll:<user|link>:<id>:<all|hour:<unix>|day:<unix>>
+
+// visitorSketchesBuilt marks a store whose sketches cover every stored event.
+var visitorSketchesBuilt = []byte("analytics:hll:built")
+
+func visitorSketchKey(scope, id, period string) []byte {
+	return []byte(fmt.Sprintf("analytics:hll:%s:%s:%s", scope, id, period))
+}
+
+func visitorSketchPeriod(bucket domain.TimeBucket, start int64) string {
+	return fmt.Sprintf("%s:%d", bucket, start)
+}
+
+// visitorSketchKeys returns the keys of the sketches an event counts towards.
+// Bots count towards none.
+func visitorSketchKeys(event *domain.AnalyticsEvent) [][]byte {
+	if (event.EventType != domain.EventTypeView && event.EventType != domain.EventTypeClick) || event.IsBot {
+		return nil
+	}
+	periods := []string{
+		"all",
+		visitorSketchPeriod(domain.BucketHour, domain.BucketHour.Truncate(event.CreatedAt).Unix()),
+		visitorSketchPeriod(domain.BucketDay, domain.BucketDay.Truncate(event.CreatedAt).Unix()),
+	}
+
+	var keys [][]byte
+	for _, period := range periods {
+		if event.UserID != nil {
+			keys = append(keys, visitorSketchKey("user", *event.UserID, period))
+		}
+		if event.LinkID != nil {
+			keys = append(keys, visitorSketchKey("link", *event.LinkID, period))
+		}
+	}
+	return keys
+}
+
+// loadVisitorSketch reads a sketch, or returns an empty one when none is stored.
+func (r *PebbleRepository) loadVisitorSketch(key []byte) (*domain.HyperLogLog, error) {
+	sketch := domain.NewHyperLogLog()
+	val, closer, err := r.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return sketch, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	if err := sketch.UnmarshalBinary(val); err != nil {
+		return nil, fmt.Errorf("sketch %s: %w", key, err)
+	}
+	return sketch, nil
+}
+
+// addVisitor adds the event's visitor to its sketches within batch.
+// Callers hold visitorMu until the batch is committed.
+func (r *PebbleRepository) addVisitor(batch *pebble.Batch, event *domain.AnalyticsEvent) error {
+	for _, key := range visitorSketchKeys(event) {
+		sketch, err := r.loadVisitorSketch(key)
+		if err != nil {
+			return err
+		}
+		sketch.Add(event.VisitorID)
+		data, err := sketch.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(key, data, pebble.Sync); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeVisitors returns the union of the given sketches. Unreadable sketches are skipped.
+func (r *PebbleRepository) mergeVisitors(keys ...[]byte) *domain.HyperLogLog {
+	merged := domain.NewHyperLogLog()
+	for _, key := range keys {
+		sketch, err := r.loadVisitorSketch(key)
+		if err != nil {
+			log.Printf("[WARN] Failed to load visitor sketch: %v", err)
+			continue
+		}
+		merged.Merge(sketch)
+	}
+	return merged
+}
+
+// countVisitors estimates the distinct visitors of the union of the given sketches.
+func (r *PebbleRepository) countVisitors(keys ...[]byte) int64 {
+	return r.mergeVisitors(keys...).Count()
+}
+
+// fillUniqueVisitors sets the unique visitors of every point of series, and of
+// its whole range, from the hourly or daily sketches of the user or link.
+// Sketches cover whole buckets, so the buckets the range only partly covers
+// are counted from edges, the visitors of their events within the range.
+func (r *PebbleRepository) fillUniqueVisitors(series *domain.TimeSeries, scope, id string, edges map[time.Time]map[string]struct{}) {
+	all := domain.NewHyperLogLog()
+	for i := range series.Points {
+		point := &series.Points[i]
+		if series.Partial(point.Start) {
+			visitors := edges[point.Start]
+			point.UniqueVisitors = int64(len(visitors))
+			for visitor := range visitors {
+				all.Add(visitor)
+			}
+			continue
+		}
+
+		var keys [][]byte
+		switch series.Bucket {
+		case domain.BucketHour:
+			keys = append(keys, visitorSketchKey(scope, id, visitorSketchPeriod(domain.BucketHour, point.Start.Unix())))
+		default:
+			// Weeks start on a day boundary, so they are the union of their days
+			for day := point.Start; day.Before(series.Bucket.Next(point.Start)); day = day.AddDate(0, 0, 1) {
+				keys = append(keys, visitorSketchKey(scope, id, visitorSketchPeriod(domain.BucketDay, day.Unix())))
+			}
+		}
+
+		sketch := r.mergeVisitors(keys...)
+		point.UniqueVisitors = sketch.Count()
+		all.Merge(sketch)
+	}
+	series.UniqueVisitors = all.Count()
+}
+
+// dropLinkVisitorSketches deletes the sketches of a deleted link.
+func dropLinkVisitorSketches(batch *pebble.Batch, linkID domain.LinkID) error {
+	prefix := []byte(fmt.Sprintf("analytics:hll:link:%s:", linkID))
+	return batch.DeleteRange(prefix, append(prefix[:len(prefix):len(prefix)], 0xff), pebble.Sync)
+}
+
+// buildVisitorSketches fills the sketches from the stored events once, for stores
+// created before unique visitors were counted.
+func (r *PebbleRepository) buildVisitorSketches() error {
+	if _, closer, err := r.db.Get(visitorSketchesBuilt); err == nil {
+		closer.Close()
+		return nil
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+
+	r.visitorMu.Lock()
+	defer r.visitorMu.Unlock()
+
+	sketches := make(map[string]*domain.HyperLogLog)
+	prefix := []byte("analytics:event:")
+	iter, _ := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+	})
+	for iter.SeekGE(prefix); iter.Valid() && strings.HasPrefix(string(iter.Key()), string(prefix)); iter.Next() {
+		var event domain.AnalyticsEvent
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			continue
+		}
+		for _, key := range visitorSketchKeys(&event) {
+			sketch := sketches[string(key)]
+			if sketch == nil {
+				sketch = domain.NewHyperLogLog()
+				sketches[string(key)] = sketch
+			}
+			sketch.Add(event.VisitorID)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+	for key, sketch := range sketches {
+		data, err := sketch.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := batch.Set([]byte(key), data, pebble.Sync); err != nil {
+			return err
+		}
+	}
+	if err := batch.Set(visitorSketchesBuilt, []byte{}, pebble.Sync); err != nil {
+		return err
+	}
+	if len(sketches) > 0 {
+		log.Printf("[INFO] Built %d visitor sketches from stored events", len(sketches))
+	}
+	return batch.Commit(pebble.Sync)
+}
@@ -9,7 +9,7 @@ import (
 	"log"
 
 	"github.com/elchemista/driplnk/internal/domain"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type PostgresRepository struct {
@@ -58,10 +58,22 @@ func (r *PostgresRepository) Save(ctx context.Context, user *domain.User) error
 	if err != nil {
 		return fmt.Errorf("marshal theme: %w", err)
 	}
+	affiliateTags := user.AffiliateTags
+	if affiliateTags == nil {
+		affiliateTags = map[string]string{}
+	}
+	affiliateTagsBytes, err := json.Marshal(affiliateTags)
+	if err != nil {
+		return fmt.Errorf("marshal affiliate_tags: %w", err)
+	}
+	utmBytes, err := json.Marshal(user.UTM)
+	if err != nil {
+		return fmt.Errorf("marshal utm: %w", err)
+	}
 
 	query := `
-		INSERT INTO users (id, email, handle, title, description, avatar_url, seo_meta, theme, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO users (id, email, handle, title, description, avatar_url, seo_meta, theme, affiliate_tags, utm, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		ON CONFLICT (id) DO UPDATE SET
 			email = EXCLUDED.email,
 			handle = EXCLUDED.handle,
@@ -70,6 +82,8 @@ func (r *PostgresRepository) Save(ctx context.Context, user *domain.User) error
 			avatar_url = EXCLUDED.avatar_url,
 			seo_meta = EXCLUDED.seo_meta,
 			theme = EXCLUDED.theme,
+			affiliate_tags = EXCLUDED.affiliate_tags,
+			utm = EXCLUDED.utm,
 			updated_at = EXCLUDED.updated_at;
 	`
 
@@ -82,6 +96,8 @@ func (r *PostgresRepository) Save(ctx context.Context, user *domain.User) error
 		user.AvatarURL,
 		seoMetaBytes,
 		themeBytes,
+		affiliateTagsBytes,
+		utmBytes,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -102,13 +118,13 @@ func (r *PostgresRepository) GetByHandle(ctx context.Context, handle string) (*d
 
 func (r *PostgresRepository) getUserByField(ctx context.Context, field string, value interface{}) (*domain.User, error) {
 	query := fmt.Sprintf(`
-		SELECT id, email, handle, title, description, avatar_url, seo_meta, theme, created_at, updated_at
+		SELECT id, email, handle, title, description, avatar_url, seo_meta, theme, affiliate_tags, utm, created_at, updated_at
 		FROM users WHERE %s = $1`, field)
 
 	row := r.db.QueryRowContext(ctx, query, value)
 
 	var user domain.User
-	var seoMetaBytes, themeBytes []byte
+	var seoMetaBytes, themeBytes, affiliateTagsBytes, utmBytes []byte
 
 	err := row.Scan(
 		&user.ID,
@@ -119,6 +135,8 @@ func (r *PostgresRepository) getUserByField(ctx context.Context, field string, v
 		&user.AvatarURL,
 		&seoMetaBytes,
 		&themeBytes,
+		&affiliateTagsBytes,
+		&utmBytes,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -140,12 +158,22 @@ func (r *PostgresRepository) getUserByField(ctx context.Context, field string, v
 			return nil, fmt.Errorf("unmarshal theme: %w", err)
 		}
 	}
+	if len(affiliateTagsBytes) > 0 {
+		if err := json.Unmarshal(affiliateTagsBytes, &user.AffiliateTags); err != nil {
+			return nil, fmt.Errorf("unmarshal affiliate_tags: %w", err)
+		}
+	}
+	if len(utmBytes) > 0 {
+		if err := json.Unmarshal(utmBytes, &user.UTM); err != nil {
+			return nil, fmt.Errorf("unmarshal utm: %w", err)
+		}
+	}
 
 	return &user, nil
 }
 
 func (r *PostgresRepository) ListAll(ctx context.Context) ([]*domain.User, error) {
-	query := `SELECT id, email, handle, title, description, avatar_url, seo_meta, theme, created_at, updated_at FROM users ORDER BY created_at ASC`
+	query := `SELECT id, email, handle, title, description, avatar_url, seo_meta, theme, affiliate_tags, utm, created_at, updated_at FROM users ORDER BY created_at ASC`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -155,7 +183,7 @@ func (r *PostgresRepository) ListAll(ctx context.Context) ([]*domain.User, error
 	var users []*domain.User
 	for rows.Next() {
 		var user domain.User
-		var seoMetaBytes, themeBytes []byte
+		var seoMetaBytes, themeBytes, affiliateTagsBytes, utmBytes []byte
 		if err := rows.Scan(
 			&user.ID,
 			&user.Email,
@@ -165,6 +193,8 @@ func (r *PostgresRepository) ListAll(ctx context.Context) ([]*domain.User, error
 			&user.AvatarURL,
 			&seoMetaBytes,
 			&themeBytes,
+			&affiliateTagsBytes,
+			&utmBytes,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		); err != nil {
@@ -181,6 +211,16 @@ func (r *PostgresRepository) ListAll(ctx context.Context) ([]*domain.User, error
 				return nil, fmt.Errorf("unmarshal theme: %w", err)
 			}
 		}
+		if len(affiliateTagsBytes) > 0 {
+			if err := json.Unmarshal(affiliateTagsBytes, &user.AffiliateTags); err != nil {
+				return nil, fmt.Errorf("unmarshal affiliate_tags: %w", err)
+			}
+		}
+		if len(utmBytes) > 0 {
+			if err := json.Unmarshal(utmBytes, &user.UTM); err != nil {
+				return nil, fmt.Errorf("unmarshal utm: %w", err)
+			}
+		}
 		users = append(users, &user)
 	}
 
@@ -189,66 +229,215 @@ func (r *PostgresRepository) ListAll(ctx context.Context) ([]*domain.User, error
 
 // --- Link Repository ---
 
+// linkColumns lists the links table columns in the order scanLink expects them.
+const linkColumns = `id, user_id, slug, title, url, type, link_order, COALESCE(group_id::text, ''), is_active, metadata, click_count,
+		max_clicks, max_visitors, unique_visitors, starts_at, ends_at, fallback_url, variants, rules, protection, password_hash, product, health, utm,
+		deep_link, deleted_at, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLink(row rowScanner) (*domain.Link, error) {
+	var link domain.Link
+	var metadataBytes, variantsBytes, rulesBytes, productBytes, healthBytes, utmBytes []byte
+
+	if err := row.Scan(
+		&link.ID,
+		&link.UserID,
+		&link.Slug,
+		&link.Title,
+		&link.URL,
+		&link.Type,
+		&link.Order,
+		&link.GroupID,
+		&link.IsActive,
+		&metadataBytes,
+		&link.ClickCount,
+		&link.MaxClicks,
+		&link.MaxVisitors,
+		&link.UniqueVisitors,
+		&link.StartsAt,
+		&link.EndsAt,
+		&link.FallbackURL,
+		&variantsBytes,
+		&rulesBytes,
+		&link.Protection,
+		&link.PasswordHash,
+		&productBytes,
+		&healthBytes,
+		&utmBytes,
+		&link.DeepLink,
+		&link.DeletedAt,
+		&link.CreatedAt,
+		&link.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(metadataBytes) > 0 {
+		if err := json.Unmarshal(metadataBytes, &link.Metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal metadata: %w", err)
+		}
+	}
+	if len(variantsBytes) > 0 {
+		if err := json.Unmarshal(variantsBytes, &link.Variants); err != nil {
+			return nil, fmt.Errorf("unmarshal variants: %w", err)
+		}
+	}
+	if len(rulesBytes) > 0 {
+		if err := json.Unmarshal(rulesBytes, &link.Rules); err != nil {
+			return nil, fmt.Errorf("unmarshal rules: %w", err)
+		}
+	}
+	if len(productBytes) > 0 {
+		if err := json.Unmarshal(productBytes, &link.Product); err != nil {
+			return nil, fmt.Errorf("unmarshal product: %w", err)
+		}
+	}
+	if len(healthBytes) > 0 {
+		if err := json.Unmarshal(healthBytes, &link.Health); err != nil {
+			return nil, fmt.Errorf("unmarshal health: %w", err)
+		}
+	}
+	if len(utmBytes) > 0 {
+		if err := json.Unmarshal(utmBytes, &link.UTM); err != nil {
+			return nil, fmt.Errorf("unmarshal utm: %w", err)
+		}
+	}
+
+	return &link, nil
+}
+
 func (r *PostgresRepository) SaveLink(ctx context.Context, link *domain.Link) error {
 	metadataBytes, err := json.Marshal(link.Metadata)
 	if err != nil {
 		return fmt.Errorf("marshal metadata: %w", err)
 	}
+	variants := link.Variants
+	if variants == nil {
+		variants = []domain.LinkVariant{}
+	}
+	variantsBytes, err := json.Marshal(variants)
+	if err != nil {
+		return fmt.Errorf("marshal variants: %w", err)
+	}
+	rules := link.Rules
+	if rules == nil {
+		rules = []domain.RedirectRule{}
+	}
+	rulesBytes, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("marshal rules: %w", err)
+	}
+	// Non-product links store NULL
+	var product interface{}
+	if link.Product != nil {
+		productBytes, err := json.Marshal(link.Product)
+		if err != nil {
+			return fmt.Errorf("marshal product: %w", err)
+		}
+		product = productBytes
+	}
+	var health interface{}
+	if link.Health != nil {
+		healthBytes, err := json.Marshal(link.Health)
+		if err != nil {
+			return fmt.Errorf("marshal health: %w", err)
+		}
+		health = healthBytes
+	}
+	// Links using the owner's defaults store NULL
+	var utm interface{}
+	if link.UTM != nil {
+		utmBytes, err := json.Marshal(link.UTM)
+		if err != nil {
+			return fmt.Errorf("marshal utm: %w", err)
+		}
+		utm = utmBytes
+	}
 
+	// Counters are only written on insert; afterwards they only move through CountClick
 	query := `
-		INSERT INTO links (id, user_id, title, url, type, link_order, is_active, metadata, click_count, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO links (id, user_id, slug, title, url, type, link_order, group_id, is_active, metadata, click_count,
+			max_clicks, max_visitors, unique_visitors, starts_at, ends_at, fallback_url, variants, rules, protection, password_hash, product, health, utm,
+			deep_link, deleted_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::uuid, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
+			$25, $26, $27, $28)
 		ON CONFLICT (id) DO UPDATE SET
 			user_id = EXCLUDED.user_id,
+			slug = EXCLUDED.slug,
 			title = EXCLUDED.title,
 			url = EXCLUDED.url,
 			type = EXCLUDED.type,
 			link_order = EXCLUDED.link_order,
+			group_id = EXCLUDED.group_id,
 			is_active = EXCLUDED.is_active,
 			metadata = EXCLUDED.metadata,
-			click_count = EXCLUDED.click_count,
+			max_clicks = EXCLUDED.max_clicks,
+			max_visitors = EXCLUDED.max_visitors,
+			starts_at = EXCLUDED.starts_at,
+			ends_at = EXCLUDED.ends_at,
+			fallback_url = EXCLUDED.fallback_url,
+			variants = EXCLUDED.variants,
+			rules = EXCLUDED.rules,
+			protection = EXCLUDED.protection,
+			password_hash = EXCLUDED.password_hash,
+			product = EXCLUDED.product,
+			health = EXCLUDED.health,
+			utm = EXCLUDED.utm,
+			deep_link = EXCLUDED.deep_link,
+			deleted_at = EXCLUDED.deleted_at,
 			updated_at = EXCLUDED.updated_at;
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
 		link.ID,
 		link.UserID,
+		link.Slug,
 		link.Title,
 		link.URL,
 		link.Type,
 		link.Order,
+		string(link.GroupID),
 		link.IsActive,
 		metadataBytes,
 		link.ClickCount,
+		link.MaxClicks,
+		link.MaxVisitors,
+		link.UniqueVisitors,
+		link.StartsAt,
+		link.EndsAt,
+		link.FallbackURL,
+		variantsBytes,
+		rulesBytes,
+		link.Protection,
+		link.PasswordHash,
+		product,
+		health,
+		utm,
+		link.DeepLink,
+		link.DeletedAt,
 		link.CreatedAt,
 		link.UpdatedAt,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("slug %q is already in use: %w", link.Slug, domain.ErrConflict)
+	}
 	return err
 }
 
-func (r *PostgresRepository) GetLinkByID(ctx context.Context, id domain.LinkID) (*domain.Link, error) {
-	query := `
-		SELECT id, user_id, title, url, type, link_order, is_active, metadata, click_count, created_at, updated_at
-		FROM links WHERE id = $1
-	`
-	row := r.db.QueryRowContext(ctx, query, id)
+// isUniqueViolation reports whether err is a Postgres unique_violation (23505).
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
 
-	var link domain.Link
-	var metadataBytes []byte
+func (r *PostgresRepository) GetLinkByID(ctx context.Context, id domain.LinkID) (*domain.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM links WHERE id = $1`
 
-	err := row.Scan(
-		&link.ID,
-		&link.UserID,
-		&link.Title,
-		&link.URL,
-		&link.Type,
-		&link.Order,
-		&link.IsActive,
-		&metadataBytes,
-		&link.ClickCount,
-		&link.CreatedAt,
-		&link.UpdatedAt,
-	)
+	link, err := scanLink(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -256,21 +445,25 @@ func (r *PostgresRepository) GetLinkByID(ctx context.Context, id domain.LinkID)
 		return nil, err
 	}
 
-	if len(metadataBytes) > 0 {
-		if err := json.Unmarshal(metadataBytes, &link.Metadata); err != nil {
-			return nil, fmt.Errorf("unmarshal metadata: %w", err)
+	return link, nil
+}
+
+func (r *PostgresRepository) GetLinkBySlug(ctx context.Context, slug string) (*domain.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM links WHERE slug = $1`
+
+	link, err := scanLink(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
 		}
+		return nil, err
 	}
 
-	return &link, nil
+	return link, nil
 }
 
 func (r *PostgresRepository) ListLinksByUser(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
-	query := `
-		SELECT id, user_id, title, url, type, link_order, is_active, metadata, click_count, created_at, updated_at
-		FROM links WHERE user_id = $1
-		ORDER BY link_order ASC
-	`
+	query := `SELECT ` + linkColumns + ` FROM links WHERE user_id = $1 ORDER BY link_order ASC`
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
@@ -279,63 +472,211 @@ func (r *PostgresRepository) ListLinksByUser(ctx context.Context, userID domain.
 
 	var links []*domain.Link
 	for rows.Next() {
-		var link domain.Link
-		var metadataBytes []byte
-		if err := rows.Scan(
-			&link.ID,
-			&link.UserID,
-			&link.Title,
-			&link.URL,
-			&link.Type,
-			&link.Order,
-			&link.IsActive,
-			&metadataBytes,
-			&link.ClickCount,
-			&link.CreatedAt,
-			&link.UpdatedAt,
-		); err != nil {
+		link, err := scanLink(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		if len(metadataBytes) > 0 {
-			if err := json.Unmarshal(metadataBytes, &link.Metadata); err != nil {
-				return nil, fmt.Errorf("unmarshal metadata: %w", err)
-			}
-		}
-		links = append(links, &link)
+		links = append(links, link)
 	}
 
 	return links, nil
 }
 
+// Delete removes a link. Its analytics events stay in the owner's totals but
+// are detached from the link in the same transaction.
 func (r *PostgresRepository) Delete(ctx context.Context, id domain.LinkID) error {
-	query := `DELETE FROM links WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE analytics_events SET link_id = NULL WHERE link_id = $1`, string(id)); err != nil {
+		return fmt.Errorf("failed to detach analytics events: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM links WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CountClick locks the link row for the duration of the transaction, so
+// concurrent clicks cannot go over the link's limits.
+func (r *PostgresRepository) CountClick(ctx context.Context, id domain.LinkID, visitorID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var clicks, visitors, maxClicks, maxVisitors uint64
+	err = tx.QueryRowContext(ctx,
+		`SELECT click_count, unique_visitors, max_clicks, max_visitors FROM links WHERE id = $1 FOR UPDATE`, id,
+	).Scan(&clicks, &visitors, &maxClicks, &maxVisitors)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, err
+	}
+
+	newVisitor := true
+	if visitorID != "" {
+		var seen bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM link_visitors WHERE link_id = $1 AND visitor_id = $2)`, id, visitorID,
+		).Scan(&seen); err != nil {
+			return false, err
+		}
+		newVisitor = !seen
+	}
+	if maxClicks > 0 && clicks >= maxClicks {
+		return false, nil
+	}
+	if newVisitor && maxVisitors > 0 && visitors >= maxVisitors {
+		return false, nil
+	}
+
+	if newVisitor && visitorID != "" {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO link_visitors (link_id, visitor_id, created_at) VALUES ($1, $2, NOW())`, id, visitorID,
+		); err != nil {
+			return false, err
+		}
+	}
+	added := 0
+	if newVisitor {
+		added = 1
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE links SET click_count = click_count + 1, unique_visitors = unique_visitors + $2 WHERE id = $1`, id, added,
+	); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (r *PostgresRepository) Reorder(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error {
+// Reorder assigns each link the position of its placement and moves it into
+// the placement's group. IDs that do not belong to the user are skipped.
+func (r *PostgresRepository) Reorder(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	for i, id := range linkIDs {
+	for i, placement := range placements {
 		// Ensure the link belongs to the user to avoid cross-polluting
-		// Ideally we should check ownership
-		query := `UPDATE links SET link_order = $1 WHERE id = $2 AND user_id = $3`
-		res, err := tx.ExecContext(ctx, query, i, id, userID)
-		if err != nil {
+		query := `UPDATE links SET link_order = $1, group_id = NULLIF($2, '')::uuid WHERE id = $3 AND user_id = $4`
+		if _, err := tx.ExecContext(ctx, query, i, string(placement.GroupID), placement.LinkID, userID); err != nil {
 			return err
 		}
-		rowsHub, err := res.RowsAffected()
+	}
+
+	return tx.Commit()
+}
+
+// --- Link Group Repository ---
+
+const groupColumns = `id, user_id, title, description, collapsed, group_order, created_at, updated_at`
+
+func scanGroup(row rowScanner) (*domain.LinkGroup, error) {
+	var group domain.LinkGroup
+	if err := row.Scan(
+		&group.ID,
+		&group.UserID,
+		&group.Title,
+		&group.Description,
+		&group.Collapsed,
+		&group.Order,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *PostgresRepository) SaveGroup(ctx context.Context, group *domain.LinkGroup) error {
+	query := `
+		INSERT INTO link_groups (id, user_id, title, description, collapsed, group_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			collapsed = EXCLUDED.collapsed,
+			group_order = EXCLUDED.group_order,
+			updated_at = EXCLUDED.updated_at;
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		group.ID,
+		group.UserID,
+		group.Title,
+		group.Description,
+		group.Collapsed,
+		group.Order,
+		group.CreatedAt,
+		group.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresRepository) GetGroupByID(ctx context.Context, id domain.LinkGroupID) (*domain.LinkGroup, error) {
+	query := `SELECT ` + groupColumns + ` FROM link_groups WHERE id = $1`
+
+	group, err := scanGroup(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+func (r *PostgresRepository) ListGroupsByUser(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	query := `SELECT ` + groupColumns + ` FROM link_groups WHERE user_id = $1 ORDER BY group_order ASC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*domain.LinkGroup
+	for rows.Next() {
+		group, err := scanGroup(rows)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if rowsHub == 0 {
-			// Might want to log specific ID not found or not owned
-			continue
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+// DeleteGroup removes the group; the foreign key ungroups its links.
+func (r *PostgresRepository) DeleteGroup(ctx context.Context, id domain.LinkGroupID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM link_groups WHERE id = $1`, id)
+	return err
+}
+
+// ReorderGroups assigns each group its position in groupIDs.
+// IDs that do not belong to the user are skipped.
+func (r *PostgresRepository) ReorderGroups(ctx context.Context, userID domain.UserID, groupIDs []domain.LinkGroupID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, id := range groupIDs {
+		query := `UPDATE link_groups SET group_order = $1 WHERE id = $2 AND user_id = $3`
+		if _, err := tx.ExecContext(ctx, query, i, id, userID); err != nil {
+			return err
 		}
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
 )
@@ -17,8 +18,8 @@ func (r *PostgresRepository) SaveEvent(ctx context.Context, event *domain.Analyt
 	}
 
 	query := `
-		INSERT INTO analytics_events (event_type, link_id, user_id, visitor_id, country, region, meta, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO analytics_events (event_type, link_id, user_id, visitor_id, country, region, is_bot, meta, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -28,6 +29,7 @@ func (r *PostgresRepository) SaveEvent(ctx context.Context, event *domain.Analyt
 		event.VisitorID,
 		event.Country,
 		event.Region,
+		event.IsBot,
 		metaBytes,
 		event.CreatedAt,
 	)
@@ -40,39 +42,53 @@ func (r *PostgresRepository) SaveEvent(ctx context.Context, event *domain.Analyt
 // GetSummary returns aggregated stats for a user.
 func (r *PostgresRepository) GetSummary(ctx context.Context, userID string, linkID *string) (*domain.AnalyticsSummary, error) {
 	summary := &domain.AnalyticsSummary{
-		ByCountry: make(map[string]int64),
-		ByDevice:  make(map[string]int64),
+		ByCountry:  make(map[string]int64),
+		ByDevice:   make(map[string]int64),
+		ByReferrer: make(map[string]int64),
+		BySource:   make(map[string]int64),
+		ByCampaign: make(map[string]int64),
 	}
 
 	// Base filter
 	filter := "user_id = $1"
 	args := []interface{}{userID}
+	// Splits count profile views, or clicks when looking at a single link
+	splitType := domain.EventTypeView
 	if linkID != nil {
 		filter += " AND link_id = $2"
 		args = append(args, *linkID)
+		splitType = domain.EventTypeClick
 	}
 
-	// 1. Counts (Views and Clicks)
+	// 1. Counts (Views, Clicks and unique visitors; bots apart)
 	queryCounts := fmt.Sprintf(`
 		SELECT 
-			COUNT(*) FILTER (WHERE event_type = 'view'),
-			COUNT(*) FILTER (WHERE event_type = 'click')
+			COUNT(*) FILTER (WHERE event_type = 'view' AND NOT is_bot),
+			COUNT(*) FILTER (WHERE event_type = 'click' AND NOT is_bot),
+			COUNT(*) FILTER (WHERE event_type = 'view' AND NOT is_bot AND meta->>'source' = 'qr'),
+			COUNT(*) FILTER (WHERE event_type = 'click' AND NOT is_bot AND meta->>'source' = 'qr'),
+			COUNT(DISTINCT visitor_id) FILTER (WHERE event_type IN ('view', 'click') AND NOT is_bot),
+			COUNT(*) FILTER (WHERE event_type = 'view' AND is_bot),
+			COUNT(*) FILTER (WHERE event_type = 'click' AND is_bot)
 		FROM analytics_events
 		WHERE %s
 	`, filter)
 
-	err := r.db.QueryRowContext(ctx, queryCounts, args...).Scan(&summary.TotalViews, &summary.TotalClicks)
+	err := r.db.QueryRowContext(ctx, queryCounts, args...).Scan(&summary.TotalViews, &summary.TotalClicks, &summary.QRViews, &summary.QRClicks, &summary.UniqueVisitors, &summary.BotViews, &summary.BotClicks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get counts: %w", err)
 	}
 
+	// The splits below only count real visitors
+	filter += " AND NOT is_bot"
+
 	// 2. Group by Country
 	queryCountry := fmt.Sprintf(`
 		SELECT country, COUNT(*)
 		FROM analytics_events
-		WHERE %s AND country != '' AND event_type = 'view'
+		WHERE %s AND country != '' AND event_type = '%s'
 		GROUP BY country
-	`, filter)
+	`, filter, splitType)
 
 	rows, err := r.db.QueryContext(ctx, queryCountry, args...)
 	if err != nil {
@@ -97,9 +113,9 @@ func (r *PostgresRepository) GetSummary(ctx context.Context, userID string, link
 	queryDevice := fmt.Sprintf(`
 		SELECT meta->>'device_type', COUNT(*)
 		FROM analytics_events
-		WHERE %s AND meta->>'device_type' IS NOT NULL AND event_type = 'view'
+		WHERE %s AND meta->>'device_type' IS NOT NULL AND event_type = '%s'
 		GROUP BY meta->>'device_type'
-	`, filter)
+	`, filter, splitType)
 
 	rowsDevice, err := r.db.QueryContext(ctx, queryDevice, args...)
 	if err == nil {
@@ -116,5 +132,195 @@ func (r *PostgresRepository) GetSummary(ctx context.Context, userID string, link
 		log.Printf("[DEBUG] Failed to get device stats (expected if no data): %v", err)
 	}
 
+	// 4. Group by Referrer, traffic source and campaign
+	for key, counts := range map[string]map[string]int64{
+		"referrer":     summary.ByReferrer,
+		"source":       summary.BySource,
+		"utm_campaign": summary.ByCampaign,
+	} {
+		if err := r.countByMeta(ctx, counts, key, filter, splitType, args); err != nil {
+			return nil, err
+		}
+	}
+
 	return summary, nil
 }
+
+// countByMeta counts the events of splitType matching filter per value of a meta key.
+func (r *PostgresRepository) countByMeta(ctx context.Context, counts map[string]int64, key, filter string, splitType domain.AnalyticsEventType, args []interface{}) error {
+	query := fmt.Sprintf(`
+		SELECT meta->>'%[1]s', COUNT(*)
+		FROM analytics_events
+		WHERE %[2]s AND meta->>'%[1]s' IS NOT NULL AND meta->>'%[1]s' != '' AND event_type = '%[3]s'
+		GROUP BY meta->>'%[1]s'
+	`, key, filter, splitType)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get %s stats: %w", key, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			log.Printf("[WARN] Failed to scan %s row: %v", key, err)
+			continue
+		}
+		counts[value] = count
+	}
+	return rows.Err()
+}
+
+// GetVariantStats returns clicks and CTR per A/B variant of a link.
+func (r *PostgresRepository) GetVariantStats(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error) {
+	viewsByVisitor := make(map[string]int64)
+	clicksByVariant := make(map[string]int64)
+
+	// 1. Profile views per visitor (attributed to variants in Go, like the redirect does)
+	queryViews := `
+		SELECT visitor_id, COUNT(*)
+		FROM analytics_events
+		WHERE user_id = $1 AND event_type = 'view' AND NOT is_bot
+		GROUP BY visitor_id
+	`
+	rows, err := r.db.QueryContext(ctx, queryViews, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var visitorID string
+		var count int64
+		if err := rows.Scan(&visitorID, &count); err != nil {
+			log.Printf("[WARN] Failed to scan variant view row: %v", err)
+			continue
+		}
+		viewsByVisitor[visitorID] = count
+	}
+
+	// 2. Clicks per variant
+	queryClicks := `
+		SELECT meta->>'variant', COUNT(*)
+		FROM analytics_events
+		WHERE user_id = $1 AND link_id = $2 AND event_type = 'click' AND NOT is_bot AND meta->>'variant' IS NOT NULL
+		GROUP BY meta->>'variant'
+	`
+	rowsClicks, err := r.db.QueryContext(ctx, queryClicks, userID, string(link.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant clicks: %w", err)
+	}
+	defer rowsClicks.Close()
+
+	for rowsClicks.Next() {
+		var variant string
+		var count int64
+		if err := rowsClicks.Scan(&variant, &count); err != nil {
+			log.Printf("[WARN] Failed to scan variant click row: %v", err)
+			continue
+		}
+		clicksByVariant[variant] = count
+	}
+
+	return domain.BuildVariantStats(link, viewsByVisitor, clicksByVariant), nil
+}
+
+// GetTimeSeries returns views, clicks and unique visitors per bucket of the query's range.
+// Buckets are computed in UTC with date_trunc so they line up with domain.TimeBucket.
+func (r *PostgresRepository) GetTimeSeries(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error) {
+	series := domain.NewTimeSeries(query)
+
+	filter := "user_id = $1 AND created_at >= $2 AND created_at < $3 AND event_type IN ('view', 'click') AND NOT is_bot"
+	args := []interface{}{query.UserID, query.From, query.To}
+	if query.LinkID != nil {
+		filter += " AND link_id = $4"
+		args = append(args, *query.LinkID)
+	}
+
+	// 1. Counts per bucket
+	queryBuckets := fmt.Sprintf(`
+		SELECT
+			date_trunc('%s', created_at AT TIME ZONE 'UTC'),
+			COUNT(*) FILTER (WHERE event_type = 'view'),
+			COUNT(*) FILTER (WHERE event_type = 'click'),
+			COUNT(DISTINCT visitor_id)
+		FROM analytics_events
+		WHERE %s
+		GROUP BY 1
+	`, query.Bucket, filter)
+
+	rows, err := r.db.QueryContext(ctx, queryBuckets, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time series: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start time.Time
+		var views, clicks, visitors int64
+		if err := rows.Scan(&start, &views, &clicks, &visitors); err != nil {
+			log.Printf("[WARN] Failed to scan time series row: %v", err)
+			continue
+		}
+		// date_trunc returns a timestamp without time zone holding the UTC wall clock
+		start = time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, time.UTC)
+		if point := series.Point(start); point != nil {
+			point.Views = views
+			point.Clicks = clicks
+			point.UniqueVisitors = visitors
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read time series: %w", err)
+	}
+
+	// 2. Unique visitors over the whole range (not the sum of the buckets)
+	queryVisitors := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT visitor_id)
+		FROM analytics_events
+		WHERE %s
+	`, filter)
+	if err := r.db.QueryRowContext(ctx, queryVisitors, args...).Scan(&series.UniqueVisitors); err != nil {
+		return nil, fmt.Errorf("failed to get unique visitors: %w", err)
+	}
+
+	return series, nil
+}
+
+// GetTopLinks returns the user's most clicked links, most clicks first.
+func (r *PostgresRepository) GetTopLinks(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error) {
+	query := `
+		SELECT link_id, COUNT(*), COUNT(DISTINCT visitor_id)
+		FROM analytics_events
+		WHERE user_id = $1 AND event_type = 'click' AND NOT is_bot AND link_id IS NOT NULL
+		GROUP BY link_id
+		ORDER BY 2 DESC, link_id
+	`
+	args := []interface{}{userID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top links: %w", err)
+	}
+	defer rows.Close()
+
+	var top []domain.LinkClicks
+	for rows.Next() {
+		var lc domain.LinkClicks
+		if err := rows.Scan(&lc.LinkID, &lc.Clicks, &lc.UniqueVisitors); err != nil {
+			log.Printf("[WARN] Failed to scan top link row: %v", err)
+			continue
+		}
+		top = append(top, lc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read top links: %w", err)
+	}
+	return top, nil
+}
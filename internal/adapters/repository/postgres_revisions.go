@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+const revisionColumns = `id, link_id, user_id, actor_id, action, before_snapshot, after_snapshot, created_at`
+
+// SaveRevision appends a revision. Revisions are immutable, so there is no upsert.
+func (r *PostgresRepository) SaveRevision(ctx context.Context, rev *domain.LinkRevision) error {
+	before, err := marshalSnapshot(rev.Before)
+	if err != nil {
+		return fmt.Errorf("marshal before: %w", err)
+	}
+	after, err := marshalSnapshot(rev.After)
+	if err != nil {
+		return fmt.Errorf("marshal after: %w", err)
+	}
+
+	query := `
+		INSERT INTO link_revisions (id, link_id, user_id, actor_id, action, before_snapshot, after_snapshot, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		rev.ID,
+		rev.LinkID,
+		rev.UserID,
+		rev.ActorID,
+		rev.Action,
+		before,
+		after,
+		rev.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("revision %s already exists: %w", rev.ID, domain.ErrConflict)
+	}
+	return err
+}
+
+// marshalSnapshot encodes a link snapshot, keeping nil as SQL NULL.
+func marshalSnapshot(link *domain.Link) (interface{}, error) {
+	if link == nil {
+		return nil, nil
+	}
+	return json.Marshal(link)
+}
+
+func scanRevision(row rowScanner) (*domain.LinkRevision, error) {
+	var rev domain.LinkRevision
+	var beforeBytes, afterBytes []byte
+	if err := row.Scan(
+		&rev.ID,
+		&rev.LinkID,
+		&rev.UserID,
+		&rev.ActorID,
+		&rev.Action,
+		&beforeBytes,
+		&afterBytes,
+		&rev.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(beforeBytes) > 0 {
+		if err := json.Unmarshal(beforeBytes, &rev.Before); err != nil {
+			return nil, fmt.Errorf("unmarshal before: %w", err)
+		}
+	}
+	if len(afterBytes) > 0 {
+		if err := json.Unmarshal(afterBytes, &rev.After); err != nil {
+			return nil, fmt.Errorf("unmarshal after: %w", err)
+		}
+	}
+	return &rev, nil
+}
+
+func (r *PostgresRepository) GetRevisionByID(ctx context.Context, id domain.LinkRevisionID) (*domain.LinkRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM link_revisions WHERE id = $1`
+
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (r *PostgresRepository) ListRevisionsByLink(ctx context.Context, linkID domain.LinkID) ([]*domain.LinkRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM link_revisions WHERE link_id = $1 ORDER BY created_at DESC`
+	return r.queryRevisions(ctx, query, linkID)
+}
+
+// ListRevisionsByUser returns the newest revisions of the user's links. A limit of 0 returns all of them.
+func (r *PostgresRepository) ListRevisionsByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM link_revisions WHERE user_id = $1 ORDER BY created_at DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return r.queryRevisions(ctx, query, userID)
+}
+
+func (r *PostgresRepository) queryRevisions(ctx context.Context, query string, args ...interface{}) ([]*domain.LinkRevision, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*domain.LinkRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
@@ -0,0 +1,241 @@
+package safety
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+	"golang.org/x/net/idna"
+)
+
+// defaultSchemes are allowed when the config does not list any.
+var defaultSchemes = []string{"https", "http", "mailto", "tel"}
+
+// reloadInterval is how often the blocklist file is checked for changes.
+const reloadInterval = 30 * time.Second
+
+type SafetyAdapter struct {
+	schemes map[string]bool
+	blocked map[string]bool // from the JSON config
+	path    string          // optional blocklist file
+
+	mu        sync.RWMutex
+	fileList  map[string]bool
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewSafetyAdapter builds the checker from cfg. blocklistPath is the resolved
+// path of cfg.BlocklistFile, or empty when there is none. The file is re-read
+// when it changes so blocklist updates apply to existing links without a restart.
+func NewSafetyAdapter(cfg config.LinkSafetyConfig, blocklistPath string) *SafetyAdapter {
+	schemes := cfg.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = defaultSchemes
+	}
+	a := &SafetyAdapter{
+		schemes: make(map[string]bool),
+		blocked: make(map[string]bool),
+		path:    blocklistPath,
+	}
+	for _, scheme := range schemes {
+		a.schemes[strings.ToLower(strings.TrimSpace(scheme))] = true
+	}
+	for _, d := range cfg.BlockedDomains {
+		if host, err := normalizeHost(d); err == nil && host != "" {
+			a.blocked[host] = true
+		}
+	}
+	a.reload()
+	return a
+}
+
+func (a *SafetyAdapter) Check(rawURL string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("malformed URL: %w", domain.ErrUnsafeURL)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !a.schemes[scheme] {
+		return fmt.Errorf("scheme %q is not allowed: %w", scheme, domain.ErrUnsafeURL)
+	}
+
+	var hosts []string
+	switch scheme {
+	case "mailto":
+		// mailto:a@example.com,b@example.org
+		for _, addr := range strings.Split(u.Opaque, ",") {
+			if at := strings.LastIndex(addr, "@"); at >= 0 {
+				if host, err := url.PathUnescape(addr[at+1:]); err == nil {
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	case "tel":
+	default:
+		if u.Hostname() == "" {
+			return fmt.Errorf("URL has no host: %w", domain.ErrUnsafeURL)
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+
+	for _, raw := range hosts {
+		host, err := normalizeHost(raw)
+		if err != nil {
+			return fmt.Errorf("invalid domain %q: %w", raw, domain.ErrUnsafeURL)
+		}
+		if a.isBlocked(host) {
+			return fmt.Errorf("domain %q is blocklisted: %w", host, domain.ErrUnsafeURL)
+		}
+		if isSpoofed(host) {
+			return fmt.Errorf("domain %q imitates another domain: %w", raw, domain.ErrUnsafeURL)
+		}
+	}
+	return nil
+}
+
+// isBlocked reports whether host or one of its parent domains is blocklisted.
+func (a *SafetyAdapter) isBlocked(host string) bool {
+	a.maybeReload()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for d := host; d != ""; {
+		if a.blocked[d] || a.fileList[d] {
+			return true
+		}
+		dot := strings.IndexByte(d, '.')
+		if dot < 0 {
+			break
+		}
+		d = d[dot+1:]
+	}
+	return false
+}
+
+// maybeReload re-reads the blocklist file if it changed since the last load,
+// checking at most once per reloadInterval.
+func (a *SafetyAdapter) maybeReload() {
+	if a.path == "" {
+		return
+	}
+	a.mu.RLock()
+	due := time.Since(a.checkedAt) >= reloadInterval
+	a.mu.RUnlock()
+	if due {
+		a.reload()
+	}
+}
+
+func (a *SafetyAdapter) reload() {
+	if a.path == "" {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checkedAt = time.Now()
+
+	info, err := os.Stat(a.path)
+	if err != nil {
+		log.Printf("[WARN] Failed to read link blocklist %s: %v", a.path, err)
+		return
+	}
+	if info.ModTime().Equal(a.modTime) {
+		return
+	}
+
+	f, err := os.Open(a.path)
+	if err != nil {
+		log.Printf("[WARN] Failed to read link blocklist %s: %v", a.path, err)
+		return
+	}
+	defer f.Close()
+
+	list := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if host, err := normalizeHost(line); err == nil && host != "" {
+			list[host] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("[WARN] Failed to read link blocklist %s: %v", a.path, err)
+		return
+	}
+
+	a.fileList = list
+	a.modTime = info.ModTime()
+	log.Printf("[INFO] Loaded %d blocklisted domains from %s", len(list), a.path)
+}
+
+// normalizeHost returns the lowercase ASCII (punycode) form of a host name.
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	return idna.Lookup.ToASCII(host)
+}
+
+// latinLookalikes are Cyrillic and Greek letters that render like Latin ones.
+var latinLookalikes = map[rune]bool{
+	'а': true, 'в': true, 'е': true, 'к': true, 'м': true, 'н': true, 'о': true, 'р': true,
+	'с': true, 'т': true, 'у': true, 'х': true, 'ѕ': true, 'і': true, 'ј': true, 'ӏ': true,
+	'ԁ': true, 'ԛ': true, 'ԝ': true, 'ү': true, 'һ': true, 'ԍ': true,
+	'α': true, 'ε': true, 'ι': true, 'κ': true, 'ν': true, 'ο': true, 'ρ': true, 'τ': true,
+	'υ': true, 'χ': true,
+}
+
+// isSpoofed reports whether an ASCII host decodes to a homograph: a label that
+// mixes Latin with Cyrillic or Greek letters (pаypal with a Cyrillic а), or one
+// written only in Cyrillic or Greek letters that look Latin (аррӏе).
+func isSpoofed(asciiHost string) bool {
+	if !strings.Contains(asciiHost, "xn--") {
+		return false
+	}
+	host, err := idna.Lookup.ToUnicode(asciiHost)
+	if err != nil {
+		return true
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		var latin, cyrillic, greek bool
+		lookalikesOnly := true
+		for _, r := range label {
+			switch {
+			case r < unicode.MaxASCII:
+				if unicode.IsLetter(r) {
+					latin = true
+				}
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic = true
+				lookalikesOnly = lookalikesOnly && latinLookalikes[r]
+			case unicode.Is(unicode.Greek, r):
+				greek = true
+				lookalikesOnly = lookalikesOnly && latinLookalikes[r]
+			case unicode.IsLetter(r):
+				lookalikesOnly = false
+			}
+		}
+
+		scripts := 0
+		for _, used := range []bool{latin, cyrillic, greek} {
+			if used {
+				scripts++
+			}
+		}
+		if scripts > 1 || (scripts == 1 && !latin && lookalikesOnly) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package safety_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/safety"
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+func TestSafetyAdapter_Check(t *testing.T) {
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte("# comment\n\nmalware.example\nbücher.example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	adapter := safety.NewSafetyAdapter(config.LinkSafetyConfig{
+		AllowedSchemes: []string{"https", "http", "mailto", "tel"},
+		BlockedDomains: []string{"Phish.Example."},
+	}, blocklist)
+
+	tests := []struct {
+		name string
+		url  string
+		safe bool
+	}{
+		{"plain https", "https://example.com/page", true},
+		{"tel", "tel:+15551234567", true},
+		{"mailto", "mailto:hello@example.com", true},
+		{"non-latin domain", "https://例え.jp", true},
+		{"javascript scheme", "javascript:alert(1)", false},
+		{"data scheme", "data:text/html,hi", false},
+		{"missing host", "https:///path", false},
+		{"config blocklist", "https://phish.example/login", false},
+		{"file blocklist", "http://malware.example", false},
+		{"blocked subdomain", "https://cdn.malware.example/x.exe", false},
+		{"similar name is not a subdomain", "https://notmalware.example", true},
+		{"blocked unicode entry by punycode", "https://xn--bcher-kva.example", false},
+		{"blocked mailto domain", "mailto:a@example.com,b@phish.example", false},
+		{"mixed script", "https://pаypal.com", false},
+		{"mixed script punycode", "https://xn--pypal-4ve.com", false},
+		{"cyrillic lookalikes only", "https://аррӏе.com", false},
+		{"cyrillic word", "https://пример.рф", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := adapter.Check(tt.url)
+			if tt.safe && err != nil {
+				t.Errorf("expected %s to be allowed, got %v", tt.url, err)
+			}
+			if !tt.safe && !errors.Is(err, domain.ErrUnsafeURL) {
+				t.Errorf("expected %s to be rejected as unsafe, got %v", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestSafetyAdapter_DefaultSchemes(t *testing.T) {
+	adapter := safety.NewSafetyAdapter(config.LinkSafetyConfig{}, "")
+
+	if err := adapter.Check("https://example.com"); err != nil {
+		t.Errorf("expected https to be allowed by default, got %v", err)
+	}
+	if err := adapter.Check("ftp://example.com"); !errors.Is(err, domain.ErrUnsafeURL) {
+		t.Errorf("expected ftp to be rejected by default, got %v", err)
+	}
+}
@@ -3,22 +3,27 @@ package seo
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
+	"github.com/elchemista/driplnk/internal/config"
 	"github.com/elchemista/driplnk/internal/domain"
 	"golang.org/x/net/html"
 )
 
 type HTMLFetcher struct {
-	client *http.Client
+	client    *http.Client
+	providers []embedProvider
 }
 
-func NewHTMLFetcher() *HTMLFetcher {
+// NewHTMLFetcher creates a fetcher; embedProviders enables oEmbed discovery for matching URLs.
+func NewHTMLFetcher(embedProviders []config.EmbedProviderConfig) *HTMLFetcher {
 	return &HTMLFetcher{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		providers: compileEmbedProviders(embedProviders),
 	}
 }
 
@@ -48,6 +53,13 @@ func (f *HTMLFetcher) Fetch(ctx context.Context, url string) (*domain.LinkMetada
 	meta := &domain.LinkMetadata{URL: url}
 	extractMeta(doc, meta)
 
+	// Embeds are optional: a failing provider still leaves the OG metadata usable
+	if provider := f.matchEmbedProvider(url); provider != nil {
+		if err := f.extractEmbed(ctx, provider, url, doc, meta); err != nil {
+			log.Printf("[WARN] Failed to resolve embed for %s: %v", url, err)
+		}
+	}
+
 	return meta, nil
 }
 
@@ -0,0 +1,106 @@
+package seo_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/seo"
+	"github.com/elchemista/driplnk/internal/config"
+)
+
+func newProviderServer(t *testing.T, iframeHost string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var oembedCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/video/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head>
+			<meta property="og:title" content="My Video">
+			<title>Video</title>
+			<link rel="alternate" type="application/json+oembed" href="/oembed?id=1">
+		</head></html>`)
+	})
+	mux.HandleFunc("/post/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Post</title></head></html>`)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Plain</title></head></html>`)
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		oembedCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("id") == "1" {
+			fmt.Fprintf(w, `{"type":"video","width":640,"height":"360","html":"<iframe src=\"https://%s/embed/1\" onload=\"alert(1)\"></iframe>"}`, iframeHost)
+			return
+		}
+		fmt.Fprint(w, `{"type":"rich","width":550,"html":"<blockquote><p>Hello</p></blockquote><script src=\"https://x.test/widgets.js\"></script>"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &oembedCalls
+}
+
+func TestHTMLFetcher_Embeds(t *testing.T) {
+	ctx := context.Background()
+	srv, calls := newProviderServer(t, "player.example.com")
+	fetcher := seo.NewHTMLFetcher([]config.EmbedProviderConfig{
+		{Name: "Videos", RegexPattern: `/video/`, IframeHosts: []string{"player.example.com"}},
+		{Name: "Posts", RegexPattern: `/post/`, Endpoint: srv.URL + "/oembed"},
+	})
+
+	t.Run("discovers oembed link and keeps allowed iframe", func(t *testing.T) {
+		meta, err := fetcher.Fetch(ctx, srv.URL+"/video/1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if meta.Title != "My Video" {
+			t.Errorf("expected OG title, got %q", meta.Title)
+		}
+		if meta.EmbedURL != "https://player.example.com/embed/1" || meta.EmbedHTML != "" {
+			t.Errorf("unexpected embed %q / %q", meta.EmbedURL, meta.EmbedHTML)
+		}
+		if meta.EmbedProvider != "Videos" || meta.EmbedWidth != 640 || meta.EmbedHeight != 360 {
+			t.Errorf("unexpected embed info %+v", meta)
+		}
+	})
+
+	t.Run("uses configured endpoint and sanitizes markup", func(t *testing.T) {
+		meta, err := fetcher.Fetch(ctx, srv.URL+"/post/1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if meta.EmbedHTML != "<blockquote><p>Hello</p></blockquote>" {
+			t.Errorf("expected sanitized markup, got %q", meta.EmbedHTML)
+		}
+	})
+
+	t.Run("skips oembed for unknown providers", func(t *testing.T) {
+		before := calls.Load()
+		meta, err := fetcher.Fetch(ctx, srv.URL+"/plain")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if meta.EmbedURL != "" || meta.EmbedHTML != "" || calls.Load() != before {
+			t.Errorf("expected no embed lookup, got %+v", meta)
+		}
+	})
+}
+
+func TestHTMLFetcher_RejectsUnlistedIframeHost(t *testing.T) {
+	srv, _ := newProviderServer(t, "evil.example.com")
+	fetcher := seo.NewHTMLFetcher([]config.EmbedProviderConfig{
+		{Name: "Videos", RegexPattern: `/video/`, IframeHosts: []string{"player.example.com"}},
+	})
+
+	meta, err := fetcher.Fetch(context.Background(), srv.URL+"/video/1")
+	if err != nil {
+		t.Fatalf("expected OG metadata despite the rejected embed, got %v", err)
+	}
+	if meta.EmbedURL != "" || strings.Contains(meta.EmbedHTML, "evil") {
+		t.Errorf("expected iframe to be rejected, got %q / %q", meta.EmbedURL, meta.EmbedHTML)
+	}
+}
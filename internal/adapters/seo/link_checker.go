@@ -0,0 +1,58 @@
+package seo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// maxCheckBodySize bounds how much of a GET fallback body is drained.
+const maxCheckBodySize = 64 << 10
+
+// Check implements domain.LinkChecker with a HEAD request, falling back to GET
+// for servers that reject or mishandle HEAD.
+func (f *HTMLFetcher) Check(ctx context.Context, url string) *domain.LinkHealth {
+	health := &domain.LinkHealth{CheckedAt: time.Now()}
+
+	resp, err := f.checkRequest(ctx, http.MethodHead, url)
+	if err != nil || headUnsupported(resp.StatusCode) {
+		resp, err = f.checkRequest(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+
+	health.StatusCode = resp.StatusCode
+	health.FinalURL = resp.Request.URL.String()
+	return health
+}
+
+func (f *HTMLFetcher) checkRequest(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Driplnk-Bot/1.0")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Drain a little so the connection can be reused, then close
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxCheckBodySize))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// headUnsupported reports statuses that often mean "HEAD not allowed" rather than a dead page.
+func headUnsupported(status int) bool {
+	switch status {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusForbidden, http.StatusBadRequest, http.StatusNotFound:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package seo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/seo"
+)
+
+func TestHTMLFetcher_Check(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	fetcher := seo.NewHTMLFetcher(nil)
+	ctx := context.Background()
+
+	t.Run("falls back to GET", func(t *testing.T) {
+		h := fetcher.Check(ctx, srv.URL+"/no-head")
+		if h.StatusCode != http.StatusOK || h.IsBroken() {
+			t.Errorf("expected 200 via GET, got %+v", h)
+		}
+	})
+
+	t.Run("records final URL", func(t *testing.T) {
+		h := fetcher.Check(ctx, srv.URL+"/moved")
+		if h.StatusCode != http.StatusOK || h.FinalURL != srv.URL+"/ok" {
+			t.Errorf("expected redirect to /ok, got %+v", h)
+		}
+		if h.CheckedAt.IsZero() {
+			t.Error("expected check time")
+		}
+	})
+
+	t.Run("broken status", func(t *testing.T) {
+		h := fetcher.Check(ctx, srv.URL+"/gone")
+		if h.StatusCode != http.StatusGone || !h.IsBroken() {
+			t.Errorf("expected broken 410, got %+v", h)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		h := fetcher.Check(ctx, "http://127.0.0.1:1/")
+		if h.Error == "" || !h.IsBroken() {
+			t.Errorf("expected network error, got %+v", h)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package seo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/sanitizer"
+	"golang.org/x/net/html"
+)
+
+// maxOEmbedResponseSize bounds the oEmbed JSON we are willing to read.
+const maxOEmbedResponseSize = 1 << 20
+
+type embedProvider struct {
+	name        string
+	regex       *regexp.Regexp
+	endpoint    string
+	iframeHosts map[string]bool
+}
+
+func compileEmbedProviders(configs []config.EmbedProviderConfig) []embedProvider {
+	var providers []embedProvider
+	for _, cfg := range configs {
+		regex, err := regexp.Compile(cfg.RegexPattern)
+		if err != nil {
+			// Skip invalid regex, same as the social adapter
+			continue
+		}
+		hosts := make(map[string]bool, len(cfg.IframeHosts))
+		for _, h := range cfg.IframeHosts {
+			hosts[strings.ToLower(h)] = true
+		}
+		providers = append(providers, embedProvider{
+			name:        cfg.Name,
+			regex:       regex,
+			endpoint:    cfg.Endpoint,
+			iframeHosts: hosts,
+		})
+	}
+	return providers
+}
+
+func (f *HTMLFetcher) matchEmbedProvider(pageURL string) *embedProvider {
+	for i := range f.providers {
+		if f.providers[i].regex.MatchString(pageURL) {
+			return &f.providers[i]
+		}
+	}
+	return nil
+}
+
+// oEmbedResponse is the subset of the oEmbed 1.0 response we use.
+type oEmbedResponse struct {
+	Type   string  `json:"type"`
+	HTML   string  `json:"html"`
+	Width  flexInt `json:"width"`
+	Height flexInt `json:"height"`
+}
+
+// flexInt accepts both numbers and numeric strings; providers disagree on the type.
+type flexInt int
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		// Values like "100%" carry no usable size
+		*n = 0
+		return nil
+	}
+	*n = flexInt(v)
+	return nil
+}
+
+// extractEmbed resolves the oEmbed endpoint for a page of a known provider and stores
+// an allow-listed iframe src or sanitized markup in meta. The endpoint advertised by the
+// page wins over the configured one.
+func (f *HTMLFetcher) extractEmbed(ctx context.Context, provider *embedProvider, pageURL string, doc *html.Node, meta *domain.LinkMetadata) error {
+	endpoint, err := oEmbedEndpoint(provider, pageURL, doc)
+	if err != nil {
+		return err
+	}
+
+	data, err := f.fetchOEmbed(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if src := iframeSrc(data.HTML); src != "" {
+		if !provider.allowsIframe(src) {
+			return fmt.Errorf("iframe host not allowed for %s: %s", provider.name, src)
+		}
+		meta.EmbedURL = src
+	} else if data.Type == "rich" || data.Type == "video" {
+		meta.EmbedHTML = sanitizer.SanitizeHTML(data.HTML)
+	}
+	if meta.EmbedURL == "" && meta.EmbedHTML == "" {
+		return fmt.Errorf("no embeddable content from %s", provider.name)
+	}
+
+	meta.EmbedProvider = provider.name
+	meta.EmbedWidth = int(data.Width)
+	meta.EmbedHeight = int(data.Height)
+	return nil
+}
+
+func oEmbedEndpoint(provider *embedProvider, pageURL string, doc *html.Node) (string, error) {
+	if href := findOEmbedLink(doc); href != "" {
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid page url: %w", err)
+		}
+		ref, err := url.Parse(href)
+		if err != nil {
+			return "", fmt.Errorf("invalid oembed link: %w", err)
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+	if provider.endpoint == "" {
+		return "", fmt.Errorf("no oembed endpoint for %s", provider.name)
+	}
+	return provider.endpoint + "?format=json&url=" + url.QueryEscape(pageURL), nil
+}
+
+func (f *HTMLFetcher) fetchOEmbed(ctx context.Context, endpoint string) (*oEmbedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oembed request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Driplnk-Bot/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch oembed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected oembed status code: %d", resp.StatusCode)
+	}
+
+	var data oEmbedResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOEmbedResponseSize)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse oembed: %w", err)
+	}
+	return &data, nil
+}
+
+// allowsIframe reports whether src is an https URL on one of the provider's iframe hosts.
+func (p *embedProvider) allowsIframe(src string) bool {
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	return p.iframeHosts[strings.ToLower(u.Hostname())]
+}
+
+// findOEmbedLink returns the href of <link rel="alternate" type="application/json+oembed">.
+func findOEmbedLink(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "link" {
+		var rel, typ, href string
+		for _, attr := range n.Attr {
+			switch attr.Key {
+			case "rel":
+				rel = attr.Val
+			case "type":
+				typ = attr.Val
+			case "href":
+				href = attr.Val
+			}
+		}
+		if strings.EqualFold(rel, "alternate") && strings.EqualFold(typ, "application/json+oembed") && href != "" {
+			return href
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if href := findOEmbedLink(c); href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// iframeSrc returns the src of the first iframe in an oEmbed HTML snippet.
+func iframeSrc(snippet string) string {
+	if snippet == "" {
+		return ""
+	}
+	doc, err := html.Parse(strings.NewReader(snippet))
+	if err != nil {
+		log.Printf("[WARN] Failed to parse oembed html: %v", err)
+		return ""
+	}
+	var find func(*html.Node) string
+	find = func(n *html.Node) string {
+		if n.Type == html.ElementNode && n.Data == "iframe" {
+			for _, attr := range n.Attr {
+				if attr.Key == "src" {
+					return attr.Val
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if src := find(c); src != "" {
+				return src
+			}
+		}
+		return ""
+	}
+	return find(doc)
+}
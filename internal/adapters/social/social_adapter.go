@@ -25,6 +25,10 @@ func NewSocialAdapter(configs []config.SocialPlatformConfig) *SocialAdapter {
 			// Skip invalid regex, maybe log it
 			continue
 		}
+		var paths []domain.AppLinkPath
+		for _, p := range cfg.AppLinks.Paths {
+			paths = append(paths, domain.AppLinkPath{Pattern: p.Pattern, IOS: p.IOS, Android: p.Android})
+		}
 		rules = append(rules, platformRule{
 			regex: regex,
 			platform: domain.SocialPlatform{
@@ -32,6 +36,11 @@ func NewSocialAdapter(configs []config.SocialPlatformConfig) *SocialAdapter {
 				Domain:  cfg.Domain,
 				IconSVG: cfg.IconSVG,
 				Color:   cfg.Color,
+				AppLinks: domain.AppLinkTemplates{
+					IOS:     cfg.AppLinks.IOS,
+					Android: cfg.AppLinks.Android,
+					Paths:   paths,
+				},
 			},
 		})
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/elchemista/driplnk/internal/adapters/social"
 	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
 )
 
 func TestSocialAdapter_Resolve(t *testing.T) {
@@ -140,3 +141,76 @@ func TestSocialAdapter_InvalidRegex(t *testing.T) {
 		}
 	})
 }
+
+func TestSocialAdapter_AppLinks(t *testing.T) {
+	spotify := config.SocialPlatformConfig{Name: "Spotify", Domain: "spotify.com", RegexPattern: `spotify\.com`}
+	spotify.AppLinks.Paths = []config.AppLinkPathConfig{
+		{Pattern: "intl-*/*/*", IOS: "spotify:{2}:{3}"},
+		{Pattern: "*/*", IOS: "spotify:{1}:{2}"},
+	}
+	spotify.AppLinks.Android = "intent://{host}/{path}{query}#Intent;scheme=https;package=com.spotify.music;S.browser_fallback_url={url};end"
+	instagram := config.SocialPlatformConfig{Name: "Instagram", Domain: "instagram.com", RegexPattern: `instagram\.com`}
+	instagram.AppLinks.Paths = []config.AppLinkPathConfig{{Pattern: "*", IOS: "instagram://user?username={1}"}}
+	instagram.AppLinks.Android = "intent://{host}/{path}{query}#Intent;scheme=https;package=com.instagram.android;end"
+	adapter := social.NewSocialAdapter([]config.SocialPlatformConfig{
+		spotify,
+		instagram,
+		{Name: "GitHub", Domain: "github.com", RegexPattern: `github\.com`},
+	})
+
+	platform, err := adapter.Resolve("https://open.spotify.com/track/abc123?si=x")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		os   domain.MobileOS
+		url  string
+		want string
+	}{
+		{"ios scheme from path segments", domain.MobileOSIOS, "https://open.spotify.com/track/abc123?si=x", "spotify:track:abc123"},
+		{"android intent with fallback", domain.MobileOSAndroid, "https://open.spotify.com/track/abc123?si=x",
+			"intent://open.spotify.com/track/abc123?si=x#Intent;scheme=https;package=com.spotify.music;S.browser_fallback_url=https%3A%2F%2Fopen.spotify.com%2Ftrack%2Fabc123%3Fsi%3Dx;end"},
+		{"intl prefixed path", domain.MobileOSIOS, "https://open.spotify.com/intl-de/track/abc123", "spotify:track:abc123"},
+		{"missing path segment", domain.MobileOSIOS, "https://open.spotify.com/", ""},
+		{"unmatched path shape", domain.MobileOSIOS, "https://open.spotify.com/a/b/c/d", ""},
+		{"unknown os", "", "https://open.spotify.com/track/abc123", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := platform.AppLinks.For(tt.os, tt.url); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	instagramPlatform, err := adapter.Resolve("https://www.instagram.com/elchemista")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	instagramTests := []struct {
+		name string
+		os   domain.MobileOS
+		url  string
+		want string
+	}{
+		{"profile", domain.MobileOSIOS, "https://www.instagram.com/elchemista/", "instagram://user?username=elchemista"},
+		{"post falls back to web", domain.MobileOSIOS, "https://www.instagram.com/p/Cx1abc/", ""},
+		{"reel falls back to web", domain.MobileOSIOS, "https://www.instagram.com/reel/Cx1abc/", ""},
+		{"android opens any path", domain.MobileOSAndroid, "https://www.instagram.com/p/Cx1abc/",
+			"intent://www.instagram.com/p/Cx1abc#Intent;scheme=https;package=com.instagram.android;end"},
+	}
+	for _, tt := range instagramTests {
+		t.Run("instagram "+tt.name, func(t *testing.T) {
+			if got := instagramPlatform.AppLinks.For(tt.os, tt.url); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	github, _ := adapter.Resolve("https://github.com/elchemista")
+	if got := github.AppLinks.For(domain.MobileOSIOS, "https://github.com/elchemista"); got != "" {
+		t.Errorf("expected no app link for a platform without templates, got %q", got)
+	}
+}
@@ -72,14 +72,27 @@ func (u *S3Uploader) Upload(ctx context.Context, file io.Reader, filename string
 
 // UploadProfileImage uploads a profile image, resizing it and converting to WebP.
 func (u *S3Uploader) UploadProfileImage(ctx context.Context, file io.Reader, filename string) (string, error) {
+	// Resize to 500x500 (Cover) - Smart cropping
+	return u.uploadWebP(ctx, file, filename, 500, 500)
+}
+
+// UploadThumbnail uploads a link preview image, cropping it to the 1.91:1 card ratio
+// used by OpenGraph images and converting to WebP.
+func (u *S3Uploader) UploadThumbnail(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return u.uploadWebP(ctx, file, filename, 800, 420)
+}
+
+// uploadWebP decodes an image, fills it to width x height and uploads it as WebP.
+// It returns the public URL of the uploaded file.
+func (u *S3Uploader) uploadWebP(ctx context.Context, file io.Reader, filename string, width, height int) (string, error) {
 	// 1. Decode image
 	img, _, err := image.Decode(file)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode image: %w", err)
 	}
 
-	// 2. Resize to 500x500 (Cover) - Smart cropping
-	resized := imaging.Fill(img, 500, 500, imaging.Center, imaging.Lanczos)
+	// 2. Resize (Cover) - Smart cropping
+	resized := imaging.Fill(img, width, height, imaging.Center, imaging.Lanczos)
 
 	// 3. Encode to WebP
 	var buf bytes.Buffer
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/ports"
+)
+
+// maxThumbnailBytes caps the size of a downloaded preview image.
+const maxThumbnailBytes = 5 << 20
+
+// ThumbnailCache implements domain.ThumbnailStore on top of a FileUploader.
+// Remote OG images are downloaded once and served from our storage afterwards.
+type ThumbnailCache struct {
+	uploader ports.FileUploader
+	client   *http.Client
+}
+
+func NewThumbnailCache(uploader ports.FileUploader) *ThumbnailCache {
+	return &ThumbnailCache{
+		uploader: uploader,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (c *ThumbnailCache) StoreThumbnail(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return c.uploader.UploadThumbnail(ctx, file, filename)
+}
+
+func (c *ThumbnailCache) CacheThumbnail(ctx context.Context, imageURL, filename string) (string, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid image url: %q", imageURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Driplnk-Bot/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "image/") {
+		return "", fmt.Errorf("unexpected content type: %s", ct)
+	}
+	if resp.ContentLength > maxThumbnailBytes {
+		return "", fmt.Errorf("image too large: %d bytes", resp.ContentLength)
+	}
+
+	// Read one byte past the cap to detect oversized bodies without a Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxThumbnailBytes {
+		return "", fmt.Errorf("image too large: more than %d bytes", maxThumbnailBytes)
+	}
+
+	return c.uploader.UploadThumbnail(ctx, bytes.NewReader(data), filename)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeUploader struct {
+	thumbnails map[string][]byte
+}
+
+func (f *fakeUploader) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return filename, nil
+}
+
+func (f *fakeUploader) UploadProfileImage(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return filename, nil
+}
+
+func (f *fakeUploader) UploadThumbnail(ctx context.Context, file io.Reader, filename string) (string, error) {
+	data, _ := io.ReadAll(file)
+	f.thumbnails[filename] = data
+	return "https://cdn.example.com/" + filename + ".webp", nil
+}
+
+func TestThumbnailCache_CacheThumbnail(t *testing.T) {
+	image := []byte("\x89PNG fake image")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/og.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(image)
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(bytes.Repeat([]byte("x"), maxThumbnailBytes+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	uploader := &fakeUploader{thumbnails: make(map[string][]byte)}
+	cache := NewThumbnailCache(uploader)
+	ctx := context.Background()
+
+	got, err := cache.CacheThumbnail(ctx, srv.URL+"/og.png", "thumbnails/link-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != "https://cdn.example.com/thumbnails/link-1.webp" {
+		t.Errorf("unexpected stored URL %q", got)
+	}
+	if !bytes.Equal(uploader.thumbnails["thumbnails/link-1"], image) {
+		t.Error("expected the downloaded image to be uploaded")
+	}
+
+	for _, imageURL := range []string{
+		srv.URL + "/page",
+		srv.URL + "/missing.png",
+		srv.URL + "/huge.png",
+		"ftp://example.com/og.png",
+		"/relative.png",
+	} {
+		if _, err := cache.CacheThumbnail(ctx, imageURL, "thumbnails/link-2"); err == nil {
+			t.Errorf("expected %s to be rejected", imageURL)
+		}
+	}
+	if _, ok := uploader.thumbnails["thumbnails/link-2"]; ok {
+		t.Error("expected rejected images not to be uploaded")
+	}
+}
@@ -0,0 +1,55 @@
+package traffic
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+type BotAdapter struct {
+	patterns []*regexp.Regexp
+}
+
+func NewBotAdapter(cfg config.BotDetectionConfig) *BotAdapter {
+	a := &BotAdapter{}
+	for _, pattern := range cfg.UserAgentPatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			// Skip invalid regex, same as the social adapter
+			continue
+		}
+		a.patterns = append(a.patterns, re)
+	}
+	return a
+}
+
+// IsBot reports whether the client is automated. Besides the configured patterns,
+// clients are bots when they send no User-Agent, do not identify as a browser,
+// only ask for headers, prefetch, or claim to be a browser without the
+// Accept-Language header every browser sends.
+func (a *BotAdapter) IsBot(signals domain.ClientSignals) bool {
+	ua := strings.TrimSpace(signals.UserAgent)
+	if ua == "" {
+		return true
+	}
+	for _, re := range a.patterns {
+		if re.MatchString(ua) {
+			return true
+		}
+	}
+
+	// Every browser, in-app browsers included, starts with Mozilla/ (old Opera aside)
+	if !strings.HasPrefix(ua, "Mozilla/") && !strings.HasPrefix(ua, "Opera/") {
+		return true
+	}
+	if signals.Method == http.MethodHead {
+		return true
+	}
+	if strings.Contains(strings.ToLower(signals.Purpose), "prefetch") {
+		return true
+	}
+	return signals.AcceptLanguage == ""
+}
@@ -0,0 +1,52 @@
+package traffic_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/traffic"
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+func TestBotAdapter_IsBot(t *testing.T) {
+	adapter := traffic.NewBotAdapter(config.BotDetectionConfig{
+		UserAgentPatterns: []string{"slackbot", "facebookexternalhit", "whatsapp", "^curl/", "("},
+	})
+
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36"
+	browser := func(s domain.ClientSignals) domain.ClientSignals {
+		if s.UserAgent == "" {
+			s.UserAgent = chrome
+		}
+		if s.Method == "" {
+			s.Method = http.MethodGet
+		}
+		s.AcceptLanguage = "en-US,en;q=0.9"
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		signals domain.ClientSignals
+		want    bool
+	}{
+		{"browser", browser(domain.ClientSignals{}), false},
+		{"in-app browser", browser(domain.ClientSignals{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148 Instagram 300.0"}), false},
+		{"pattern", browser(domain.ClientSignals{UserAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"}), true},
+		{"pattern is case-insensitive", browser(domain.ClientSignals{UserAgent: "Mozilla/5.0 (compatible; FacebookExternalHit/1.1)"}), true},
+		{"WhatsApp preview", browser(domain.ClientSignals{UserAgent: "WhatsApp/2.23.20 A"}), true},
+		{"not a browser", browser(domain.ClientSignals{UserAgent: "Java/17.0.2"}), true},
+		{"no user agent", domain.ClientSignals{Method: http.MethodGet, AcceptLanguage: "en"}, true},
+		{"HEAD request", browser(domain.ClientSignals{Method: http.MethodHead}), true},
+		{"prefetch", browser(domain.ClientSignals{Purpose: "prefetch;prerender"}), true},
+		{"browser without Accept-Language", domain.ClientSignals{UserAgent: chrome, Method: http.MethodGet}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adapter.IsBot(tt.signals); got != tt.want {
+				t.Errorf("IsBot(%+v) = %v, want %v", tt.signals, got, tt.want)
+			}
+		})
+	}
+}
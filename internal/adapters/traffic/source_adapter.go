@@ -0,0 +1,68 @@
+package traffic
+
+import (
+	"strings"
+
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+type SourceAdapter struct {
+	domains    map[string]string // referrer host -> source name
+	utmSources map[string]string // lowercased utm_source -> source name
+}
+
+func NewSourceAdapter(configs []config.TrafficSourceConfig) *SourceAdapter {
+	a := &SourceAdapter{
+		domains:    make(map[string]string),
+		utmSources: make(map[string]string),
+	}
+	for _, cfg := range configs {
+		name := strings.ToLower(strings.TrimSpace(cfg.Name))
+		if name == "" {
+			continue
+		}
+		a.utmSources[name] = name
+		for _, alias := range cfg.UTMSources {
+			a.utmSources[strings.ToLower(strings.TrimSpace(alias))] = name
+		}
+		for _, d := range cfg.Domains {
+			host := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "www.")
+			if host != "" {
+				a.domains[host] = name
+			}
+		}
+	}
+	return a
+}
+
+// ResolveSource names the traffic source. Unknown utm_source values are kept
+// (lowercased) and unknown referrers are reported by host.
+func (a *SourceAdapter) ResolveSource(referrer, utmSource string) string {
+	if utm := strings.ToLower(strings.TrimSpace(utmSource)); utm != "" {
+		if name, ok := a.utmSources[utm]; ok {
+			return name
+		}
+		return utm
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(referrer), "www.")
+	if host == "" {
+		return domain.SourceDirect
+	}
+	if host == domain.ReferrerInternal {
+		return host
+	}
+	// Match the host and then its parent domains, so l.instagram.com is instagram
+	for h := host; h != ""; {
+		if name, ok := a.domains[h]; ok {
+			return name
+		}
+		dot := strings.IndexByte(h, '.')
+		if dot < 0 {
+			break
+		}
+		h = h[dot+1:]
+	}
+	return host
+}
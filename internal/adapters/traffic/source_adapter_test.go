@@ -0,0 +1,43 @@
+package traffic_test
+
+import (
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/adapters/traffic"
+	"github.com/elchemista/driplnk/internal/config"
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+func TestSourceAdapter_ResolveSource(t *testing.T) {
+	adapter := traffic.NewSourceAdapter([]config.TrafficSourceConfig{
+		{Name: "Instagram", Domains: []string{"instagram.com"}, UTMSources: []string{"ig"}},
+		{Name: "google", Domains: []string{"google.com", "www.google.de"}},
+		{Name: "", Domains: []string{"ignored.example"}},
+	})
+
+	tests := []struct {
+		name      string
+		referrer  string
+		utmSource string
+		want      string
+	}{
+		{"referrer host", "instagram.com", "", "instagram"},
+		{"referrer subdomain", "l.instagram.com", "", "instagram"},
+		{"www is ignored", "google.de", "", "google"},
+		{"utm alias", "", "IG", "instagram"},
+		{"utm wins over referrer", "google.com", "instagram", "instagram"},
+		{"unknown utm source is kept", "google.com", " Newsletter ", "newsletter"},
+		{"unknown referrer is kept", "blog.example.com", "", "blog.example.com"},
+		{"lookalike domain", "notinstagram.com", "", "notinstagram.com"},
+		{"nameless config is skipped", "ignored.example", "", "ignored.example"},
+		{"internal", domain.ReferrerInternal, "", domain.ReferrerInternal},
+		{"direct", "", "", domain.SourceDirect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adapter.ResolveSource(tt.referrer, tt.utmSource); got != tt.want {
+				t.Errorf("ResolveSource(%q, %q) = %q, want %q", tt.referrer, tt.utmSource, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,26 @@ package config
 
 // SocialPlatformConfig defines configuration for social media platforms
 type SocialPlatformConfig struct {
-	Name         string `json:"name"`
-	Domain       string `json:"domain"`
-	RegexPattern string `json:"regex_pattern"`
-	IconSVG      string `json:"icon_svg"`
-	Color        string `json:"color"`
+	Name         string        `json:"name"`
+	Domain       string        `json:"domain"`
+	RegexPattern string        `json:"regex_pattern"`
+	IconSVG      string        `json:"icon_svg"`
+	Color        string        `json:"color"`
+	AppLinks     AppLinkConfig `json:"app_links,omitempty"`
+}
+
+// AppLinkConfig holds the URI templates that open a platform's native app (see domain.AppLinkTemplates)
+type AppLinkConfig struct {
+	IOS     string              `json:"ios,omitempty"`
+	Android string              `json:"android,omitempty"`
+	Paths   []AppLinkPathConfig `json:"paths,omitempty"`
+}
+
+// AppLinkPathConfig holds the URI templates for web URLs whose path matches Pattern
+type AppLinkPathConfig struct {
+	Pattern string `json:"pattern"`
+	IOS     string `json:"ios,omitempty"`
+	Android string `json:"android,omitempty"`
 }
 
 // ThemeConfig defines available theme options
@@ -26,3 +41,38 @@ type ThemeOption struct {
 	CSSClass string `json:"css_class,omitempty"`
 	Family   string `json:"family,omitempty"`
 }
+
+// AffiliateNetworkConfig defines an affiliate program whose tag is injected into product links
+type AffiliateNetworkConfig struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	HostPattern string `json:"host_pattern"` // matched against the destination hostname
+	Param       string `json:"param"`        // query parameter carrying the tag, e.g. "tag" for Amazon
+}
+
+// EmbedProviderConfig defines an oEmbed provider whose players can be embedded inline
+type EmbedProviderConfig struct {
+	Name         string   `json:"name"`
+	RegexPattern string   `json:"regex_pattern"` // matched against the link URL
+	Endpoint     string   `json:"endpoint"`      // oEmbed endpoint; empty to use the one discovered in the page
+	IframeHosts  []string `json:"iframe_hosts"`  // hosts allowed as iframe src
+}
+
+// LinkSafetyConfig defines which outbound link destinations users may link to
+type LinkSafetyConfig struct {
+	AllowedSchemes []string `json:"allowed_schemes"`           // e.g. ["https", "http", "mailto", "tel"]
+	BlockedDomains []string `json:"blocked_domains,omitempty"` // rejected together with their subdomains
+	BlocklistFile  string   `json:"blocklist_file,omitempty"`  // optional text file next to the config, one domain per line
+}
+
+// TrafficSourceConfig maps referrers and utm_source values to a traffic source name
+type TrafficSourceConfig struct {
+	Name       string   `json:"name"`                  // source name shown in analytics, e.g. "instagram"
+	Domains    []string `json:"domains"`               // referrer hosts, matched together with their subdomains
+	UTMSources []string `json:"utm_sources,omitempty"` // utm_source aliases, e.g. ["ig"]; the name itself always matches
+}
+
+// BotDetectionConfig lists the User-Agents of automated clients left out of analytics
+type BotDetectionConfig struct {
+	UserAgentPatterns []string `json:"user_agent_patterns"` // case-insensitive regexes, e.g. "slackbot", "^curl/"
+}
@@ -0,0 +1,16 @@
+package domain
+
+// AffiliateNetwork describes an affiliate program whose tag can be added to product URLs.
+type AffiliateNetwork struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AffiliateTagger defines the contract for adding a user's affiliate tag to outbound product URLs.
+type AffiliateTagger interface {
+	// Networks lists the configured affiliate networks.
+	Networks() []AffiliateNetwork
+	// Tag returns rawURL carrying tag for the given network.
+	// URLs on hosts the network does not cover are returned unchanged.
+	Tag(rawURL, networkID, tag string) (string, error)
+}
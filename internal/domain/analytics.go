@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"sort"
 	"time"
 )
 
@@ -13,6 +14,44 @@ const (
 	EventTypeScroll AnalyticsEventType = "scroll"
 )
 
+// SourceQR is the "source" meta value of events from visitors who scanned a QR code.
+// Other visits carry their traffic source there (see TrafficSourceResolver).
+const SourceQR = "qr"
+
+// SourceDirect is the traffic source of visits with neither a referrer nor a utm_source.
+const SourceDirect = "direct"
+
+// TrafficSourceResolver names the platform that sent a visitor, e.g. "instagram" or
+// "google", from the referrer host (see ReferrerInternal) and the utm_source parameter.
+type TrafficSourceResolver interface {
+	// ResolveSource returns the source name; utm_source wins over the referrer and
+	// SourceDirect is returned when both are empty.
+	ResolveSource(referrer, utmSource string) string
+}
+
+// ReferrerInternal is the "referrer" meta value of events referred by our own pages.
+// Clicks on the profile page take the referrer of the profile view instead, and
+// only keep ReferrerInternal when that view is unknown.
+const ReferrerInternal = "internal"
+
+// MetaIsBot is the meta key handlers set to "true" for events of automated clients.
+// AnalyticsService moves it to AnalyticsEvent.IsBot.
+const MetaIsBot = "is_bot"
+
+// ClientSignals describes the client of a request for bot classification.
+type ClientSignals struct {
+	UserAgent      string
+	AcceptLanguage string
+	Method         string
+	Purpose        string // Sec-Purpose or Purpose header, e.g. "prefetch"
+}
+
+// BotClassifier recognizes automated clients: crawlers, link preview fetchers
+// of chat apps and social networks, uptime monitors and prefetches.
+type BotClassifier interface {
+	IsBot(signals ClientSignals) bool
+}
+
 type AnalyticsEvent struct {
 	ID        string             `json:"id"`
 	EventType AnalyticsEventType `json:"event_type"`
@@ -21,16 +60,265 @@ type AnalyticsEvent struct {
 	VisitorID string             `json:"visitor_id"`
 	Country   string             `json:"country,omitempty"`
 	Region    string             `json:"region,omitempty"`
+	IsBot     bool               `json:"is_bot,omitempty"` // automated client; only counted in BotViews/BotClicks
 	Meta      map[string]string  `json:"meta,omitempty"`
 	CreatedAt time.Time          `json:"created_at"`
 }
 
 // AnalyticsSummary represents aggregated data for charts/stats
 type AnalyticsSummary struct {
-	TotalViews  int64            `json:"total_views"`
-	TotalClicks int64            `json:"total_clicks"`
-	ByCountry   map[string]int64 `json:"by_country"` // country_code -> count
-	ByDevice    map[string]int64 `json:"by_device"`  // mobile/desktop -> count (parsed from UA in meta)
+	TotalViews     int64            `json:"total_views"`
+	TotalClicks    int64            `json:"total_clicks"`
+	UniqueVisitors int64            `json:"unique_visitors"` // distinct visitors with a view or click; estimated by some stores
+	ByCountry      map[string]int64 `json:"by_country"`      // country_code -> count
+	ByDevice       map[string]int64 `json:"by_device"`       // mobile/desktop -> count (parsed from UA in meta)
+	QRViews        int64            `json:"qr_views"`        // views from profile QR code scans
+	QRClicks       int64            `json:"qr_clicks"`       // clicks from link QR code scans
+	ByReferrer     map[string]int64 `json:"by_referrer"`     // referrer host -> profile views, or clicks for a link
+	BySource       map[string]int64 `json:"by_source"`       // traffic source (instagram, direct, qr, ...) -> count, split like ByReferrer
+	ByCampaign     map[string]int64 `json:"by_campaign"`     // utm_campaign -> count, split like ByReferrer
+	BotViews       int64            `json:"bot_views"`       // views by bots, left out of every other count
+	BotClicks      int64            `json:"bot_clicks"`      // clicks by bots, left out of every other count
+}
+
+// LinkClicks ranks one link of a user by its clicks.
+type LinkClicks struct {
+	LinkID         string `json:"link_id"`
+	Clicks         int64  `json:"clicks"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+}
+
+// SortLinkClicks orders links by clicks, most first, then by ID for a stable ranking.
+func SortLinkClicks(links []LinkClicks) {
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Clicks != links[j].Clicks {
+			return links[i].Clicks > links[j].Clicks
+		}
+		return links[i].LinkID < links[j].LinkID
+	})
+}
+
+// LinkStats gathers the analytics of a single link.
+type LinkStats struct {
+	Summary      *AnalyticsSummary `json:"summary"`       // clicks with their country, device and referrer split
+	ProfileViews int64             `json:"profile_views"` // views of the owner's profile, the link's audience
+	CTR          float64           `json:"ctr"`           // Clicks / ProfileViews, 0 when there are no views
+	Series       *TimeSeries       `json:"series"`
+}
+
+// VariantStats reports the performance of one A/B variant of a link.
+// Views are the profile views of visitors assigned to the variant.
+type VariantStats struct {
+	Variant string  `json:"variant"`
+	Views   int64   `json:"views"`
+	Clicks  int64   `json:"clicks"`
+	CTR     float64 `json:"ctr"` // Clicks / Views, 0 when there are no views
+}
+
+// BuildVariantStats assembles per-variant stats, in variant order, from raw counts.
+// viewsByVisitor maps visitor IDs to their profile views; clicksByVariant maps variant names to clicks.
+func BuildVariantStats(link *Link, viewsByVisitor map[string]int64, clicksByVariant map[string]int64) []VariantStats {
+	views := make(map[string]int64)
+	for visitorID, count := range viewsByVisitor {
+		// Anonymous visitors cannot be attributed to a sticky variant
+		if visitorID == "" || visitorID == "unknown" {
+			continue
+		}
+		if v := link.VariantFor(visitorID); v != nil {
+			views[v.Name] += count
+		}
+	}
+
+	stats := make([]VariantStats, 0, len(link.Variants))
+	for _, v := range link.Variants {
+		s := VariantStats{
+			Variant: v.Name,
+			Views:   views[v.Name],
+			Clicks:  clicksByVariant[v.Name],
+		}
+		if s.Views > 0 {
+			s.CTR = float64(s.Clicks) / float64(s.Views)
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// TimeBucket is the width of one point of an analytics time series.
+// Buckets are aligned in UTC; weeks start on Monday like Postgres date_trunc.
+type TimeBucket string
+
+const (
+	BucketHour TimeBucket = "hour"
+	BucketDay  TimeBucket = "day"
+	BucketWeek TimeBucket = "week"
+)
+
+// IsValid reports whether b is a supported bucket size.
+func (b TimeBucket) IsValid() bool {
+	return b == BucketHour || b == BucketDay || b == BucketWeek
+}
+
+// Truncate returns the start of the bucket containing t.
+func (b TimeBucket) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch b {
+	case BucketHour:
+		return t.Truncate(time.Hour)
+	case BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at start.
+func (b TimeBucket) Next(start time.Time) time.Time {
+	switch b {
+	case BucketHour:
+		return start.Add(time.Hour)
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Duration returns the length of a bucket; buckets are aligned in UTC so it is fixed.
+func (b TimeBucket) Duration() time.Duration {
+	switch b {
+	case BucketHour:
+		return time.Hour
+	case BucketWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// TimeSeriesQuery selects the events counted in a time series.
+type TimeSeriesQuery struct {
+	UserID string
+	LinkID *string // Optional: only events of this link
+	From   time.Time
+	To     time.Time // Exclusive
+	Bucket TimeBucket
+}
+
+// BucketCount returns how many points the series of q has.
+func (q TimeSeriesQuery) BucketCount() int {
+	if !q.From.Before(q.To) {
+		return 0
+	}
+	span := q.To.Sub(q.Bucket.Truncate(q.From))
+	return int((span + q.Bucket.Duration() - 1) / q.Bucket.Duration())
+}
+
+// TimeSeriesPoint aggregates the events of one bucket.
+type TimeSeriesPoint struct {
+	Start          time.Time `json:"start"`
+	Views          int64     `json:"views"`
+	Clicks         int64     `json:"clicks"`
+	UniqueVisitors int64     `json:"unique_visitors"` // distinct visitors with a view or click in the bucket
+}
+
+// TimeSeries holds one point per bucket of a date range, including empty buckets.
+type TimeSeries struct {
+	Bucket         TimeBucket        `json:"bucket"`
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	Points         []TimeSeriesPoint `json:"points"`
+	UniqueVisitors int64             `json:"unique_visitors"` // distinct visitors over the whole range
+}
+
+// NewTimeSeries returns the series of q with a zero point for every bucket.
+func NewTimeSeries(q TimeSeriesQuery) *TimeSeries {
+	series := &TimeSeries{Bucket: q.Bucket, From: q.From, To: q.To}
+	for start := q.Bucket.Truncate(q.From); start.Before(q.To); start = q.Bucket.Next(start) {
+		series.Points = append(series.Points, TimeSeriesPoint{Start: start})
+	}
+	return series
+}
+
+// Point returns the point of the bucket containing t, or nil when t is outside the series.
+func (s *TimeSeries) Point(t time.Time) *TimeSeriesPoint {
+	start := s.Bucket.Truncate(t)
+	i := sort.Search(len(s.Points), func(i int) bool { return !s.Points[i].Start.Before(start) })
+	if i == len(s.Points) || !s.Points[i].Start.Equal(start) {
+		return nil
+	}
+	return &s.Points[i]
+}
+
+// Partial reports whether the bucket starting at start only partly lies in the
+// series range, like the first bucket of a range starting mid-day or the current hour.
+func (s *TimeSeries) Partial(start time.Time) bool {
+	return start.Before(s.From) || s.Bucket.Next(start).After(s.To)
+}
+
+// Totals sums the views and clicks of all points.
+func (s *TimeSeries) Totals() (views, clicks int64) {
+	for _, p := range s.Points {
+		views += p.Views
+		clicks += p.Clicks
+	}
+	return views, clicks
+}
+
+// TimeSeriesCounter builds a time series from raw events, for stores that
+// cannot aggregate on their own. Callers filter events by user and link.
+type TimeSeriesCounter struct {
+	query    TimeSeriesQuery
+	series   *TimeSeries
+	visitors map[time.Time]map[string]struct{}
+	all      map[string]struct{}
+}
+
+func NewTimeSeriesCounter(q TimeSeriesQuery) *TimeSeriesCounter {
+	return &TimeSeriesCounter{
+		query:    q,
+		series:   NewTimeSeries(q),
+		visitors: make(map[time.Time]map[string]struct{}),
+		all:      make(map[string]struct{}),
+	}
+}
+
+// Add counts a view or click event; other events, bots and events outside the range are ignored.
+func (c *TimeSeriesCounter) Add(event *AnalyticsEvent) {
+	if (event.EventType != EventTypeView && event.EventType != EventTypeClick) || event.IsBot {
+		return
+	}
+	if event.CreatedAt.Before(c.query.From) || !event.CreatedAt.Before(c.query.To) {
+		return
+	}
+	point := c.series.Point(event.CreatedAt)
+	if point == nil {
+		return
+	}
+
+	if event.EventType == EventTypeView {
+		point.Views++
+	} else {
+		point.Clicks++
+	}
+
+	seen := c.visitors[point.Start]
+	if seen == nil {
+		seen = make(map[string]struct{})
+		c.visitors[point.Start] = seen
+	}
+	if _, ok := seen[event.VisitorID]; !ok {
+		seen[event.VisitorID] = struct{}{}
+		point.UniqueVisitors++
+	}
+	c.all[event.VisitorID] = struct{}{}
+}
+
+// Series returns the counted time series.
+func (c *TimeSeriesCounter) Series() *TimeSeries {
+	c.series.UniqueVisitors = int64(len(c.all))
+	return c.series
 }
 
 // AnalyticsRepository defines the contract for analytics data persistence.
@@ -42,4 +330,15 @@ type AnalyticsRepository interface {
 	// GetSummary returns aggregated stats for a user (and optionally a specific link).
 	// If linkID is empty, it returns stats for the user's profile view.
 	GetSummary(ctx context.Context, userID string, linkID *string) (*AnalyticsSummary, error)
+
+	// GetVariantStats returns clicks and CTR for each variant of an A/B tested link.
+	// Profile views are attributed to variants with link.VariantFor on the visitor ID.
+	GetVariantStats(ctx context.Context, userID string, link *Link) ([]VariantStats, error)
+
+	// GetTimeSeries returns views, clicks and unique visitors per bucket of the
+	// query's date range. Empty buckets are included with zero counts.
+	GetTimeSeries(ctx context.Context, query TimeSeriesQuery) (*TimeSeries, error)
+
+	// GetTopLinks returns the user's most clicked links, most clicks first.
+	GetTopLinks(ctx context.Context, userID string, limit int) ([]LinkClicks, error)
 }
@@ -22,6 +22,9 @@ var (
 	// ErrConflict is returned when there's a resource conflict (e.g., duplicate).
 	ErrConflict = errors.New("conflict")
 
+	// ErrUnsafeURL is returned when a link destination is rejected by the link safety policy.
+	ErrUnsafeURL = errors.New("unsafe url")
+
 	// ErrTooManyRequests is returned when rate limit is exceeded.
 	ErrTooManyRequests = errors.New("too many requests")
 
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LinkHealth is the result of the last destination check of a link.
+type LinkHealth struct {
+	StatusCode  int       `json:"status_code,omitempty"`
+	FinalURL    string    `json:"final_url,omitempty"` // URL after following redirects
+	Error       string    `json:"error,omitempty"`     // network error, empty when a response was received
+	CheckedAt   time.Time `json:"checked_at"`
+	Failures    int       `json:"failures,omitempty"`    // consecutive broken checks
+	Deactivated bool      `json:"deactivated,omitempty"` // the link was switched off by the auto-deactivate policy
+}
+
+// IsBroken reports whether the destination was unreachable or answered with an error status.
+// 429 is not counted: the destination is up but rate limiting the checker.
+func (h *LinkHealth) IsBroken() bool {
+	if h == nil {
+		return false
+	}
+	if h.Error != "" {
+		return true
+	}
+	return h.StatusCode >= 400 && h.StatusCode != 429
+}
+
+// LinkChecker defines the contract for checking whether a link destination is reachable.
+type LinkChecker interface {
+	// Check requests url and reports the outcome. Network failures are reported in
+	// LinkHealth.Error rather than as an error so they can be stored.
+	Check(ctx context.Context, url string) *LinkHealth
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// hllPrecision is the number of hash bits used to pick a register.
+// 2^12 registers give a standard error of about 1.6%.
+const (
+	hllPrecision = 12
+	hllRegisters = 1 << hllPrecision
+)
+
+// Encodings of a marshaled HyperLogLog
+const (
+	hllDense  byte = 1 // one byte per register
+	hllSparse byte = 2 // (uint16 index, uint8 value) per non-zero register
+)
+
+var ErrInvalidSketch = errors.New("invalid hyperloglog sketch")
+
+// HyperLogLog estimates the number of distinct visitors it has seen in fixed memory.
+// Sketches are mergeable: the merge of two sketches estimates the size of the
+// union of their visitors, so per-hour sketches can be combined into days or ranges.
+type HyperLogLog struct {
+	registers []uint8
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{registers: make([]uint8, hllRegisters)}
+}
+
+// Add records a visitor ID.
+func (h *HyperLogLog) Add(visitorID string) {
+	x := hllHash(visitorID)
+	index := x >> (64 - hllPrecision)
+	// The sentinel bit caps the rank when the remaining bits are all zero
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge adds the visitors of other to h.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	for i, rank := range other.registers {
+		if rank > h.registers[i] {
+			h.registers[i] = rank
+		}
+	}
+}
+
+// Count returns the estimated number of distinct visitors.
+func (h *HyperLogLog) Count() int64 {
+	sum := 0.0
+	zeros := 0
+	for _, rank := range h.registers {
+		sum += 1 / float64(uint64(1)<<rank)
+		if rank == 0 {
+			zeros++
+		}
+	}
+
+	m := float64(hllRegisters)
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// Linear counting is more accurate for small cardinalities
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(math.Round(estimate))
+}
+
+// MarshalBinary encodes the sketch, sparsely while few registers are set.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	set := 0
+	for _, rank := range h.registers {
+		if rank > 0 {
+			set++
+		}
+	}
+
+	if 1+3*set >= 1+hllRegisters {
+		return append([]byte{hllDense}, h.registers...), nil
+	}
+	data := make([]byte, 1, 1+3*set)
+	data[0] = hllSparse
+	for i, rank := range h.registers {
+		if rank > 0 {
+			data = binary.BigEndian.AppendUint16(data, uint16(i))
+			data = append(data, rank)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch written by MarshalBinary.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrInvalidSketch
+	}
+	registers := make([]uint8, hllRegisters)
+
+	switch data[0] {
+	case hllDense:
+		if len(data) != 1+hllRegisters {
+			return ErrInvalidSketch
+		}
+		copy(registers, data[1:])
+	case hllSparse:
+		if (len(data)-1)%3 != 0 {
+			return ErrInvalidSketch
+		}
+		for i := 1; i < len(data); i += 3 {
+			index := binary.BigEndian.Uint16(data[i:])
+			if int(index) >= hllRegisters {
+				return ErrInvalidSketch
+			}
+			registers[index] = data[i+2]
+		}
+	default:
+		return ErrInvalidSketch
+	}
+
+	h.registers = registers
+	return nil
+}
+
+// hllHash spreads FNV-1a with the SplitMix64 finalizer, as HyperLogLog needs
+// uniformly distributed high bits.
+func hllHash(s string) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(s))
+	x := f.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -2,6 +2,12 @@ package domain
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,26 +18,255 @@ const (
 	LinkTypeStandard LinkType = "standard"
 	LinkTypeSocial   LinkType = "social"
 	LinkTypeProduct  LinkType = "product"
+	LinkTypeEmbed    LinkType = "embed" // rendered as an inline player/post via oEmbed
+)
+
+// LinkProtection is the gate visitors must pass before being redirected.
+type LinkProtection string
+
+const (
+	ProtectionNone      LinkProtection = ""
+	ProtectionPassword  LinkProtection = "password"  // visitor must enter a password
+	ProtectionSensitive LinkProtection = "sensitive" // visitor must confirm an 18+ / sensitive content warning
 )
 
 type Link struct {
-	ID         LinkID            `json:"id"`
-	UserID     UserID            `json:"user_id"`
-	Title      string            `json:"title" validate:"required,max=100"`
-	URL        string            `json:"url" validate:"required,url"`
-	Type       LinkType          `json:"type" validate:"oneof=standard social product"`
-	Order      int               `json:"order"`
-	IsActive   bool              `json:"is_active"`
-	Metadata   map[string]string `json:"metadata,omitempty"` // Stores icon_name, og:title, og:image, etc.
-	ClickCount uint64            `json:"click_count"`
-	CreatedAt  time.Time         `json:"created_at"`
-	UpdatedAt  time.Time         `json:"updated_at"`
+	ID             LinkID            `json:"id"`
+	UserID         UserID            `json:"user_id"`
+	Slug           string            `json:"slug,omitempty"` // Optional: unique short path used by /go/{slug} and /{handle}/{slug}
+	Title          string            `json:"title" validate:"required,max=100"`
+	URL            string            `json:"url" validate:"required,url"`
+	Type           LinkType          `json:"type" validate:"oneof=standard social product embed"`
+	Order          int               `json:"order"`
+	GroupID        LinkGroupID       `json:"group_id,omitempty"` // Optional: profile section the link is listed under
+	IsActive       bool              `json:"is_active"`
+	Metadata       map[string]string `json:"metadata,omitempty"` // Stores icon_name, og:title, og:image, etc.
+	ClickCount     uint64            `json:"click_count"`
+	MaxClicks      uint64            `json:"max_clicks,omitempty"`   // Optional: link is used up after this many clicks
+	MaxVisitors    uint64            `json:"max_visitors,omitempty"` // Optional: link is used up after this many unique visitors
+	UniqueVisitors uint64            `json:"unique_visitors"`
+	StartsAt       *time.Time        `json:"starts_at,omitempty"`                             // Optional: link goes live at this time
+	EndsAt         *time.Time        `json:"ends_at,omitempty"`                               // Optional: link expires at this time
+	FallbackURL    string            `json:"fallback_url,omitempty" validate:"omitempty,url"` // Optional: where expired and used-up links redirect
+	Variants       []LinkVariant     `json:"variants,omitempty" validate:"dive"`              // Optional: weighted A/B destinations replacing URL
+	Rules          []RedirectRule    `json:"rules,omitempty" validate:"dive"`                 // Optional: geo/device targeting, checked before URL and Variants
+	Protection     LinkProtection    `json:"protection,omitempty" validate:"omitempty,oneof=password sensitive"`
+	PasswordHash   string            `json:"-"`                    // bcrypt hash; never serialized, repositories persist it outside the JSON document
+	Product        *Product          `json:"product,omitempty"`    // Optional: structured fields of a LinkTypeProduct link
+	Health         *LinkHealth       `json:"health,omitempty"`     // Last destination check by the health checker
+	UTM            *UTMParams        `json:"utm,omitempty"`        // Optional: overrides the owner's default UTM parameters
+	DeepLink       bool              `json:"deep_link,omitempty"`  // Open the destination in its native app on phones when the platform has app links
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"` // Set while the link is in the trash
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// IsPending reports whether the link is scheduled to go live after now.
+func (l *Link) IsPending(now time.Time) bool {
+	return l.StartsAt != nil && now.Before(*l.StartsAt)
+}
+
+// IsExpired reports whether the link's publish window has ended.
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.EndsAt != nil && !now.Before(*l.EndsAt)
+}
+
+// IsWithinSchedule reports whether now falls inside the link's publish window.
+// Links without a start or end time are always within their window.
+func (l *Link) IsWithinSchedule(now time.Time) bool {
+	return !l.IsPending(now) && !l.IsExpired(now)
+}
+
+// IsCapped reports whether the link has a click or unique visitor limit.
+func (l *Link) IsCapped() bool {
+	return l.MaxClicks > 0 || l.MaxVisitors > 0
+}
+
+// IsExhausted reports whether the link has reached one of its limits.
+// Visitors already counted towards MaxVisitors may still follow a link that
+// reached only that limit; see LinkRepository.CountClick.
+func (l *Link) IsExhausted() bool {
+	return (l.MaxClicks > 0 && l.ClickCount >= l.MaxClicks) ||
+		(l.MaxVisitors > 0 && l.UniqueVisitors >= l.MaxVisitors)
+}
+
+// IsTrashed reports whether the link was deleted and waits in the trash to be restored or purged.
+func (l *Link) IsTrashed() bool {
+	return l.DeletedAt != nil
+}
+
+// IsProtected reports whether visitors must pass a gate before the redirect.
+func (l *Link) IsProtected() bool {
+	return l.Protection != ProtectionNone
+}
+
+// UnlockToken identifies the current protection settings of the link.
+// It is remembered in the visitor's signed unlock cookie, so changing the
+// protection mode or password invalidates earlier unlocks.
+func (l *Link) UnlockToken() string {
+	sum := sha256.Sum256([]byte(string(l.ID) + ":" + string(l.Protection) + ":" + l.PasswordHash))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Metadata keys holding thumbnails served from our own storage.
+const (
+	MetaThumbnail       = "thumb:image"  // copy of og:image, refreshed with the metadata
+	MetaCustomThumbnail = "thumb:custom" // uploaded by the owner, wins over the OG image
+)
+
+// ThumbnailURL returns the preview image shown on link cards: the owner's upload,
+// else the stored copy of the OG image. The remote OG image is only used when no
+// copy could be stored (e.g. media storage is not configured).
+func (l *Link) ThumbnailURL() string {
+	for _, key := range []string{MetaCustomThumbnail, MetaThumbnail, "og:image"} {
+		if v := l.Metadata[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Product holds the structured fields of a product link.
+// Prices are decimal strings (e.g. "19.99") so they round-trip without float rounding.
+type Product struct {
+	Price            string `json:"price,omitempty" validate:"omitempty,numeric"`
+	SalePrice        string `json:"sale_price,omitempty" validate:"omitempty,numeric"`
+	Currency         string `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	SKU              string `json:"sku,omitempty" validate:"max=64"`
+	AffiliateNetwork string `json:"affiliate_network,omitempty" validate:"max=32"` // ID from config/affiliates.json
+}
+
+// OnSale reports whether the product has a sale price below its regular price.
+func (p *Product) OnSale() bool {
+	if p == nil || p.Price == "" || p.SalePrice == "" {
+		return false
+	}
+	sale, err := strconv.ParseFloat(p.SalePrice, 64)
+	if err != nil {
+		return false
+	}
+	price, err := strconv.ParseFloat(p.Price, 64)
+	return err == nil && sale < price
+}
+
+// CurrentPrice returns the price a visitor pays: the sale price when on sale, else the regular price.
+func (p *Product) CurrentPrice() string {
+	if p.OnSale() || p.Price == "" {
+		return p.SalePrice
+	}
+	return p.Price
+}
+
+// IsAffiliate reports whether the link should carry its owner's affiliate tag.
+func (l *Link) IsAffiliate() bool {
+	return l.Type == LinkTypeProduct && l.Product != nil && l.Product.AffiliateNetwork != ""
+}
+
+// LinkVariant is one weighted destination of an A/B tested link.
+type LinkVariant struct {
+	Name   string `json:"name"` // Short label recorded in click meta, e.g. "A"
+	URL    string `json:"url" validate:"required,url"`
+	Weight int    `json:"weight" validate:"min=1,max=1000"`
+}
+
+// VariantFor picks the destination variant for a visitor.
+// The choice is derived from the visitor ID so a returning visitor always lands on
+// the same variant; an empty visitor ID gets a random weighted pick.
+// Returns nil if the link has no variants.
+func (l *Link) VariantFor(visitorID string) *LinkVariant {
+	total := 0
+	for _, v := range l.Variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var n int
+	if visitorID == "" {
+		n = rand.IntN(total)
+	} else {
+		h := fnv.New32a()
+		h.Write([]byte(string(l.ID) + ":" + visitorID))
+		n = int(h.Sum32() % uint32(total))
+	}
+
+	for i := range l.Variants {
+		if l.Variants[i].Weight <= 0 {
+			continue
+		}
+		if n < l.Variants[i].Weight {
+			return &l.Variants[i]
+		}
+		n -= l.Variants[i].Weight
+	}
+	return nil
+}
+
+// RedirectRuleField is the visitor attribute a redirect rule matches on.
+type RedirectRuleField string
+
+const (
+	RuleFieldCountry    RedirectRuleField = "country"     // ISO country code from the edge proxy
+	RuleFieldDeviceType RedirectRuleField = "device_type" // "mobile" or "desktop"
+)
+
+// RedirectRule sends visitors whose attribute is one of Values to URL,
+// e.g. country in [DE, AT] -> https://shop.example.de.
+type RedirectRule struct {
+	Field  RedirectRuleField `json:"field" validate:"oneof=country device_type"`
+	Values []string          `json:"values" validate:"min=1"`
+	URL    string            `json:"url" validate:"required,url"`
+}
+
+// Matches reports whether the rule applies to a visitor.
+// Country codes are compared case-insensitively.
+func (r RedirectRule) Matches(country, deviceType string) bool {
+	var actual string
+	switch r.Field {
+	case RuleFieldCountry:
+		actual = country
+	case RuleFieldDeviceType:
+		actual = deviceType
+	}
+	if actual == "" {
+		return false
+	}
+	for _, v := range r.Values {
+		if strings.EqualFold(v, actual) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRule returns the first rule matching the visitor, or nil.
+func (l *Link) MatchRule(country, deviceType string) *RedirectRule {
+	for i := range l.Rules {
+		if l.Rules[i].Matches(country, deviceType) {
+			return &l.Rules[i]
+		}
+	}
+	return nil
 }
 
+// LinkRepository stores links. Trashed links are stored and listed like any other;
+// hiding them is up to the service layer.
 type LinkRepository interface {
 	Save(ctx context.Context, link *Link) error
 	GetByID(ctx context.Context, id LinkID) (*Link, error)
+	GetBySlug(ctx context.Context, slug string) (*Link, error)
 	ListByUser(ctx context.Context, userID UserID) ([]*Link, error)
+	// Delete removes a link permanently. Its analytics events are kept for the
+	// owner's totals but detached from the link.
 	Delete(ctx context.Context, id LinkID) error
-	Reorder(ctx context.Context, userID UserID, linkIDs []LinkID) error
+	Reorder(ctx context.Context, userID UserID, placements []LinkPlacement) error
+	// CountClick atomically adds a click to the link, and a unique visitor when
+	// visitorID has not clicked it before, unless that would go over MaxClicks or
+	// MaxVisitors. It reports whether the click was counted; a refused click
+	// changes nothing. An empty visitorID counts as a new visitor every time.
+	// Save never overwrites the counters.
+	CountClick(ctx context.Context, id LinkID, visitorID string) (bool, error)
 }
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type LinkGroupID string
+
+// LinkGroup is a titled section of a profile. Links join a group through Link.GroupID;
+// links without a group are rendered above all sections.
+type LinkGroup struct {
+	ID          LinkGroupID `json:"id"`
+	UserID      UserID      `json:"user_id"`
+	Title       string      `json:"title" validate:"required,max=100"`
+	Description string      `json:"description,omitempty" validate:"max=280"`
+	Collapsed   bool        `json:"collapsed"` // section starts folded on the public profile
+	Order       int         `json:"order"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// LinkPlacement positions a link in its owner's layout: Reorder gives each link the
+// index of its placement and moves it into GroupID (empty means no group).
+type LinkPlacement struct {
+	LinkID  LinkID      `json:"link_id"`
+	GroupID LinkGroupID `json:"group_id,omitempty"`
+}
+
+type LinkGroupRepository interface {
+	Save(ctx context.Context, group *LinkGroup) error
+	GetByID(ctx context.Context, id LinkGroupID) (*LinkGroup, error)
+	ListByUser(ctx context.Context, userID UserID) ([]*LinkGroup, error)
+	Delete(ctx context.Context, id LinkGroupID) error
+	Reorder(ctx context.Context, userID UserID, groupIDs []LinkGroupID) error
+}
@@ -0,0 +1,34 @@
+package domain
+
+// LinkRecord is the portable form of a link used by bulk export and import.
+type LinkRecord struct {
+	Title    string            `json:"title" validate:"required,max=100"`
+	URL      string            `json:"url" validate:"required,url"`
+	Type     LinkType          `json:"type,omitempty" validate:"omitempty,oneof=standard social product embed"`
+	Order    int               `json:"order" validate:"min=0"`
+	IsActive bool              `json:"is_active"`
+	Slug     string            `json:"slug,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// LinkImportMode decides what happens to a user's existing links on import.
+type LinkImportMode string
+
+const (
+	ImportMerge   LinkImportMode = "merge"   // keep existing links, append new ones, skip duplicate URLs
+	ImportReplace LinkImportMode = "replace" // delete existing links first; nothing changes if any row is invalid
+)
+
+// LinkImportIssue describes a rejected or skipped import row. Rows are 1-based.
+type LinkImportIssue struct {
+	Row     int    `json:"row"`
+	URL     string `json:"url,omitempty"`
+	Message string `json:"message"`
+}
+
+// LinkImportResult summarizes a bulk import.
+type LinkImportResult struct {
+	Created int               `json:"created"`
+	Skipped []LinkImportIssue `json:"skipped,omitempty"` // duplicates of existing or earlier rows
+	Errors  []LinkImportIssue `json:"errors,omitempty"`  // rows that failed validation
+}
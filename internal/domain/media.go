@@ -12,3 +12,12 @@ type MediaUploader interface {
 	// GetURL returns the full accessible URL for the given key, handling CDN replacement if configured.
 	GetURL(key string) string
 }
+
+// ThumbnailStore keeps link preview images on our own storage so profile pages
+// never hotlink third-party sites.
+type ThumbnailStore interface {
+	// StoreThumbnail resizes and re-encodes an uploaded image and returns its public URL.
+	StoreThumbnail(ctx context.Context, file io.Reader, filename string) (string, error)
+	// CacheThumbnail downloads the image at imageURL and stores it like StoreThumbnail.
+	CacheThumbnail(ctx context.Context, imageURL, filename string) (string, error)
+}
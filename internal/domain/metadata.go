@@ -8,6 +8,13 @@ type LinkMetadata struct {
 	Description string `json:"description"`
 	ImageURL    string `json:"image_url"`
 	URL         string `json:"url"`
+
+	// Embed data discovered via oEmbed; empty when the URL has no supported embed.
+	EmbedProvider string `json:"embed_provider,omitempty"`
+	EmbedURL      string `json:"embed_url,omitempty"`  // iframe src on an allow-listed host
+	EmbedHTML     string `json:"embed_html,omitempty"` // sanitized markup for providers without an iframe (e.g. posts)
+	EmbedWidth    int    `json:"embed_width,omitempty"`
+	EmbedHeight   int    `json:"embed_height,omitempty"`
 }
 
 // MetadataFetcher defines the contract for fetching SEO metadata from a URL.
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type LinkRevisionID string
+
+// RevisionAction is the kind of change a revision records.
+type RevisionAction string
+
+const (
+	RevisionUpdate  RevisionAction = "update"
+	RevisionRefresh RevisionAction = "refresh" // metadata refetched from the destination
+	RevisionDelete  RevisionAction = "delete"  // moved to the trash
+	RevisionRestore RevisionAction = "restore" // taken back out of the trash
+	RevisionRevert  RevisionAction = "revert"  // an earlier revision was restored
+)
+
+// LinkRevision is an immutable record of one change to a link.
+// Before is nil when the change recreated a deleted link; After is nil for deletions.
+// Snapshots never carry the password hash (Link.PasswordHash is not serialized).
+type LinkRevision struct {
+	ID        LinkRevisionID `json:"id"`
+	LinkID    LinkID         `json:"link_id"`
+	UserID    UserID         `json:"user_id"`  // owner of the link
+	ActorID   UserID         `json:"actor_id"` // who made the change
+	Action    RevisionAction `json:"action"`
+	Before    *Link          `json:"before,omitempty"`
+	After     *Link          `json:"after,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ChangedFields lists the user-facing fields that differ between Before and After.
+func (r *LinkRevision) ChangedFields() []string {
+	if r.Before == nil || r.After == nil {
+		return nil
+	}
+	var fields []string
+	if r.Before.Title != r.After.Title {
+		fields = append(fields, "title")
+	}
+	if r.Before.URL != r.After.URL {
+		fields = append(fields, "url")
+	}
+	if r.Before.Type != r.After.Type {
+		fields = append(fields, "type")
+	}
+	if r.Before.IsActive != r.After.IsActive {
+		fields = append(fields, "active")
+	}
+	if r.Before.Slug != r.After.Slug {
+		fields = append(fields, "slug")
+	}
+	if !sameMetadata(r.Before.Metadata, r.After.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	return fields
+}
+
+func sameMetadata(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// LinkRevisionRepository stores revisions. Revisions are append-only: there is
+// no update or delete, and they outlive the link they describe.
+type LinkRevisionRepository interface {
+	Save(ctx context.Context, revision *LinkRevision) error
+	GetByID(ctx context.Context, id LinkRevisionID) (*LinkRevision, error)
+	ListByLink(ctx context.Context, linkID LinkID) ([]*LinkRevision, error)            // newest first
+	ListByUser(ctx context.Context, userID UserID, limit int) ([]*LinkRevision, error) // newest first
+}
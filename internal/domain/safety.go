@@ -0,0 +1,8 @@
+package domain
+
+// LinkSafetyChecker defines the contract for vetting outbound link destinations
+// against a scheme allowlist, a domain blocklist and spoofed (homograph) domains.
+type LinkSafetyChecker interface {
+	// Check returns an error wrapping ErrUnsafeURL if rawURL must not be linked to.
+	Check(rawURL string) error
+}
@@ -1,12 +1,19 @@
 package domain
 
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
 // SocialPlatform represents a detected social media platform and its styling properties.
 type SocialPlatform struct {
-	Name    string `json:"name"`
-	Domain  string `json:"domain"`
-	IconSVG string `json:"icon_svg"`
-	Color   string `json:"color"`
-	Effect  string `json:"effect,omitempty"`
+	Name     string           `json:"name"`
+	Domain   string           `json:"domain"`
+	IconSVG  string           `json:"icon_svg"`
+	Color    string           `json:"color"`
+	Effect   string           `json:"effect,omitempty"`
+	AppLinks AppLinkTemplates `json:"app_links,omitempty"`
 }
 
 // SocialResolver defines the contract for resolving a URL to a widely known social platform.
@@ -14,3 +21,93 @@ type SocialResolver interface {
 	// Resolve returns a SocialPlatform if the url matches a known platform, or nil if not found.
 	Resolve(url string) (*SocialPlatform, error)
 }
+
+// MobileOS is the visitor's phone operating system, as far as deep links care.
+type MobileOS string
+
+const (
+	MobileOSIOS     MobileOS = "ios"
+	MobileOSAndroid MobileOS = "android"
+)
+
+// AppLinkTemplates are the URIs that open a platform's native app, built from the web URL.
+// Placeholders: {host}, {path} (without the leading slash), {query} ("?..." or empty),
+// {url} (the whole web URL, query-escaped) and {1}..{9} (path segments).
+type AppLinkTemplates struct {
+	IOS     string `json:"ios,omitempty"`     // App URI scheme, e.g. "youtube://{path}{query}"
+	Android string `json:"android,omitempty"` // Intent URL, e.g. "intent://{host}/{path}{query}#Intent;scheme=https;package=...;end"
+	// Paths are templates for web URLs of a given shape; the first matching one
+	// with a template for the OS wins over IOS/Android.
+	Paths []AppLinkPath `json:"paths,omitempty"`
+}
+
+// AppLinkPath holds the app URI templates for web URLs whose path (without
+// surrounding slashes) matches Pattern, a path.Match pattern such as "p/*".
+type AppLinkPath struct {
+	Pattern string `json:"pattern"`
+	IOS     string `json:"ios,omitempty"`
+	Android string `json:"android,omitempty"`
+}
+
+// template returns the template for os and the given web URL path.
+func (t AppLinkTemplates) template(os MobileOS, urlPath string) string {
+	pick := func(ios, android string) string {
+		switch os {
+		case MobileOSIOS:
+			return ios
+		case MobileOSAndroid:
+			return android
+		}
+		return ""
+	}
+	for _, p := range t.Paths {
+		if ok, _ := path.Match(p.Pattern, urlPath); !ok {
+			continue
+		}
+		if tmpl := pick(p.IOS, p.Android); tmpl != "" {
+			return tmpl
+		}
+	}
+	return pick(t.IOS, t.Android)
+}
+
+// For returns the app URI opening webURL on os, or an empty string when the
+// platform has no template for os and the shape of webURL, or webURL lacks a
+// path segment the template needs.
+func (t AppLinkTemplates) For(os MobileOS, webURL string) string {
+	u, err := url.Parse(webURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	path := strings.Trim(u.EscapedPath(), "/")
+	tmpl := t.template(os, path)
+	if tmpl == "" {
+		return ""
+	}
+	query := ""
+	if u.RawQuery != "" {
+		query = "?" + u.RawQuery
+	}
+
+	replacements := []string{
+		"{host}", u.Host,
+		"{path}", path,
+		"{query}", query,
+		"{url}", url.QueryEscape(webURL),
+	}
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, "/")
+	}
+	for i := 1; i <= 9; i++ {
+		placeholder := "{" + string(rune('0'+i)) + "}"
+		if i > len(segments) {
+			if strings.Contains(tmpl, placeholder) {
+				return ""
+			}
+			continue
+		}
+		replacements = append(replacements, placeholder, segments[i-1])
+	}
+	return strings.NewReplacer(replacements...).Replace(tmpl)
+}
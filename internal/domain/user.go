@@ -29,16 +29,18 @@ type Theme struct {
 }
 
 type User struct {
-	ID          UserID    `json:"id"`
-	Email       string    `json:"email" validate:"required,email"`
-	Handle      string    `json:"handle" validate:"required,min=3,max=30,excludesall= "`
-	Title       string    `json:"title,omitempty" validate:"max=100"`
-	Description string    `json:"description,omitempty" validate:"max=500"`
-	AvatarURL   string    `json:"avatar_url,omitempty" validate:"omitempty,url"`
-	SEOMeta     SEOMeta   `json:"seo_meta,omitempty"`
-	Theme       Theme     `json:"theme,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            UserID            `json:"id"`
+	Email         string            `json:"email" validate:"required,email"`
+	Handle        string            `json:"handle" validate:"required,min=3,max=30,excludesall= "`
+	Title         string            `json:"title,omitempty" validate:"max=100"`
+	Description   string            `json:"description,omitempty" validate:"max=500"`
+	AvatarURL     string            `json:"avatar_url,omitempty" validate:"omitempty,url"`
+	SEOMeta       SEOMeta           `json:"seo_meta,omitempty"`
+	Theme         Theme             `json:"theme,omitempty"`
+	AffiliateTags map[string]string `json:"affiliate_tags,omitempty"` // affiliate network ID -> the user's tag on it
+	UTM           UTMParams         `json:"utm,omitempty"`            // default campaign parameters for outbound clicks
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
 }
 
 // Repository interfaces define the contract for data persistence
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// UTMParams are the campaign parameters added to outbound clicks so they show up
+// attributed in the destination's own analytics.
+type UTMParams struct {
+	Source   string `json:"source,omitempty" validate:"max=100"`
+	Medium   string `json:"medium,omitempty" validate:"max=100"`
+	Campaign string `json:"campaign,omitempty" validate:"max=100"`
+}
+
+// IsZero reports whether no parameter is set.
+func (p UTMParams) IsZero() bool {
+	return p == UTMParams{}
+}
+
+// Override returns p with the fields set in o taking precedence.
+// A nil o leaves p unchanged.
+func (p UTMParams) Override(o *UTMParams) UTMParams {
+	if o == nil {
+		return p
+	}
+	if o.Source != "" {
+		p.Source = o.Source
+	}
+	if o.Medium != "" {
+		p.Medium = o.Medium
+	}
+	if o.Campaign != "" {
+		p.Campaign = o.Campaign
+	}
+	return p
+}
+
+// Apply appends the parameters to an http(s) destination. The existing query
+// string and fragment are kept as written, and a parameter the destination
+// already carries wins over the default. Other URLs are returned unchanged.
+func (p UTMParams) Apply(rawURL string) string {
+	if p.IsZero() {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return rawURL
+	}
+	existing, _ := url.ParseQuery(u.RawQuery)
+
+	added := url.Values{}
+	for key, value := range map[string]string{"utm_source": p.Source, "utm_medium": p.Medium, "utm_campaign": p.Campaign} {
+		if value != "" && !existing.Has(key) {
+			added.Set(key, value)
+		}
+	}
+	if len(added) == 0 {
+		return rawURL
+	}
+
+	query := strings.TrimSuffix(u.RawQuery, "&")
+	if query != "" {
+		query += "&"
+	}
+	u.RawQuery = query + added.Encode()
+	u.ForceQuery = false
+	return u.String()
+}
@@ -13,8 +13,11 @@ type MockAnalyticsRepository struct {
 	events []*domain.AnalyticsEvent
 
 	// Hooks for custom behavior
-	SaveEventFunc  func(ctx context.Context, event *domain.AnalyticsEvent) error
-	GetSummaryFunc func(ctx context.Context, userID string, linkID *string) (*domain.AnalyticsSummary, error)
+	SaveEventFunc       func(ctx context.Context, event *domain.AnalyticsEvent) error
+	GetSummaryFunc      func(ctx context.Context, userID string, linkID *string) (*domain.AnalyticsSummary, error)
+	GetVariantStatsFunc func(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error)
+	GetTimeSeriesFunc   func(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error)
+	GetTopLinksFunc     func(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error)
 }
 
 func NewMockAnalyticsRepository() *MockAnalyticsRepository {
@@ -43,21 +46,48 @@ func (m *MockAnalyticsRepository) GetSummary(ctx context.Context, userID string,
 	defer m.mu.RUnlock()
 
 	summary := &domain.AnalyticsSummary{
-		ByCountry: make(map[string]int64),
-		ByDevice:  make(map[string]int64),
+		ByCountry:  make(map[string]int64),
+		ByDevice:   make(map[string]int64),
+		ByReferrer: make(map[string]int64),
+		BySource:   make(map[string]int64),
+		ByCampaign: make(map[string]int64),
 	}
 
+	referrerType := domain.EventTypeView
+	if linkID != nil {
+		referrerType = domain.EventTypeClick
+	}
+
+	visitors := make(map[string]struct{})
 	for _, event := range m.events {
 		if event.UserID != nil && *event.UserID == userID {
 			if linkID != nil && event.LinkID != nil && *event.LinkID != *linkID {
 				continue
 			}
+			if event.IsBot {
+				switch event.EventType {
+				case domain.EventTypeView:
+					summary.BotViews++
+				case domain.EventTypeClick:
+					summary.BotClicks++
+				}
+				continue
+			}
 
+			qr := event.Meta["source"] == domain.SourceQR
 			switch event.EventType {
 			case domain.EventTypeView:
 				summary.TotalViews++
+				visitors[event.VisitorID] = struct{}{}
+				if qr {
+					summary.QRViews++
+				}
 			case domain.EventTypeClick:
 				summary.TotalClicks++
+				visitors[event.VisitorID] = struct{}{}
+				if qr {
+					summary.QRClicks++
+				}
 			}
 
 			if event.Country != "" {
@@ -66,12 +96,101 @@ func (m *MockAnalyticsRepository) GetSummary(ctx context.Context, userID string,
 			if device, ok := event.Meta["device_type"]; ok {
 				summary.ByDevice[device]++
 			}
+			if event.EventType == referrerType {
+				if referrer := event.Meta["referrer"]; referrer != "" {
+					summary.ByReferrer[referrer]++
+				}
+				if source := event.Meta["source"]; source != "" {
+					summary.BySource[source]++
+				}
+				if campaign := event.Meta["utm_campaign"]; campaign != "" {
+					summary.ByCampaign[campaign]++
+				}
+			}
 		}
 	}
+	summary.UniqueVisitors = int64(len(visitors))
 
 	return summary, nil
 }
 
+func (m *MockAnalyticsRepository) GetVariantStats(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error) {
+	if m.GetVariantStatsFunc != nil {
+		return m.GetVariantStatsFunc(ctx, userID, link)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	viewsByVisitor := make(map[string]int64)
+	clicksByVariant := make(map[string]int64)
+	for _, event := range m.events {
+		if event.UserID == nil || *event.UserID != userID || event.IsBot {
+			continue
+		}
+		switch event.EventType {
+		case domain.EventTypeView:
+			viewsByVisitor[event.VisitorID]++
+		case domain.EventTypeClick:
+			if event.LinkID != nil && *event.LinkID == string(link.ID) && event.Meta["variant"] != "" {
+				clicksByVariant[event.Meta["variant"]]++
+			}
+		}
+	}
+
+	return domain.BuildVariantStats(link, viewsByVisitor, clicksByVariant), nil
+}
+
+func (m *MockAnalyticsRepository) GetTimeSeries(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error) {
+	if m.GetTimeSeriesFunc != nil {
+		return m.GetTimeSeriesFunc(ctx, query)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counter := domain.NewTimeSeriesCounter(query)
+	for _, event := range m.events {
+		if event.UserID == nil || *event.UserID != query.UserID {
+			continue
+		}
+		if query.LinkID != nil && (event.LinkID == nil || *event.LinkID != *query.LinkID) {
+			continue
+		}
+		counter.Add(event)
+	}
+	return counter.Series(), nil
+}
+
+func (m *MockAnalyticsRepository) GetTopLinks(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error) {
+	if m.GetTopLinksFunc != nil {
+		return m.GetTopLinksFunc(ctx, userID, limit)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clicks := make(map[string]int64)
+	visitors := make(map[string]map[string]bool)
+	for _, event := range m.events {
+		if event.UserID == nil || *event.UserID != userID || event.EventType != domain.EventTypeClick || event.LinkID == nil || event.IsBot {
+			continue
+		}
+		clicks[*event.LinkID]++
+		if visitors[*event.LinkID] == nil {
+			visitors[*event.LinkID] = make(map[string]bool)
+		}
+		visitors[*event.LinkID][event.VisitorID] = true
+	}
+
+	top := make([]domain.LinkClicks, 0, len(clicks))
+	for linkID, count := range clicks {
+		top = append(top, domain.LinkClicks{LinkID: linkID, Clicks: count, UniqueVisitors: int64(len(visitors[linkID]))})
+	}
+	domain.SortLinkClicks(top)
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top, nil
+}
+
 // GetEvents returns all recorded events for assertions.
 func (m *MockAnalyticsRepository) GetEvents() []*domain.AnalyticsEvent {
 	m.mu.RLock()
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// MockLinkGroupRepository is a test double for domain.LinkGroupRepository.
+type MockLinkGroupRepository struct {
+	mu     sync.RWMutex
+	groups map[domain.LinkGroupID]*domain.LinkGroup
+
+	// Hooks for custom behavior
+	SaveFunc   func(ctx context.Context, group *domain.LinkGroup) error
+	DeleteFunc func(ctx context.Context, id domain.LinkGroupID) error
+}
+
+func NewMockLinkGroupRepository() *MockLinkGroupRepository {
+	return &MockLinkGroupRepository{
+		groups: make(map[domain.LinkGroupID]*domain.LinkGroup),
+	}
+}
+
+func (m *MockLinkGroupRepository) Save(ctx context.Context, group *domain.LinkGroup) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, group)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g := *group
+	m.groups[g.ID] = &g
+	return nil
+}
+
+func (m *MockLinkGroupRepository) GetByID(ctx context.Context, id domain.LinkGroupID) (*domain.LinkGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if group, ok := m.groups[id]; ok {
+		g := *group
+		return &g, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *MockLinkGroupRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.LinkGroup
+	for _, group := range m.groups {
+		if group.UserID == userID {
+			g := *group
+			result = append(result, &g)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Order < result[j].Order
+	})
+	return result, nil
+}
+
+func (m *MockLinkGroupRepository) Delete(ctx context.Context, id domain.LinkGroupID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.groups, id)
+	return nil
+}
+
+func (m *MockLinkGroupRepository) Reorder(ctx context.Context, userID domain.UserID, groupIDs []domain.LinkGroupID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, id := range groupIDs {
+		if group, ok := m.groups[id]; ok && group.UserID == userID {
+			group.Order = i
+		}
+	}
+	return nil
+}
+
+// AddGroup is a helper to seed groups for tests.
+func (m *MockLinkGroupRepository) AddGroup(group *domain.LinkGroup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g := *group
+	m.groups[g.ID] = &g
+}
@@ -10,20 +10,24 @@ import (
 
 // MockLinkRepository is a test double for domain.LinkRepository.
 type MockLinkRepository struct {
-	mu    sync.RWMutex
-	links map[domain.LinkID]*domain.Link
+	mu       sync.RWMutex
+	links    map[domain.LinkID]*domain.Link
+	visitors map[domain.LinkID]map[string]bool
 
 	// Hooks for custom behavior
 	SaveFunc       func(ctx context.Context, link *domain.Link) error
 	GetByIDFunc    func(ctx context.Context, id domain.LinkID) (*domain.Link, error)
+	GetBySlugFunc  func(ctx context.Context, slug string) (*domain.Link, error)
 	ListByUserFunc func(ctx context.Context, userID domain.UserID) ([]*domain.Link, error)
 	DeleteFunc     func(ctx context.Context, id domain.LinkID) error
-	ReorderFunc    func(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error
+	ReorderFunc    func(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error
+	CountClickFunc func(ctx context.Context, id domain.LinkID, visitorID string) (bool, error)
 }
 
 func NewMockLinkRepository() *MockLinkRepository {
 	return &MockLinkRepository{
-		links: make(map[domain.LinkID]*domain.Link),
+		links:    make(map[domain.LinkID]*domain.Link),
+		visitors: make(map[domain.LinkID]map[string]bool),
 	}
 }
 
@@ -34,7 +38,19 @@ func (m *MockLinkRepository) Save(ctx context.Context, link *domain.Link) error
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if link.Slug != "" {
+		for _, existing := range m.links {
+			if existing.Slug == link.Slug && existing.ID != link.ID {
+				return domain.ErrConflict
+			}
+		}
+	}
+
 	l := *link
+	if existing, ok := m.links[l.ID]; ok {
+		l.ClickCount = existing.ClickCount
+		l.UniqueVisitors = existing.UniqueVisitors
+	}
 	m.links[l.ID] = &l
 	return nil
 }
@@ -52,6 +68,21 @@ func (m *MockLinkRepository) GetByID(ctx context.Context, id domain.LinkID) (*do
 	return nil, domain.ErrNotFound
 }
 
+func (m *MockLinkRepository) GetBySlug(ctx context.Context, slug string) (*domain.Link, error) {
+	if m.GetBySlugFunc != nil {
+		return m.GetBySlugFunc(ctx, slug)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, link := range m.links {
+		if link.Slug == slug {
+			return link, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
 func (m *MockLinkRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
 	if m.ListByUserFunc != nil {
 		return m.ListByUserFunc(ctx, userID)
@@ -82,19 +113,53 @@ func (m *MockLinkRepository) Delete(ctx context.Context, id domain.LinkID) error
 	defer m.mu.Unlock()
 
 	delete(m.links, id)
+	delete(m.visitors, id)
 	return nil
 }
 
-func (m *MockLinkRepository) Reorder(ctx context.Context, userID domain.UserID, linkIDs []domain.LinkID) error {
+func (m *MockLinkRepository) CountClick(ctx context.Context, id domain.LinkID, visitorID string) (bool, error) {
+	if m.CountClickFunc != nil {
+		return m.CountClickFunc(ctx, id, visitorID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	link, ok := m.links[id]
+	if !ok {
+		return false, domain.ErrNotFound
+	}
+	newVisitor := visitorID == "" || !m.visitors[id][visitorID]
+	if link.MaxClicks > 0 && link.ClickCount >= link.MaxClicks {
+		return false, nil
+	}
+	if newVisitor && link.MaxVisitors > 0 && link.UniqueVisitors >= link.MaxVisitors {
+		return false, nil
+	}
+
+	link.ClickCount++
+	if newVisitor {
+		link.UniqueVisitors++
+		if visitorID != "" {
+			if m.visitors[id] == nil {
+				m.visitors[id] = make(map[string]bool)
+			}
+			m.visitors[id][visitorID] = true
+		}
+	}
+	return true, nil
+}
+
+func (m *MockLinkRepository) Reorder(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
 	if m.ReorderFunc != nil {
-		return m.ReorderFunc(ctx, userID, linkIDs)
+		return m.ReorderFunc(ctx, userID, placements)
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for i, id := range linkIDs {
-		if link, ok := m.links[id]; ok && link.UserID == userID {
+	for i, p := range placements {
+		if link, ok := m.links[p.LinkID]; ok && link.UserID == userID {
 			link.Order = i
+			link.GroupID = p.GroupID
 		}
 	}
 	return nil
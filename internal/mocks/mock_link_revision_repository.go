@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// MockLinkRevisionRepository is a test double for domain.LinkRevisionRepository.
+// Revisions are kept in insertion order and listed newest first.
+type MockLinkRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions []*domain.LinkRevision
+
+	// Hooks for custom behavior
+	SaveFunc func(ctx context.Context, revision *domain.LinkRevision) error
+}
+
+func NewMockLinkRevisionRepository() *MockLinkRevisionRepository {
+	return &MockLinkRevisionRepository{}
+}
+
+func (m *MockLinkRevisionRepository) Save(ctx context.Context, revision *domain.LinkRevision) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, revision)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.revisions {
+		if existing.ID == revision.ID {
+			return domain.ErrConflict
+		}
+	}
+	r := *revision
+	m.revisions = append(m.revisions, &r)
+	return nil
+}
+
+func (m *MockLinkRevisionRepository) GetByID(ctx context.Context, id domain.LinkRevisionID) (*domain.LinkRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, revision := range m.revisions {
+		if revision.ID == id {
+			r := *revision
+			return &r, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *MockLinkRevisionRepository) ListByLink(ctx context.Context, linkID domain.LinkID) ([]*domain.LinkRevision, error) {
+	return m.list(func(r *domain.LinkRevision) bool { return r.LinkID == linkID }, 0), nil
+}
+
+func (m *MockLinkRevisionRepository) ListByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	return m.list(func(r *domain.LinkRevision) bool { return r.UserID == userID }, limit), nil
+}
+
+func (m *MockLinkRevisionRepository) list(match func(*domain.LinkRevision) bool, limit int) []*domain.LinkRevision {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.LinkRevision
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		if !match(m.revisions[i]) {
+			continue
+		}
+		r := *m.revisions[i]
+		result = append(result, &r)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// MockLinkSafetyChecker is a test double for domain.LinkSafetyChecker.
+// URLs containing a blocked substring are rejected; everything else is allowed.
+type MockLinkSafetyChecker struct {
+	mu      sync.RWMutex
+	blocked []string
+
+	// Track method calls
+	CheckCalls []string
+}
+
+func NewMockLinkSafetyChecker(blocked ...string) *MockLinkSafetyChecker {
+	return &MockLinkSafetyChecker{blocked: blocked}
+}
+
+func (m *MockLinkSafetyChecker) Check(rawURL string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckCalls = append(m.CheckCalls, rawURL)
+
+	for _, pattern := range m.blocked {
+		if contains(rawURL, pattern) {
+			return fmt.Errorf("%q is blocked: %w", pattern, domain.ErrUnsafeURL)
+		}
+	}
+	return nil
+}
+
+// Block rejects URLs containing pattern from now on, like a blocklist update.
+func (m *MockLinkSafetyChecker) Block(pattern string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocked = append(m.blocked, pattern)
+}
@@ -2,8 +2,11 @@ package mocks
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 )
 
 // MockSessionManager is a test double for ports.SessionManager.
@@ -67,6 +70,30 @@ func (m *MockSessionManager) ClearSession(ctx context.Context, w http.ResponseWr
 	return nil
 }
 
+// mockSignaturePrefix marks cookie values "signed" by the mock.
+const mockSignaturePrefix = "signed:"
+
+func (m *MockSessionManager) SetSignedValue(w http.ResponseWriter, name, value string, ttl time.Duration) error {
+	http.SetCookie(w, &http.Cookie{
+		Name:    name,
+		Value:   mockSignaturePrefix + value,
+		Path:    "/",
+		Expires: time.Now().Add(ttl),
+	})
+	return nil
+}
+
+func (m *MockSessionManager) GetSignedValue(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(cookie.Value, mockSignaturePrefix) {
+		return "", fmt.Errorf("invalid %s", name)
+	}
+	return strings.TrimPrefix(cookie.Value, mockSignaturePrefix), nil
+}
+
 // SetCurrentUser is a helper to set the logged-in user for tests.
 func (m *MockSessionManager) SetCurrentUser(userID string) {
 	m.mu.Lock()
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// MockThumbnailStore is a test double for domain.ThumbnailStore.
+// Stored thumbnails get a URL under BaseURL named after the requested filename.
+type MockThumbnailStore struct {
+	mu sync.Mutex
+
+	// Configurable responses
+	BaseURL  string
+	StoreErr error
+	CacheErr error
+
+	// Track method calls
+	StoreCalls []string // filenames
+	CacheCalls []string // remote image URLs
+}
+
+func NewMockThumbnailStore() *MockThumbnailStore {
+	return &MockThumbnailStore{BaseURL: "https://cdn.example.com"}
+}
+
+func (m *MockThumbnailStore) StoreThumbnail(ctx context.Context, file io.Reader, filename string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.StoreCalls = append(m.StoreCalls, filename)
+
+	if m.StoreErr != nil {
+		return "", m.StoreErr
+	}
+	return m.BaseURL + "/" + filename + ".webp", nil
+}
+
+func (m *MockThumbnailStore) CacheThumbnail(ctx context.Context, imageURL, filename string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CacheCalls = append(m.CacheCalls, imageURL)
+
+	if m.CacheErr != nil {
+		return "", m.CacheErr
+	}
+	return m.BaseURL + "/" + filename + ".webp", nil
+}
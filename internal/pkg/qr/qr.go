@@ -0,0 +1,214 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Level is the error-correction level of a QR code: L, M, Q or H.
+// Higher levels survive more damage at the cost of denser codes.
+type Level string
+
+const (
+	LevelLow      Level = "L" // ~7% recovery
+	LevelMedium   Level = "M" // ~15% recovery
+	LevelQuartile Level = "Q" // ~25% recovery
+	LevelHigh     Level = "H" // ~30% recovery
+)
+
+// Size limits in pixels.
+const (
+	DefaultSize = 512
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// logoRatio is the share of the code's width covered by a centred logo.
+// It stays well under what LevelHigh can recover.
+const logoRatio = 0.22
+
+// Options control how a QR code is rendered.
+type Options struct {
+	Size       int         // width and height in pixels; DefaultSize when zero
+	Level      Level       // LevelMedium when empty; raised to LevelHigh when a logo is drawn
+	Foreground color.Color // black when nil
+	Background color.Color // white when nil
+	Logo       image.Image // optional: drawn in the centre, e.g. the profile avatar
+}
+
+// ParseLevel parses an error-correction level, case-insensitively.
+func ParseLevel(s string) (Level, error) {
+	switch level := Level(strings.ToUpper(strings.TrimSpace(s))); level {
+	case LevelLow, LevelMedium, LevelQuartile, LevelHigh:
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown error-correction level %q", s)
+}
+
+// ParseHexColor parses a #RRGGBB or #RGB colour; the leading # is optional.
+func ParseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("invalid colour %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid colour %q", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// PNG renders content as a QR code PNG.
+func PNG(content string, opts Options) ([]byte, error) {
+	opts = opts.withDefaults()
+	bitmap, err := encode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Size, opts.Size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	// Whole pixels per module keep edges sharp; the remainder becomes extra margin
+	n := len(bitmap)
+	scale := max(opts.Size/n, 1)
+	offset := (opts.Size - n*scale) / 2
+	fg := image.NewUniform(opts.Foreground)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				r := image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale)
+				draw.Draw(img, r, fg, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	if opts.Logo != nil {
+		box := logoBox(opts.Size)
+		pad := box.Inset(-box.Dx() / 10)
+		draw.Draw(img, pad, image.NewUniform(opts.Background), image.Point{}, draw.Src)
+		logo := imaging.Fill(opts.Logo, box.Dx(), box.Dy(), imaging.Center, imaging.Lanczos)
+		draw.Draw(img, box, logo, image.Point{}, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders content as a QR code SVG. A logo is embedded as a PNG data URI
+// so the file stays self-contained for printing.
+func SVG(content string, opts Options) ([]byte, error) {
+	opts = opts.withDefaults()
+	bitmap, err := encode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	n := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, opts.Size, opts.Size, n, n)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, n, n, hexColor(opts.Background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(opts.Foreground))
+	for y, row := range bitmap {
+		for x := 0; x < n; x++ {
+			if !row[x] {
+				continue
+			}
+			// Merge horizontal runs of dark modules into one rectangle
+			run := 1
+			for x+run < n && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", x, y, run, run)
+			x += run - 1
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		side := float64(n) * logoRatio
+		pos := (float64(n) - side) / 2
+		pad := side / 10
+		logo := imaging.Fill(opts.Logo, 256, 256, imaging.Center, imaging.Lanczos)
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, logo); err != nil {
+			return nil, fmt.Errorf("encode logo: %w", err)
+		}
+		fmt.Fprintf(&buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`, pos-pad, pos-pad, side+2*pad, side+2*pad, hexColor(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%.2f" y="%.2f" width="%.2f" height="%.2f" href="%s"/>`, pos, pos, side, side,
+			html.EscapeString("data:image/png;base64,"+base64.StdEncoding.EncodeToString(logoPNG.Bytes())))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// withDefaults fills in unset options and clamps the size.
+func (o Options) withDefaults() Options {
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	o.Size = min(max(o.Size, MinSize), MaxSize)
+	if o.Level == "" {
+		o.Level = LevelMedium
+	}
+	if o.Logo != nil {
+		o.Level = LevelHigh
+	}
+	if o.Foreground == nil {
+		o.Foreground = color.Black
+	}
+	if o.Background == nil {
+		o.Background = color.White
+	}
+	return o
+}
+
+// encode returns the modules of the QR code, including the quiet zone.
+func encode(content string, opts Options) ([][]bool, error) {
+	levels := map[Level]qrcode.RecoveryLevel{
+		LevelLow:      qrcode.Low,
+		LevelMedium:   qrcode.Medium,
+		LevelQuartile: qrcode.High,
+		LevelHigh:     qrcode.Highest,
+	}
+	level, ok := levels[opts.Level]
+	if !ok {
+		return nil, fmt.Errorf("unknown error-correction level %q", opts.Level)
+	}
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("encode qr code: %w", err)
+	}
+	return code.Bitmap(), nil
+}
+
+// logoBox returns the centred square covered by the logo in a code of size pixels.
+func logoBox(size int) image.Rectangle {
+	side := int(float64(size) * logoRatio)
+	pos := (size - side) / 2
+	return image.Rect(pos, pos, pos+side, pos+side)
+}
+
+// hexColor formats c as #rrggbb for SVG attributes.
+func hexColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
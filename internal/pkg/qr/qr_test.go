@@ -0,0 +1,101 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Level
+		wantErr  bool
+	}{
+		{"L", LevelLow, false},
+		{"m", LevelMedium, false},
+		{" q ", LevelQuartile, false},
+		{"H", LevelHigh, false},
+		{"X", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseLevel(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected color.Color
+		wantErr  bool
+	}{
+		{"#6366F1", color.RGBA{R: 0x63, G: 0x66, B: 0xf1, A: 0xff}, false},
+		{"22c55e", color.RGBA{R: 0x22, G: 0xc5, B: 0x5e, A: 0xff}, false},
+		{"#fff", color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, false},
+		{"#12345", nil, true},
+		{"#gggggg", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseHexColor(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseHexColor(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	fg := color.RGBA{R: 0x63, G: 0x66, B: 0xf1, A: 0xff}
+	data, err := PNG("https://driplnk.com/alice?source=qr", Options{Size: 300, Foreground: fg})
+	if err != nil {
+		t.Fatalf("PNG failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 300 {
+		t.Errorf("expected 300x300, got %dx%d", b.Dx(), b.Dy())
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}) {
+		t.Errorf("expected a white quiet zone, got %v", got)
+	}
+
+	// The top-left finder pattern starts right after the quiet zone
+	found := false
+	for i := 0; i < 150 && !found; i++ {
+		found = color.RGBAModel.Convert(img.At(i, i)) == fg
+	}
+	if !found {
+		t.Error("expected modules in the foreground colour")
+	}
+
+	if _, err := PNG("x", Options{Size: 1 << 20}); err != nil {
+		t.Errorf("expected oversized codes to be clamped, got %v", err)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	data, err := SVG("https://driplnk.com/go/abc?source=qr", Options{Size: 256, Background: color.RGBA{R: 0xff, G: 0xee, B: 0xdd, A: 0xff}, Logo: logo})
+	if err != nil {
+		t.Fatalf("SVG failed: %v", err)
+	}
+	svg := string(data)
+	for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256"`, `fill="#ffeedd"`, `fill="#000000"`, `href="data:image/png;base64,`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("expected SVG to contain %q", want)
+		}
+	}
+	if !strings.HasSuffix(svg, "</svg>") {
+		t.Error("expected a closed SVG document")
+	}
+}
@@ -10,6 +10,8 @@ import (
 var (
 	strictPolicy     *bluemonday.Policy
 	strictPolicyOnce sync.Once
+	ugcPolicy        *bluemonday.Policy
+	ugcPolicyOnce    sync.Once
 )
 
 func getStrictPolicy() *bluemonday.Policy {
@@ -19,6 +21,15 @@ func getStrictPolicy() *bluemonday.Policy {
 	return strictPolicy
 }
 
+func getUGCPolicy() *bluemonday.Policy {
+	ugcPolicyOnce.Do(func() {
+		ugcPolicy = bluemonday.UGCPolicy()
+		ugcPolicy.RequireNoFollowOnLinks(true)
+		ugcPolicy.AddTargetBlankToFullyQualifiedLinks(true)
+	})
+	return ugcPolicy
+}
+
 // Normalize trims leading and trailing whitespace.
 func Normalize(s string) string {
 	return strings.TrimSpace(s)
@@ -30,6 +41,12 @@ func SanitizeInput(s string) string {
 	return getStrictPolicy().Sanitize(s)
 }
 
+// SanitizeHTML keeps only safe formatting markup (paragraphs, links, quotes) from
+// third-party HTML such as oEmbed snippets. Scripts, iframes, styles and event handlers are removed.
+func SanitizeHTML(s string) string {
+	return strings.TrimSpace(getUGCPolicy().Sanitize(s))
+}
+
 // SanitizeURL normalizes a URL string.
 // It trims whitespace and ensures the URL starts with http:// or https://.
 // If no scheme is present, it defaults to https://.
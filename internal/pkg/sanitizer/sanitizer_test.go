@@ -46,6 +46,27 @@ func TestSanitizeInput(t *testing.T) {
 	}
 }
 
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Script removed", `<blockquote><p>Hi</p></blockquote><script src="https://x.test/w.js"></script>`, "<blockquote><p>Hi</p></blockquote>"},
+		{"Iframe removed", `<iframe src="https://evil.test"></iframe>`, ""},
+		{"Event handler removed", `<p onclick="alert(1)">Hi</p>`, "<p>Hi</p>"},
+		{"Links are nofollow", `<a href="https://x.com/a">a</a>`, `<a href="https://x.com/a" rel="nofollow noopener" target="_blank">a</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.input); got != tt.expected {
+				t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestSanitizeURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,77 @@
+package urlcanon
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only carry campaign or click
+// attribution and never change the page a URL points to.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"gbraid":  true,
+	"wbraid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"_gl":     true,
+}
+
+// isTrackingParam reports whether a query parameter is dropped by Canonicalize.
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
+
+// Canonicalize returns a normalized form of a URL for comparing destinations.
+// It lowercases the scheme and host, strips the default port and trailing slash,
+// drops tracking parameters and sorts the remaining query parameters.
+// Values that do not parse as absolute URLs are returned trimmed but otherwise unchanged.
+//
+// The result is meant for comparison only; links keep the URL their owner entered.
+func Canonicalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		host = "[" + host + "]" // IPv6 literal
+	}
+	u.Host = host
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+
+	query := u.Query()
+	for key := range query {
+		if isTrackingParam(key) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode sorts by key
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// Same reports whether two URLs point to the same destination once canonicalized.
+func Same(a, b string) bool {
+	return Canonicalize(a) == Canonicalize(b)
+}
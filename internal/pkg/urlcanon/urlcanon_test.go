@@ -0,0 +1,42 @@
+package urlcanon
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Unchanged", "https://example.com/page?a=1", "https://example.com/page?a=1"},
+		{"Lowercase host", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"Default HTTPS port", "https://example.com:443/a", "https://example.com/a"},
+		{"Default HTTP port", "http://example.com:80/a", "http://example.com/a"},
+		{"Other port kept", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"Trailing slash", "https://example.com/a/", "https://example.com/a"},
+		{"Root slash", "https://example.com/", "https://example.com"},
+		{"Sorted query", "https://example.com/?b=2&a=1", "https://example.com?a=1&b=2"},
+		{"Tracking params", "https://example.com/?utm_source=x&UTM_Medium=y&fbclid=z&id=7", "https://example.com?id=7"},
+		{"Only tracking params", "https://example.com/a?gclid=1", "https://example.com/a"},
+		{"Fragment kept", "https://example.com/#top", "https://example.com#top"},
+		{"IPv6 host", "http://[::1]:80/", "http://[::1]"},
+		{"IPv6 host with port", "http://[::1]:8080/", "http://[::1]:8080"},
+		{"Surrounding space", "  https://example.com  ", "https://example.com"},
+		{"Not absolute", "mailto:me@example.com", "mailto:me@example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := Canonicalize(tt.input); got != tt.expected {
+			t.Errorf("%s: Canonicalize(%q) = %q, want %q", tt.name, tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSame(t *testing.T) {
+	if !Same("https://Example.com/shop/?utm_campaign=x", "https://example.com:443/shop") {
+		t.Error("expected URLs to be the same destination")
+	}
+	if Same("https://example.com/a", "https://example.com/b") {
+		t.Error("expected different paths to differ")
+	}
+}
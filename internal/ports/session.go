@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 // SessionManager defines how sessions are created, retrieved, and destroyed.
@@ -17,4 +18,11 @@ type SessionManager interface {
 
 	// ClearSession invalidates/removes the session from the response.
 	ClearSession(ctx context.Context, w http.ResponseWriter) error
+
+	// SetSignedValue stores a tamper-proof value in the cookie name for ttl.
+	SetSignedValue(w http.ResponseWriter, name, value string, ttl time.Duration) error
+
+	// GetSignedValue returns the verified value of the cookie name.
+	// Returns empty string and error if the cookie is missing or its signature is invalid.
+	GetSignedValue(r *http.Request, name string) (string, error)
 }
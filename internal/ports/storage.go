@@ -10,4 +10,6 @@ type FileUploader interface {
 	Upload(ctx context.Context, file io.Reader, filename string) (string, error)
 	// UploadProfileImage processes (resizes/converts) and uploads a profile image.
 	UploadProfileImage(ctx context.Context, file io.Reader, filename string) (string, error)
+	// UploadThumbnail processes (crops/converts) and uploads a link preview image.
+	UploadThumbnail(ctx context.Context, file io.Reader, filename string) (string, error)
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
@@ -40,6 +41,10 @@ func (s *AnalyticsService) TrackEvent(ctx context.Context, eventType domain.Anal
 	if r, ok := meta["region"]; ok {
 		event.Region = r
 	}
+	if meta[domain.MetaIsBot] == "true" {
+		event.IsBot = true
+		delete(meta, domain.MetaIsBot)
+	}
 
 	return s.repo.SaveEvent(ctx, event)
 }
@@ -47,3 +52,63 @@ func (s *AnalyticsService) TrackEvent(ctx context.Context, eventType domain.Anal
 func (s *AnalyticsService) GetSummary(ctx context.Context, userID string, linkID *string) (*domain.AnalyticsSummary, error) {
 	return s.repo.GetSummary(ctx, userID, linkID)
 }
+
+// GetVariantStats returns clicks and CTR per A/B variant of a link.
+func (s *AnalyticsService) GetVariantStats(ctx context.Context, userID string, link *domain.Link) ([]domain.VariantStats, error) {
+	return s.repo.GetVariantStats(ctx, userID, link)
+}
+
+// maxTimeSeriesPoints bounds the buckets of one time series (a month of hours).
+const maxTimeSeriesPoints = 744
+
+// GetTimeSeries returns views, clicks and unique visitors per bucket of a date range,
+// optionally limited to one link.
+func (s *AnalyticsService) GetTimeSeries(ctx context.Context, query domain.TimeSeriesQuery) (*domain.TimeSeries, error) {
+	if !query.Bucket.IsValid() {
+		return nil, fmt.Errorf("unknown bucket size %q: %w", query.Bucket, domain.ErrBadRequest)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("time range must end after it starts: %w", domain.ErrBadRequest)
+	}
+	if n := query.BucketCount(); n > maxTimeSeriesPoints {
+		return nil, fmt.Errorf("time range has %d %s buckets, at most %d are allowed: %w", n, query.Bucket, maxTimeSeriesPoints, domain.ErrBadRequest)
+	}
+	return s.repo.GetTimeSeries(ctx, query)
+}
+
+// GetTopLinks returns the user's most clicked links, most clicks first.
+func (s *AnalyticsService) GetTopLinks(ctx context.Context, userID string, limit int) ([]domain.LinkClicks, error) {
+	return s.repo.GetTopLinks(ctx, userID, limit)
+}
+
+// GetLinkStats returns the analytics of the query's link: its clicks over the query's
+// range, the country, device and referrer split of all its clicks, and its CTR
+// against the owner's profile views.
+func (s *AnalyticsService) GetLinkStats(ctx context.Context, query domain.TimeSeriesQuery) (*domain.LinkStats, error) {
+	if query.LinkID == nil {
+		return nil, fmt.Errorf("link stats need a link: %w", domain.ErrBadRequest)
+	}
+
+	series, err := s.GetTimeSeries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.repo.GetSummary(ctx, query.UserID, query.LinkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link summary: %w", err)
+	}
+	profile, err := s.repo.GetSummary(ctx, query.UserID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile summary: %w", err)
+	}
+
+	stats := &domain.LinkStats{
+		Summary:      summary,
+		ProfileViews: profile.TotalViews,
+		Series:       series,
+	}
+	if stats.ProfileViews > 0 {
+		stats.CTR = float64(summary.TotalClicks) / float64(stats.ProfileViews)
+	}
+	return stats, nil
+}
@@ -2,7 +2,9 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
 	"github.com/elchemista/driplnk/internal/mocks"
@@ -128,6 +130,9 @@ func TestAnalyticsService_GetSummary(t *testing.T) {
 		if summary.TotalClicks != 1 {
 			t.Errorf("expected 1 click, got %d", summary.TotalClicks)
 		}
+		if summary.UniqueVisitors != 2 {
+			t.Errorf("expected 2 unique visitors, got %d", summary.UniqueVisitors)
+		}
 		if summary.ByCountry["US"] != 3 {
 			t.Errorf("expected 3 events from US, got %d", summary.ByCountry["US"])
 		}
@@ -136,3 +141,130 @@ func TestAnalyticsService_GetSummary(t *testing.T) {
 		}
 	})
 }
+
+func TestAnalyticsService_GetVariantStats(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockAnalyticsRepository()
+	svc := service.NewAnalyticsService(repo)
+
+	userID := "user-123"
+	linkID := "link-ab"
+	link := &domain.Link{
+		ID:     domain.LinkID(linkID),
+		UserID: domain.UserID(userID),
+		Variants: []domain.LinkVariant{
+			{Name: "A", URL: "https://a.example.com", Weight: 1},
+			{Name: "B", URL: "https://b.example.com", Weight: 1},
+		},
+	}
+
+	// Each visitor views the profile once and clicks through to their sticky variant
+	visitors := []string{"v1", "v2", "v3", "v4", "v5", "v6"}
+	expectedViews := map[string]int64{}
+	for _, visitorID := range visitors {
+		svc.TrackEvent(ctx, domain.EventTypeView, &userID, nil, visitorID, nil)
+		variant := link.VariantFor(visitorID)
+		expectedViews[variant.Name]++
+		if visitorID == "v1" {
+			svc.TrackEvent(ctx, domain.EventTypeClick, &userID, &linkID, visitorID, map[string]string{"variant": variant.Name})
+		}
+	}
+
+	stats, err := svc.GetVariantStats(ctx, userID, link)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(stats))
+	}
+
+	clicked := link.VariantFor("v1").Name
+	for _, s := range stats {
+		if s.Views != expectedViews[s.Variant] {
+			t.Errorf("variant %s: expected %d views, got %d", s.Variant, expectedViews[s.Variant], s.Views)
+		}
+		if s.Variant == clicked {
+			if s.Clicks != 1 {
+				t.Errorf("variant %s: expected 1 click, got %d", s.Variant, s.Clicks)
+			}
+			if want := 1 / float64(s.Views); s.CTR != want {
+				t.Errorf("variant %s: expected CTR %f, got %f", s.Variant, want, s.CTR)
+			}
+		} else if s.Clicks != 0 || s.CTR != 0 {
+			t.Errorf("variant %s: expected no clicks, got %d (CTR %f)", s.Variant, s.Clicks, s.CTR)
+		}
+	}
+}
+
+func TestAnalyticsService_GetTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockAnalyticsRepository()
+	svc := service.NewAnalyticsService(repo)
+	userID := "user-series"
+
+	// Wednesday and Thursday of one week, Monday of the next
+	wednesday := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{wednesday, wednesday.Add(20 * time.Hour), wednesday.AddDate(0, 0, 5)} {
+		repo.SaveEvent(ctx, &domain.AnalyticsEvent{EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v" + string(rune('1'+i)), CreatedAt: at})
+	}
+
+	t.Run("buckets by week starting on Monday", func(t *testing.T) {
+		series, err := svc.GetTimeSeries(ctx, domain.TimeSeriesQuery{UserID: userID, From: wednesday.AddDate(0, 0, -7), To: wednesday.AddDate(0, 0, 7), Bucket: domain.BucketWeek})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(series.Points) != 3 {
+			t.Fatalf("expected 3 weekly points, got %d", len(series.Points))
+		}
+		if !series.Points[1].Start.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected the week to start on Monday, got %v", series.Points[1].Start)
+		}
+		if series.Points[1].Views != 2 || series.Points[2].Views != 1 {
+			t.Errorf("expected 2 then 1 views, got %+v", series.Points)
+		}
+	})
+
+	t.Run("rejects invalid queries", func(t *testing.T) {
+		queries := []domain.TimeSeriesQuery{
+			{UserID: userID, From: wednesday, To: wednesday.AddDate(0, 0, 1), Bucket: "minute"},
+			{UserID: userID, From: wednesday, To: wednesday, Bucket: domain.BucketDay},
+			{UserID: userID, From: wednesday.AddDate(-1, 0, 0), To: wednesday, Bucket: domain.BucketHour},
+		}
+		for _, q := range queries {
+			if _, err := svc.GetTimeSeries(ctx, q); !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected bad request for %+v, got %v", q, err)
+			}
+		}
+	})
+}
+
+func TestAnalyticsService_GetLinkStats(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockAnalyticsRepository()
+	svc := service.NewAnalyticsService(repo)
+	userID, linkID := "user-stats", "link-stats"
+	now := time.Now().UTC()
+
+	for i := 0; i < 4; i++ {
+		repo.SaveEvent(ctx, &domain.AnalyticsEvent{EventType: domain.EventTypeView, UserID: &userID, VisitorID: "v", CreatedAt: now})
+	}
+	repo.SaveEvent(ctx, &domain.AnalyticsEvent{EventType: domain.EventTypeClick, UserID: &userID, LinkID: &linkID, VisitorID: "v", CreatedAt: now})
+
+	stats, err := svc.GetLinkStats(ctx, domain.TimeSeriesQuery{UserID: userID, LinkID: &linkID, From: now.Add(-time.Hour), To: now.Add(time.Hour), Bucket: domain.BucketHour})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stats.Summary.TotalClicks != 1 || stats.ProfileViews != 4 {
+		t.Errorf("expected 1 click and 4 profile views, got %d and %d", stats.Summary.TotalClicks, stats.ProfileViews)
+	}
+	if stats.CTR != 0.25 {
+		t.Errorf("expected a CTR of 0.25, got %f", stats.CTR)
+	}
+	if _, clicks := stats.Series.Totals(); clicks != 1 {
+		t.Errorf("expected the click in the series, got %d", clicks)
+	}
+
+	if _, err := svc.GetLinkStats(ctx, domain.TimeSeriesQuery{UserID: userID, From: now.Add(-time.Hour), To: now, Bucket: domain.BucketHour}); !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected bad request without a link, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/validator"
+	"github.com/google/uuid"
+)
+
+// LinkGroupService manages profile sections and which links are listed under them.
+type LinkGroupService struct {
+	groups domain.LinkGroupRepository
+	links  domain.LinkRepository
+}
+
+func NewLinkGroupService(groups domain.LinkGroupRepository, links domain.LinkRepository) *LinkGroupService {
+	return &LinkGroupService{
+		groups: groups,
+		links:  links,
+	}
+}
+
+// CreateGroup adds a new section after the user's existing ones.
+func (s *LinkGroupService) CreateGroup(ctx context.Context, userID domain.UserID, title, description string, collapsed bool) (*domain.LinkGroup, error) {
+	existing, err := s.groups.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+
+	now := time.Now()
+	group := &domain.LinkGroup{
+		ID:          domain.LinkGroupID(uuid.New().String()),
+		UserID:      userID,
+		Title:       strings.TrimSpace(title),
+		Description: strings.TrimSpace(description),
+		Collapsed:   collapsed,
+		Order:       len(existing),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := validator.ValidateStruct(group); err != nil {
+		return nil, fmt.Errorf("invalid group: %v: %w", err, domain.ErrBadRequest)
+	}
+
+	if err := s.groups.Save(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+	return group, nil
+}
+
+// UpdateGroup changes the header and default state of a section.
+func (s *LinkGroupService) UpdateGroup(ctx context.Context, groupID domain.LinkGroupID, userID domain.UserID, title, description string, collapsed bool) (*domain.LinkGroup, error) {
+	group, err := s.ownedGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Title = strings.TrimSpace(title)
+	group.Description = strings.TrimSpace(description)
+	group.Collapsed = collapsed
+	group.UpdatedAt = time.Now()
+	if err := validator.ValidateStruct(group); err != nil {
+		return nil, fmt.Errorf("invalid group: %v: %w", err, domain.ErrBadRequest)
+	}
+
+	if err := s.groups.Save(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+	return group, nil
+}
+
+// DeleteGroup removes a section. Its links are kept in place and become ungrouped.
+func (s *LinkGroupService) DeleteGroup(ctx context.Context, groupID domain.LinkGroupID, userID domain.UserID) error {
+	if _, err := s.ownedGroup(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	links, err := s.links.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch links: %w", err)
+	}
+	placements := placementsOf(links)
+	for i := range placements {
+		if placements[i].GroupID == groupID {
+			placements[i].GroupID = ""
+		}
+	}
+	if err := s.links.Reorder(ctx, userID, placements); err != nil {
+		return fmt.Errorf("failed to ungroup links: %w", err)
+	}
+
+	return s.groups.Delete(ctx, groupID)
+}
+
+// ListGroups returns the user's sections ordered by position.
+func (s *LinkGroupService) ListGroups(ctx context.Context, userID domain.UserID) ([]*domain.LinkGroup, error) {
+	return s.groups.ListByUser(ctx, userID)
+}
+
+// ReorderGroups reorders the sections of a user.
+func (s *LinkGroupService) ReorderGroups(ctx context.Context, userID domain.UserID, orderedIDs []domain.LinkGroupID) error {
+	return s.groups.Reorder(ctx, userID, orderedIDs)
+}
+
+// ArrangeLinks orders the user's links and moves them between sections in one step.
+// Every group referenced by a placement must belong to the user.
+func (s *LinkGroupService) ArrangeLinks(ctx context.Context, userID domain.UserID, placements []domain.LinkPlacement) error {
+	owned, err := s.ownedGroupIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, p := range placements {
+		if p.GroupID != "" && !owned[p.GroupID] {
+			return fmt.Errorf("unknown group %q: %w", p.GroupID, domain.ErrBadRequest)
+		}
+	}
+	return s.links.Reorder(ctx, userID, placements)
+}
+
+// MoveLink moves a link to the end of a section, or to the end of the
+// ungrouped links when groupID is empty.
+func (s *LinkGroupService) MoveLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, groupID domain.LinkGroupID) error {
+	if groupID != "" {
+		if _, err := s.ownedGroup(ctx, groupID, userID); err != nil {
+			return err
+		}
+	}
+
+	links, err := s.links.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	placements := make([]domain.LinkPlacement, 0, len(links))
+	found := false
+	for _, link := range links {
+		if link.ID == linkID {
+			found = true
+			continue
+		}
+		placements = append(placements, domain.LinkPlacement{LinkID: link.ID, GroupID: link.GroupID})
+	}
+	if !found {
+		return fmt.Errorf("link not found: %w", domain.ErrNotFound)
+	}
+
+	// Insert after the last link already in the target section
+	at := len(placements)
+	for i := len(placements) - 1; i >= 0; i-- {
+		if placements[i].GroupID == groupID {
+			at = i + 1
+			break
+		}
+	}
+	placements = append(placements[:at], append([]domain.LinkPlacement{{LinkID: linkID, GroupID: groupID}}, placements[at:]...)...)
+
+	return s.links.Reorder(ctx, userID, placements)
+}
+
+// ownedGroup loads a group and verifies it belongs to the user.
+func (s *LinkGroupService) ownedGroup(ctx context.Context, groupID domain.LinkGroupID, userID domain.UserID) (*domain.LinkGroup, error) {
+	group, err := s.groups.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("group not found: %w", err)
+	}
+	if group.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: group does not belong to user: %w", domain.ErrForbidden)
+	}
+	return group, nil
+}
+
+func (s *LinkGroupService) ownedGroupIDs(ctx context.Context, userID domain.UserID) (map[domain.LinkGroupID]bool, error) {
+	groups, err := s.groups.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+	owned := make(map[domain.LinkGroupID]bool, len(groups))
+	for _, group := range groups {
+		owned[group.ID] = true
+	}
+	return owned, nil
+}
+
+// placementsOf returns the current layout of links.
+func placementsOf(links []*domain.Link) []domain.LinkPlacement {
+	placements := make([]domain.LinkPlacement, len(links))
+	for i, link := range links {
+		placements[i] = domain.LinkPlacement{LinkID: link.ID, GroupID: link.GroupID}
+	}
+	return placements
+}
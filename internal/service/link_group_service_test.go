@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+func layoutOf(t *testing.T, repo *mocks.MockLinkRepository, userID domain.UserID) []string {
+	t.Helper()
+	links, err := repo.ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListByUser failed: %v", err)
+	}
+	layout := make([]string, len(links))
+	for i, l := range links {
+		layout[i] = string(l.ID) + "/" + string(l.GroupID)
+	}
+	return layout
+}
+
+func assertLayout(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestLinkGroupService_CreateAndUpdateGroup(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewLinkGroupService(mocks.NewMockLinkGroupRepository(), mocks.NewMockLinkRepository())
+	userID := domain.UserID("user-123")
+
+	first, err := svc.CreateGroup(ctx, userID, " Music ", "", false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, _ := svc.CreateGroup(ctx, userID, "Merch", "Shirts and vinyl", true)
+	if first.Title != "Music" || first.Order != 0 || second.Order != 1 || !second.Collapsed {
+		t.Errorf("unexpected groups %+v, %+v", first, second)
+	}
+
+	if _, err := svc.CreateGroup(ctx, userID, "", "", false); !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected bad request for empty title, got %v", err)
+	}
+
+	updated, err := svc.UpdateGroup(ctx, first.ID, userID, "Tour", "Dates", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Title != "Tour" || !updated.Collapsed {
+		t.Errorf("unexpected group %+v", updated)
+	}
+
+	if _, err := svc.UpdateGroup(ctx, first.ID, "other-user", "Mine", "", false); err == nil {
+		t.Error("expected error for unauthorized user")
+	}
+
+	if err := svc.ReorderGroups(ctx, userID, []domain.LinkGroupID{second.ID, first.ID}); err != nil {
+		t.Fatalf("ReorderGroups failed: %v", err)
+	}
+	groups, _ := svc.ListGroups(ctx, userID)
+	if len(groups) != 2 || groups[0].ID != second.ID {
+		t.Errorf("expected %s first, got %+v", second.ID, groups)
+	}
+}
+
+func TestLinkGroupService_ArrangeAndMoveLinks(t *testing.T) {
+	ctx := context.Background()
+	groups := mocks.NewMockLinkGroupRepository()
+	links := mocks.NewMockLinkRepository()
+	svc := service.NewLinkGroupService(groups, links)
+	userID := domain.UserID("user-123")
+
+	groups.AddGroup(&domain.LinkGroup{ID: "g1", UserID: userID, Title: "Music"})
+	groups.AddGroup(&domain.LinkGroup{ID: "g2", UserID: userID, Title: "Merch", Order: 1})
+	groups.AddGroup(&domain.LinkGroup{ID: "gx", UserID: "other-user", Title: "Not mine"})
+	for i, id := range []domain.LinkID{"a", "b", "c", "d"} {
+		links.AddLink(&domain.Link{ID: id, UserID: userID, Order: i})
+	}
+
+	t.Run("arrange moves links between groups", func(t *testing.T) {
+		err := svc.ArrangeLinks(ctx, userID, []domain.LinkPlacement{
+			{LinkID: "d"},
+			{LinkID: "a", GroupID: "g1"},
+			{LinkID: "b", GroupID: "g1"},
+			{LinkID: "c", GroupID: "g2"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		assertLayout(t, layoutOf(t, links, userID), []string{"d/", "a/g1", "b/g1", "c/g2"})
+	})
+
+	t.Run("arrange rejects groups of other users", func(t *testing.T) {
+		err := svc.ArrangeLinks(ctx, userID, []domain.LinkPlacement{{LinkID: "a", GroupID: "gx"}})
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+
+	t.Run("reorder keeps groups", func(t *testing.T) {
+		linkSvc := service.NewLinkService(links, nil, nil, nil, nil, nil)
+		if err := linkSvc.ReorderLinks(ctx, userID, []domain.LinkID{"b", "a", "c", "d"}); err != nil {
+			t.Fatalf("ReorderLinks failed: %v", err)
+		}
+		assertLayout(t, layoutOf(t, links, userID), []string{"b/g1", "a/g1", "c/g2", "d/"})
+	})
+
+	t.Run("move appends to the end of the section", func(t *testing.T) {
+		if err := svc.MoveLink(ctx, "d", userID, "g1"); err != nil {
+			t.Fatalf("MoveLink failed: %v", err)
+		}
+		assertLayout(t, layoutOf(t, links, userID), []string{"b/g1", "a/g1", "d/g1", "c/g2"})
+	})
+
+	t.Run("delete ungroups links in place", func(t *testing.T) {
+		if err := svc.DeleteGroup(ctx, "g1", userID); err != nil {
+			t.Fatalf("DeleteGroup failed: %v", err)
+		}
+		assertLayout(t, layoutOf(t, links, userID), []string{"b/", "a/", "d/", "c/g2"})
+		if _, err := groups.GetByID(ctx, "g1"); err == nil {
+			t.Error("expected group to be deleted")
+		}
+	})
+}
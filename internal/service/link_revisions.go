@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/google/uuid"
+)
+
+// snapshotLink returns a deep copy of a link for a revision. The copy goes
+// through JSON like the stored record, so it never carries the password hash.
+func snapshotLink(link *domain.Link) *domain.Link {
+	if link == nil {
+		return nil
+	}
+	data, err := json.Marshal(link)
+	if err != nil {
+		return nil
+	}
+	var copied domain.Link
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil
+	}
+	return &copied
+}
+
+// recordRevision appends a revision to the link's history. The change itself is
+// already saved, so a failure to record it is logged rather than returned.
+func (s *LinkService) recordRevision(ctx context.Context, actorID domain.UserID, action domain.RevisionAction, before, after *domain.Link) {
+	if s.revisions == nil {
+		return
+	}
+
+	ref := before
+	if ref == nil {
+		ref = after
+	}
+	rev := &domain.LinkRevision{
+		ID:        domain.LinkRevisionID(uuid.New().String()),
+		LinkID:    ref.ID,
+		UserID:    ref.UserID,
+		ActorID:   actorID,
+		Action:    action,
+		Before:    before,
+		After:     snapshotLink(after),
+		CreatedAt: time.Now(),
+	}
+	if err := s.revisions.Save(ctx, rev); err != nil {
+		fmt.Printf("[WARN] Failed to record %s revision for link %s: %v\n", action, ref.ID, err)
+	}
+}
+
+// LinkHistory returns the revisions of a link, newest first.
+// It also works for deleted links, whose history outlives them.
+func (s *LinkService) LinkHistory(ctx context.Context, linkID domain.LinkID, userID domain.UserID) ([]*domain.LinkRevision, error) {
+	if s.revisions == nil {
+		return nil, nil
+	}
+	revisions, err := s.revisions.ListByLink(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch link history: %w", err)
+	}
+
+	owned := revisions[:0]
+	for _, rev := range revisions {
+		if rev.UserID == userID {
+			owned = append(owned, rev)
+		}
+	}
+	return owned, nil
+}
+
+// RecentRevisions returns the latest changes across all of a user's links, newest first.
+func (s *LinkService) RecentRevisions(ctx context.Context, userID domain.UserID, limit int) ([]*domain.LinkRevision, error) {
+	if s.revisions == nil {
+		return nil, nil
+	}
+	return s.revisions.ListByUser(ctx, userID, limit)
+}
+
+// RevertLink restores a link to the state it had before the given revision,
+// undeleting it if it is in the trash or was purged, and reports whether it was undeleted.
+// The revert is itself recorded.
+//
+// Position, group and click counts are kept from the current link; an undeleted
+// link is appended without a section. The password hash of a protected link is
+// only kept while the link is still password protected, so a purged password
+// link comes back locked until its owner sets a new password. Reverting fails
+// with domain.ErrConflict when another link now points to the earlier URL.
+func (s *LinkService) RevertLink(ctx context.Context, revisionID domain.LinkRevisionID, userID domain.UserID) (*domain.Link, bool, error) {
+	if s.revisions == nil {
+		return nil, false, fmt.Errorf("link history is not enabled: %w", domain.ErrBadRequest)
+	}
+
+	rev, err := s.revisions.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, false, fmt.Errorf("revision not found: %w", err)
+	}
+	if rev.UserID != userID {
+		return nil, false, fmt.Errorf("unauthorized: revision does not belong to user: %w", domain.ErrForbidden)
+	}
+	if rev.Before == nil {
+		return nil, false, fmt.Errorf("revision has no earlier version to restore: %w", domain.ErrBadRequest)
+	}
+
+	if err := s.CheckURL(rev.Before.URL); err != nil {
+		return nil, false, fmt.Errorf("earlier link URL rejected: %w", err)
+	}
+
+	restored := snapshotLink(rev.Before)
+	restored.UpdatedAt = time.Now()
+
+	current, err := s.repo.GetByID(ctx, rev.LinkID)
+	if err != nil {
+		current = nil // purged: recreate it at the end of the list
+	}
+	undeleted := current == nil || current.IsTrashed()
+
+	if current != nil && current.UserID != userID {
+		return nil, false, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	existing, err := s.activeLinks(ctx, userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch existing links: %w", err)
+	}
+	if err := checkDuplicateURL(existing, restored.URL, restored.ID); err != nil {
+		return nil, false, err
+	}
+
+	if !undeleted {
+		restored.Order = current.Order
+		restored.GroupID = current.GroupID
+		restored.ClickCount = current.ClickCount
+		restored.UniqueVisitors = current.UniqueVisitors
+		restored.CreatedAt = current.CreatedAt
+		restored.Health = nil
+		if restored.URL == current.URL {
+			restored.Health = current.Health
+		}
+	} else {
+		restored.Order = nextOrder(existing)
+		restored.GroupID = "" // its section may be gone too
+		restored.Health = nil
+		if current != nil {
+			restored.ClickCount = current.ClickCount
+			restored.UniqueVisitors = current.UniqueVisitors
+			restored.CreatedAt = current.CreatedAt
+		}
+	}
+	if current != nil && restored.Protection == domain.ProtectionPassword && current.Protection == domain.ProtectionPassword {
+		restored.PasswordHash = current.PasswordHash
+	}
+
+	// The old slug may have been taken by another link in the meantime
+	if restored.Slug != "" && !s.slugAvailable(ctx, restored.Slug, restored.ID) {
+		restored.Slug = ""
+		if slug, err := s.generateSlug(ctx, restored.Title); err == nil {
+			restored.Slug = slug
+		}
+	}
+
+	before := snapshotLink(current)
+	if err := s.repo.Save(ctx, restored); err != nil {
+		return nil, false, fmt.Errorf("failed to restore link: %w", err)
+	}
+	s.recordRevision(ctx, userID, domain.RevisionRevert, before, restored)
+
+	return restored, undeleted, nil
+}
@@ -0,0 +1,211 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+func newRevisionTestService() (*service.LinkService, *mocks.MockLinkRepository, *mocks.MockLinkRevisionRepository) {
+	repo := mocks.NewMockLinkRepository()
+	revisions := mocks.NewMockLinkRevisionRepository()
+	return service.NewLinkService(repo, mocks.NewMockMetadataFetcher(), nil, revisions, nil, nil), repo, revisions
+}
+
+func TestLinkService_RecordsRevisions(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newRevisionTestService()
+	userID := domain.UserID("user-123")
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, Title: "Old", URL: "https://old.example.com", Type: domain.LinkTypeStandard, IsActive: true})
+
+	title := "New"
+	if _, err := svc.UpdateLink(ctx, "link-1", userID, &title, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateLink failed: %v", err)
+	}
+	if _, err := svc.RefreshMetadata(ctx, "link-1", userID); err != nil {
+		t.Fatalf("RefreshMetadata failed: %v", err)
+	}
+	if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+
+	history, err := svc.LinkHistory(ctx, "link-1", userID)
+	if err != nil {
+		t.Fatalf("LinkHistory failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(history))
+	}
+
+	deleted, refreshed, updated := history[0], history[1], history[2]
+	if deleted.Action != domain.RevisionDelete || deleted.After != nil || deleted.Before.Title != "New" {
+		t.Errorf("unexpected delete revision %+v", deleted)
+	}
+	if refreshed.Action != domain.RevisionRefresh || refreshed.After.Metadata["og:title"] != "Default Title" {
+		t.Errorf("unexpected refresh revision %+v", refreshed)
+	}
+	if updated.Action != domain.RevisionUpdate || updated.Before.Title != "Old" || updated.After.Title != "New" {
+		t.Errorf("unexpected update revision %+v", updated)
+	}
+	if updated.ActorID != userID || updated.UserID != userID {
+		t.Errorf("expected actor and owner %s, got %s and %s", userID, updated.ActorID, updated.UserID)
+	}
+	if fields := updated.ChangedFields(); len(fields) != 1 || fields[0] != "title" {
+		t.Errorf("expected only the title to change, got %v", fields)
+	}
+
+	if others, _ := svc.LinkHistory(ctx, "link-1", "user-other"); len(others) != 0 {
+		t.Errorf("expected no history for another user, got %d revisions", len(others))
+	}
+}
+
+func TestLinkService_RevertLink(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newRevisionTestService()
+	userID := domain.UserID("user-123")
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, Title: "Old", URL: "https://old.example.com", Order: 3, ClickCount: 7, IsActive: true})
+
+	url := "https://new.example.com"
+	if _, err := svc.UpdateLink(ctx, "link-1", userID, nil, &url, nil, nil); err != nil {
+		t.Fatalf("UpdateLink failed: %v", err)
+	}
+	history, _ := svc.LinkHistory(ctx, "link-1", userID)
+
+	t.Run("Forbidden", func(t *testing.T) {
+		if _, _, err := svc.RevertLink(ctx, history[0].ID, "user-other"); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden, got %v", err)
+		}
+	})
+
+	t.Run("RestoresEarlierVersion", func(t *testing.T) {
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, Title: "Old", URL: url, Order: 1, ClickCount: 9, IsActive: true})
+
+		link, undeleted, err := svc.RevertLink(ctx, history[0].ID, userID)
+		if err != nil {
+			t.Fatalf("RevertLink failed: %v", err)
+		}
+		if undeleted {
+			t.Error("expected an in-place revert")
+		}
+		if link.URL != "https://old.example.com" {
+			t.Errorf("expected the old URL, got %s", link.URL)
+		}
+		if link.Order != 1 || link.ClickCount != 9 {
+			t.Errorf("expected current order and clicks to be kept, got %d and %d", link.Order, link.ClickCount)
+		}
+
+		latest, _ := svc.LinkHistory(ctx, "link-1", userID)
+		if latest[0].Action != domain.RevisionRevert || latest[0].Before.URL != url {
+			t.Errorf("expected the revert to be recorded, got %+v", latest[0])
+		}
+	})
+
+	t.Run("Undelete", func(t *testing.T) {
+		repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, Title: "Other", URL: "https://other.example.com"})
+		if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+		latest, _ := svc.LinkHistory(ctx, "link-1", userID)
+
+		link, undeleted, err := svc.RevertLink(ctx, latest[0].ID, userID)
+		if err != nil {
+			t.Fatalf("RevertLink failed: %v", err)
+		}
+		if !undeleted {
+			t.Error("expected the link to be undeleted")
+		}
+		if link.Order != 1 || link.URL != "https://old.example.com" {
+			t.Errorf("expected the link at the end of the list with its old URL, got order %d and %s", link.Order, link.URL)
+		}
+		if _, err := svc.GetLink(ctx, "link-1"); err != nil {
+			t.Errorf("expected the link to be out of the trash, got %v", err)
+		}
+	})
+
+	t.Run("RecreatePurged", func(t *testing.T) {
+		if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+		if err := svc.PurgeLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("PurgeLink failed: %v", err)
+		}
+		latest, _ := svc.LinkHistory(ctx, "link-1", userID)
+
+		link, undeleted, err := svc.RevertLink(ctx, latest[0].ID, userID)
+		if err != nil {
+			t.Fatalf("RevertLink failed: %v", err)
+		}
+		if !undeleted || link.URL != "https://old.example.com" {
+			t.Errorf("expected the purged link to be recreated, got %+v", link)
+		}
+
+		recreated, _ := svc.LinkHistory(ctx, "link-1", userID)
+		if _, _, err := svc.RevertLink(ctx, recreated[0].ID, userID); !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request for a revision without an earlier version, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_RevertLinkOrderAndDuplicates(t *testing.T) {
+	ctx := context.Background()
+	userID := domain.UserID("user-123")
+
+	t.Run("UndeleteAfterHighestOrder", func(t *testing.T) {
+		svc, repo, _ := newRevisionTestService()
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://one.example.com", Order: 0})
+		repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, URL: "https://two.example.com", Order: 1})
+		repo.AddLink(&domain.Link{ID: "link-3", UserID: userID, URL: "https://three.example.com", Order: 2})
+		if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+		history, _ := svc.LinkHistory(ctx, "link-1", userID)
+
+		// Two links are left, but link-3 already holds order 2
+		link, undeleted, err := svc.RevertLink(ctx, history[0].ID, userID)
+		if err != nil {
+			t.Fatalf("RevertLink failed: %v", err)
+		}
+		if !undeleted || link.Order != 3 {
+			t.Errorf("expected the link undeleted after the highest order, got order %d", link.Order)
+		}
+	})
+
+	t.Run("UndeleteDuplicateURL", func(t *testing.T) {
+		svc, repo, _ := newRevisionTestService()
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://shop.example.com"})
+		if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+		history, _ := svc.LinkHistory(ctx, "link-1", userID)
+		if _, err := svc.CreateLink(ctx, userID, "Shop", "https://shop.example.com/", domain.LinkTypeStandard); err != nil {
+			t.Fatalf("CreateLink failed: %v", err)
+		}
+
+		if _, _, err := svc.RevertLink(ctx, history[0].ID, userID); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict undeleting a link whose URL was re-created, got %v", err)
+		}
+	})
+
+	t.Run("RevertToDuplicateURL", func(t *testing.T) {
+		svc, repo, _ := newRevisionTestService()
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://old.example.com", Order: 0})
+		repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, URL: "https://other.example.com", Order: 1})
+
+		moved, taken := "https://new.example.com", "https://old.example.com"
+		if _, err := svc.UpdateLink(ctx, "link-1", userID, nil, &moved, nil, nil); err != nil {
+			t.Fatalf("UpdateLink failed: %v", err)
+		}
+		history, _ := svc.LinkHistory(ctx, "link-1", userID)
+		if _, err := svc.UpdateLink(ctx, "link-2", userID, nil, &taken, nil, nil); err != nil {
+			t.Fatalf("UpdateLink failed: %v", err)
+		}
+
+		if _, _, err := svc.RevertLink(ctx, history[0].ID, userID); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict reverting to a URL another link uses, got %v", err)
+		}
+	})
+}
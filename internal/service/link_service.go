@@ -2,11 +2,21 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/pkg/urlcanon"
+	"github.com/elchemista/driplnk/internal/pkg/validator"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // LinkService encapsulates link business logic.
@@ -14,16 +24,31 @@ type LinkService struct {
 	repo            domain.LinkRepository
 	metadataFetcher domain.MetadataFetcher
 	socialResolver  domain.SocialResolver
+	revisions       domain.LinkRevisionRepository // optional: edit history, nil disables it
+	safety          domain.LinkSafetyChecker      // optional: outbound URL policy, nil allows every URL
+	thumbnails      domain.ThumbnailStore         // optional: local copies of preview images, nil keeps remote OG images
 }
 
-func NewLinkService(repo domain.LinkRepository, metadataFetcher domain.MetadataFetcher, socialResolver domain.SocialResolver) *LinkService {
+func NewLinkService(repo domain.LinkRepository, metadataFetcher domain.MetadataFetcher, socialResolver domain.SocialResolver, revisions domain.LinkRevisionRepository, safety domain.LinkSafetyChecker, thumbnails domain.ThumbnailStore) *LinkService {
 	return &LinkService{
 		repo:            repo,
 		metadataFetcher: metadataFetcher,
 		socialResolver:  socialResolver,
+		revisions:       revisions,
+		safety:          safety,
+		thumbnails:      thumbnails,
 	}
 }
 
+// CheckURL vets an outbound destination against the link safety policy.
+// Redirects call it again so blocklist updates also apply to existing links.
+func (s *LinkService) CheckURL(rawURL string) error {
+	if s.safety == nil {
+		return nil
+	}
+	return s.safety.Check(rawURL)
+}
+
 // CreateLink creates a new link for a user.
 // It automatically sets the order to be last in the list and fetches metadata.
 // For social links, it resolves the platform info instead of fetching OG metadata.
@@ -34,21 +59,21 @@ func (s *LinkService) CreateLink(ctx context.Context, userID domain.UserID, titl
 	if url == "" {
 		return nil, fmt.Errorf("link URL is required")
 	}
+	if err := s.CheckURL(url); err != nil {
+		return nil, fmt.Errorf("link URL rejected: %w", err)
+	}
 
 	// Get existing links to determine order and check for duplicates
-	existingLinks, err := s.repo.ListByUser(ctx, userID)
+	existingLinks, err := s.activeLinks(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch existing links: %w", err)
 	}
 
-	// Check for duplicate URL
-	for _, existing := range existingLinks {
-		if existing.URL == url {
-			return nil, fmt.Errorf("link with this URL already exists")
-		}
+	if err := checkDuplicateURL(existingLinks, url, ""); err != nil {
+		return nil, err
 	}
 
-	order := len(existingLinks) // New link goes at the end
+	order := nextOrder(existingLinks) // New link goes at the end
 
 	if linkType == "" {
 		linkType = domain.LinkTypeStandard
@@ -68,9 +93,16 @@ func (s *LinkService) CreateLink(ctx context.Context, userID domain.UserID, titl
 		UpdatedAt: now,
 	}
 
+	// Generate a short vanity slug from the title; slugs are optional so failures are not fatal
+	if slug, err := s.generateSlug(ctx, title); err == nil {
+		link.Slug = slug
+	} else {
+		fmt.Printf("[WARN] Failed to generate slug for %s: %v\n", url, err)
+	}
+
 	// For social links, use the social resolver instead of fetching metadata
 	if linkType == domain.LinkTypeSocial && s.socialResolver != nil {
-		platform, err := s.socialResolver.Resolve(url)
+		platform, err := s.socialResolver.Resolve(urlcanon.Canonicalize(url))
 		if err == nil && platform != nil {
 			link.Metadata["social:name"] = platform.Name
 			link.Metadata["social:icon"] = platform.IconSVG
@@ -88,15 +120,8 @@ func (s *LinkService) CreateLink(ctx context.Context, userID domain.UserID, titl
 			fmt.Printf("[WARN] Failed to fetch metadata for %s: %v\n", url, err)
 		} else if metadata != nil {
 			// Store metadata in the link
-			if metadata.Title != "" {
-				link.Metadata["og:title"] = metadata.Title
-			}
-			if metadata.Description != "" {
-				link.Metadata["og:description"] = metadata.Description
-			}
-			if metadata.ImageURL != "" {
-				link.Metadata["og:image"] = metadata.ImageURL
-			}
+			applyMetadata(link, metadata)
+			s.cacheThumbnail(ctx, link)
 			fmt.Printf("[INFO] Stored metadata for %s: title=%s, has_image=%v\n",
 				url, metadata.Title, metadata.ImageURL != "")
 		}
@@ -109,6 +134,63 @@ func (s *LinkService) CreateLink(ctx context.Context, userID domain.UserID, titl
 	return link, nil
 }
 
+// applyMetadata copies fetched OpenGraph and embed data into the link's metadata map.
+// Embed keys are replaced as a whole so a refresh drops a player that is no longer available.
+func applyMetadata(link *domain.Link, metadata *domain.LinkMetadata) {
+	if metadata.Title != "" {
+		link.Metadata["og:title"] = metadata.Title
+	}
+	if metadata.Description != "" {
+		link.Metadata["og:description"] = metadata.Description
+	}
+	if metadata.ImageURL != "" {
+		link.Metadata["og:image"] = metadata.ImageURL
+	}
+
+	for _, key := range []string{"embed:provider", "embed:src", "embed:html", "embed:width", "embed:height"} {
+		delete(link.Metadata, key)
+	}
+	if metadata.EmbedURL == "" && metadata.EmbedHTML == "" {
+		return
+	}
+	link.Metadata["embed:provider"] = metadata.EmbedProvider
+	if metadata.EmbedURL != "" {
+		link.Metadata["embed:src"] = metadata.EmbedURL
+	} else {
+		link.Metadata["embed:html"] = metadata.EmbedHTML
+	}
+	if metadata.EmbedWidth > 0 {
+		link.Metadata["embed:width"] = strconv.Itoa(metadata.EmbedWidth)
+	}
+	if metadata.EmbedHeight > 0 {
+		link.Metadata["embed:height"] = strconv.Itoa(metadata.EmbedHeight)
+	}
+}
+
+// cacheThumbnail stores a copy of the link's OG image so link cards do not hotlink
+// third-party sites. Failures are logged and keep the previous copy.
+func (s *LinkService) cacheThumbnail(ctx context.Context, link *domain.Link) {
+	source := link.Metadata["og:image"]
+	if s.thumbnails == nil || source == "" {
+		return
+	}
+
+	// OG images may be relative to the page they were found on
+	if base, err := url.Parse(link.URL); err == nil {
+		if ref, err := url.Parse(source); err == nil {
+			source = base.ResolveReference(ref).String()
+		}
+	}
+
+	filename := fmt.Sprintf("thumbnails/%s_%d", link.ID, time.Now().Unix())
+	stored, err := s.thumbnails.CacheThumbnail(ctx, source, filename)
+	if err != nil {
+		fmt.Printf("[WARN] Failed to cache thumbnail for %s: %v\n", link.URL, err)
+		return
+	}
+	link.Metadata[domain.MetaThumbnail] = stored
+}
+
 // RefreshMetadata refetches metadata for an existing link
 // For social links, it re-resolves the platform info instead of fetching OG metadata
 func (s *LinkService) RefreshMetadata(ctx context.Context, linkID domain.LinkID, userID domain.UserID) (*domain.Link, error) {
@@ -122,6 +204,8 @@ func (s *LinkService) RefreshMetadata(ctx context.Context, linkID domain.LinkID,
 		return nil, fmt.Errorf("unauthorized: link does not belong to user")
 	}
 
+	before := snapshotLink(link)
+
 	// Update metadata
 	if link.Metadata == nil {
 		link.Metadata = make(map[string]string)
@@ -129,7 +213,7 @@ func (s *LinkService) RefreshMetadata(ctx context.Context, linkID domain.LinkID,
 
 	// For social links, use the social resolver
 	if link.Type == domain.LinkTypeSocial && s.socialResolver != nil {
-		platform, err := s.socialResolver.Resolve(link.URL)
+		platform, err := s.socialResolver.Resolve(urlcanon.Canonicalize(link.URL))
 		if err == nil && platform != nil {
 			link.Metadata["social:name"] = platform.Name
 			link.Metadata["social:icon"] = platform.IconSVG
@@ -148,15 +232,8 @@ func (s *LinkService) RefreshMetadata(ctx context.Context, linkID domain.LinkID,
 			return nil, fmt.Errorf("failed to fetch metadata: %w", err)
 		}
 
-		if metadata.Title != "" {
-			link.Metadata["og:title"] = metadata.Title
-		}
-		if metadata.Description != "" {
-			link.Metadata["og:description"] = metadata.Description
-		}
-		if metadata.ImageURL != "" {
-			link.Metadata["og:image"] = metadata.ImageURL
-		}
+		applyMetadata(link, metadata)
+		s.cacheThumbnail(ctx, link)
 
 		fmt.Printf("[INFO] Refreshed metadata for %s: title=%s, desc=%s, image=%s\n",
 			link.URL, metadata.Title, metadata.Description, metadata.ImageURL)
@@ -166,11 +243,13 @@ func (s *LinkService) RefreshMetadata(ctx context.Context, linkID domain.LinkID,
 	if err := s.repo.Save(ctx, link); err != nil {
 		return nil, fmt.Errorf("failed to save updated metadata: %w", err)
 	}
+	s.recordRevision(ctx, userID, domain.RevisionRefresh, before, link)
 
 	return link, nil
 }
 
 // UpdateLink updates an existing link's fields.
+// A new URL is rejected if another of the user's links already points to it.
 func (s *LinkService) UpdateLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, title, url *string, linkType *domain.LinkType, isActive *bool) (*domain.Link, error) {
 	link, err := s.repo.GetByID(ctx, linkID)
 	if err != nil {
@@ -182,16 +261,37 @@ func (s *LinkService) UpdateLink(ctx context.Context, linkID domain.LinkID, user
 		return nil, fmt.Errorf("unauthorized: link does not belong to user")
 	}
 
+	before := snapshotLink(link)
+
 	if title != nil {
 		link.Title = *title
 	}
 	if url != nil {
+		if *url != link.URL {
+			if err := s.CheckURL(*url); err != nil {
+				return nil, fmt.Errorf("link URL rejected: %w", err)
+			}
+			existing, err := s.activeLinks(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch existing links: %w", err)
+			}
+			if err := checkDuplicateURL(existing, *url, link.ID); err != nil {
+				return nil, err
+			}
+			// The last check was for the old destination
+			link.Health = nil
+		}
 		link.URL = *url
 	}
 	if linkType != nil {
 		link.Type = *linkType
 	}
 	if isActive != nil {
+		// Re-activating by hand restarts the auto-deactivate count
+		if *isActive && !link.IsActive && link.Health != nil {
+			link.Health.Failures = 0
+			link.Health.Deactivated = false
+		}
 		link.IsActive = *isActive
 	}
 	link.UpdatedAt = time.Now()
@@ -199,11 +299,13 @@ func (s *LinkService) UpdateLink(ctx context.Context, linkID domain.LinkID, user
 	if err := s.repo.Save(ctx, link); err != nil {
 		return nil, fmt.Errorf("failed to update link: %w", err)
 	}
+	s.recordRevision(ctx, userID, domain.RevisionUpdate, before, link)
 
 	return link, nil
 }
 
-// DeleteLink removes a link.
+// DeleteLink moves a link to the trash. It disappears from the profile and the
+// dashboard list until it is restored with RestoreLink or purged for good.
 func (s *LinkService) DeleteLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID) error {
 	link, err := s.repo.GetByID(ctx, linkID)
 	if err != nil {
@@ -213,21 +315,774 @@ func (s *LinkService) DeleteLink(ctx context.Context, linkID domain.LinkID, user
 	if link.UserID != userID {
 		return fmt.Errorf("unauthorized: link does not belong to user")
 	}
+	if link.IsTrashed() {
+		return nil
+	}
+
+	return s.trashLink(ctx, link, userID)
+}
+
+// ScheduleLink sets or clears the publish window of a link.
+// A nil startsAt or endsAt leaves that side of the window open.
+// fallbackURL is where visitors are sent once the link has expired.
+func (s *LinkService) ScheduleLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, startsAt, endsAt *time.Time, fallbackURL string) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, fmt.Errorf("link end time must be after its start time")
+	}
+	if fallbackURL != "" {
+		if err := s.CheckURL(fallbackURL); err != nil {
+			return nil, fmt.Errorf("fallback URL rejected: %w", err)
+		}
+	}
+
+	link.StartsAt = startsAt
+	link.EndsAt = endsAt
+	link.FallbackURL = fallbackURL
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to schedule link: %w", err)
+	}
+
+	return link, nil
+}
+
+// LimitLink sets or clears the click and unique visitor limits of a link; zero
+// disables a limit. Once a limit is reached, visitors are sent to the link's
+// fallback URL (see ScheduleLink) or shown a "no longer available" page.
+// Counts so far are kept, so a limit at or below them uses the link up at once.
+func (s *LinkService) LimitLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, maxClicks, maxVisitors uint64) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	link.MaxClicks = maxClicks
+	link.MaxVisitors = maxVisitors
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to limit link: %w", err)
+	}
+
+	return link, nil
+}
+
+// CountClick counts a visitor's click on a link and reports whether the visitor
+// may follow it, i.e. the link has not reached its click or visitor limit.
+// visitorID is empty for visitors without a visitor cookie.
+func (s *LinkService) CountClick(ctx context.Context, linkID domain.LinkID, visitorID string) (bool, error) {
+	allowed, err := s.repo.CountClick(ctx, linkID, visitorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to count click: %w", err)
+	}
+	return allowed, nil
+}
+
+// slugPattern allows lowercase letters, digits and inner hyphens, up to 64 characters.
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// maxGeneratedSlugBase keeps generated slugs short enough to print.
+const maxGeneratedSlugBase = 32
+
+// slugAlphabet is used for random slug suffixes.
+const slugAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// slugify turns arbitrary text into a slug candidate, e.g. "My Shop!" -> "my-shop".
+func slugify(text string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+		if b.Len() >= maxGeneratedSlugBase {
+			break
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// randomSlugSuffix returns n random characters from slugAlphabet.
+func randomSlugSuffix(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i := range buf {
+		buf[i] = slugAlphabet[int(buf[i])%len(slugAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// checkDuplicateURL returns a conflict error if a link other than exclude points to
+// the same destination as url. URLs are compared in canonical form, so case,
+// default ports, trailing slashes and tracking parameters do not matter.
+func checkDuplicateURL(links []*domain.Link, url string, exclude domain.LinkID) error {
+	canonical := urlcanon.Canonicalize(url)
+	for _, existing := range links {
+		if existing.ID != exclude && urlcanon.Canonicalize(existing.URL) == canonical {
+			return fmt.Errorf("link with this URL already exists: %w", domain.ErrConflict)
+		}
+	}
+	return nil
+}
 
-	return s.repo.Delete(ctx, linkID)
+// slugAvailable reports whether no other link uses slug.
+func (s *LinkService) slugAvailable(ctx context.Context, slug string, linkID domain.LinkID) bool {
+	existing, err := s.repo.GetBySlug(ctx, slug)
+	return err != nil || existing == nil || existing.ID == linkID
+}
+
+// generateSlug derives a unique slug from the title, adding a random suffix on collision.
+func (s *LinkService) generateSlug(ctx context.Context, title string) (string, error) {
+	base := slugify(title)
+	if base != "" && s.slugAvailable(ctx, base, "") {
+		return base, nil
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		suffix, err := randomSlugSuffix(6)
+		if err != nil {
+			return "", err
+		}
+		candidate := suffix
+		if base != "" {
+			candidate = base + "-" + suffix
+		}
+		if s.slugAvailable(ctx, candidate, "") {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a free slug for %q", title)
 }
 
-// ReorderLinks reorders links for a user.
+// SetLinkSlug sets the vanity slug of a link.
+// An empty slug generates a new one from the link title.
+// Returns an error wrapping domain.ErrConflict if the slug is taken by another link.
+func (s *LinkService) SetLinkSlug(ctx context.Context, linkID domain.LinkID, userID domain.UserID, slug string) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" {
+		if slug, err = s.generateSlug(ctx, link.Title); err != nil {
+			return nil, err
+		}
+	}
+	if !slugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("invalid slug %q: use lowercase letters, digits and hyphens: %w", slug, domain.ErrBadRequest)
+	}
+	if !s.slugAvailable(ctx, slug, link.ID) {
+		return nil, fmt.Errorf("slug %q is already in use: %w", slug, domain.ErrConflict)
+	}
+
+	link.Slug = slug
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save slug: %w", err)
+	}
+
+	return link, nil
+}
+
+// ResolveLink finds a link by ID, falling back to its vanity slug.
+// Links in the trash are not found.
+func (s *LinkService) ResolveLink(ctx context.Context, ref string) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, domain.LinkID(ref))
+	if err != nil || link == nil {
+		slug := strings.ToLower(ref)
+		if !slugPattern.MatchString(slug) {
+			return nil, fmt.Errorf("link %q: %w", ref, domain.ErrNotFound)
+		}
+		if link, err = s.repo.GetBySlug(ctx, slug); err != nil {
+			return nil, err
+		}
+	}
+
+	if link.IsTrashed() {
+		return nil, fmt.Errorf("link %q is in the trash: %w", ref, domain.ErrNotFound)
+	}
+	return link, nil
+}
+
+// maxLinkVariants caps the number of A/B destinations per link.
+const maxLinkVariants = 10
+
+// SetLinkVariants replaces the weighted A/B destinations of a link.
+// Variants are labelled "A", "B", ... in the given order; an empty list disables rotation.
+func (s *LinkService) SetLinkVariants(ctx context.Context, linkID domain.LinkID, userID domain.UserID, variants []domain.LinkVariant) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if len(variants) > maxLinkVariants {
+		return nil, fmt.Errorf("a link can have at most %d variants", maxLinkVariants)
+	}
+
+	named := make([]domain.LinkVariant, 0, len(variants))
+	for i, v := range variants {
+		if v.URL == "" {
+			return nil, fmt.Errorf("variant URL is required")
+		}
+		if err := s.CheckURL(v.URL); err != nil {
+			return nil, fmt.Errorf("variant URL rejected: %w", err)
+		}
+		if v.Weight < 1 {
+			return nil, fmt.Errorf("variant weight must be at least 1")
+		}
+		v.Name = string(rune('A' + i))
+		named = append(named, v)
+	}
+
+	link.Variants = named
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save variants: %w", err)
+	}
+
+	return link, nil
+}
+
+// maxRedirectRules caps the number of targeting rules per link.
+const maxRedirectRules = 20
+
+// SetLinkRules replaces the geo/device redirect rules of a link.
+// Rules are evaluated in order; the first match wins. Country codes are upper-cased
+// and device types lower-cased so they compare like the tracked values.
+func (s *LinkService) SetLinkRules(ctx context.Context, linkID domain.LinkID, userID domain.UserID, rules []domain.RedirectRule) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if len(rules) > maxRedirectRules {
+		return nil, fmt.Errorf("a link can have at most %d rules", maxRedirectRules)
+	}
+
+	normalized := make([]domain.RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.URL == "" {
+			return nil, fmt.Errorf("rule URL is required")
+		}
+		if err := s.CheckURL(rule.URL); err != nil {
+			return nil, fmt.Errorf("rule URL rejected: %w", err)
+		}
+
+		values := make([]string, 0, len(rule.Values))
+		for _, v := range rule.Values {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			switch rule.Field {
+			case domain.RuleFieldCountry:
+				v = strings.ToUpper(v)
+			case domain.RuleFieldDeviceType:
+				v = strings.ToLower(v)
+				if v != "mobile" && v != "desktop" {
+					return nil, fmt.Errorf("unknown device type %q", v)
+				}
+			default:
+				return nil, fmt.Errorf("unknown rule field %q", rule.Field)
+			}
+			values = append(values, v)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("rule needs at least one value")
+		}
+
+		rule.Values = values
+		normalized = append(normalized, rule)
+	}
+
+	link.Rules = normalized
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save rules: %w", err)
+	}
+
+	return link, nil
+}
+
+// minLinkPasswordLength is the shortest accepted link password.
+const minLinkPasswordLength = 4
+
+// ProtectLink sets the gate visitors must pass before being redirected.
+// For ProtectionPassword an empty password keeps the current one, if any.
+// Other modes drop the stored password hash.
+func (s *LinkService) ProtectLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, protection domain.LinkProtection, password string) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	switch protection {
+	case domain.ProtectionPassword:
+		if password == "" && link.PasswordHash == "" {
+			return nil, fmt.Errorf("a password is required")
+		}
+		if password != "" {
+			if len(password) < minLinkPasswordLength {
+				return nil, fmt.Errorf("password must be at least %d characters", minLinkPasswordLength)
+			}
+			hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash password: %w", err)
+			}
+			link.PasswordHash = string(hash)
+		}
+	case domain.ProtectionSensitive, domain.ProtectionNone:
+		link.PasswordHash = ""
+	default:
+		return nil, fmt.Errorf("unknown protection mode %q", protection)
+	}
+
+	link.Protection = protection
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save protection: %w", err)
+	}
+
+	return link, nil
+}
+
+// CheckLinkPassword reports whether password unlocks a password-protected link.
+func (s *LinkService) CheckLinkPassword(link *domain.Link, password string) bool {
+	if link.Protection != domain.ProtectionPassword || link.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) == nil
+}
+
+// SetLinkProduct sets the structured product fields of a link and turns it into a product link.
+// A nil product clears the fields and keeps the link type. Prices must be non-negative
+// decimals and need a currency; the currency is upper-cased.
+func (s *LinkService) SetLinkProduct(ctx context.Context, linkID domain.LinkID, userID domain.UserID, product *domain.Product) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if product != nil {
+		p := *product
+		for _, price := range []string{p.Price, p.SalePrice} {
+			if price == "" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(price, 64); err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid price %q: %w", price, domain.ErrBadRequest)
+			}
+		}
+		p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+		if (p.Price != "" || p.SalePrice != "") && p.Currency == "" {
+			return nil, fmt.Errorf("a currency is required with a price: %w", domain.ErrBadRequest)
+		}
+		p.SKU = strings.TrimSpace(p.SKU)
+		p.AffiliateNetwork = strings.ToLower(strings.TrimSpace(p.AffiliateNetwork))
+
+		link.Product = &p
+		link.Type = domain.LinkTypeProduct
+	} else {
+		link.Product = nil
+	}
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save product: %w", err)
+	}
+
+	return link, nil
+}
+
+// SetLinkUTM sets the UTM parameters that override the owner's defaults when
+// visitors are redirected. Empty fields fall back to the defaults; a nil or empty
+// utm removes the override.
+func (s *LinkService) SetLinkUTM(ctx context.Context, linkID domain.LinkID, userID domain.UserID, utm *domain.UTMParams) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if utm != nil && !utm.IsZero() {
+		u := *utm
+		link.UTM = &u
+	} else {
+		link.UTM = nil
+	}
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save UTM parameters: %w", err)
+	}
+
+	return link, nil
+}
+
+// SetLinkDeepLink turns the deep-link mode of a link on or off. In deep-link mode,
+// phone visitors are sent to the native app of the destination's platform when
+// socials.json has app links for it, falling back to the web URL.
+func (s *LinkService) SetLinkDeepLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID, enabled bool) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	link.DeepLink = enabled
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save deep link mode: %w", err)
+	}
+
+	return link, nil
+}
+
+// SetLinkThumbnail stores an uploaded image as the link's custom thumbnail, shown
+// instead of the OG image. A nil file removes the custom thumbnail.
+func (s *LinkService) SetLinkThumbnail(ctx context.Context, linkID domain.LinkID, userID domain.UserID, file io.Reader) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Verify ownership
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+
+	if link.Metadata == nil {
+		link.Metadata = make(map[string]string)
+	}
+	if file == nil {
+		delete(link.Metadata, domain.MetaCustomThumbnail)
+	} else {
+		if s.thumbnails == nil {
+			return nil, fmt.Errorf("thumbnail uploads are not configured: %w", domain.ErrBadRequest)
+		}
+		filename := fmt.Sprintf("thumbnails/%s_custom_%d", link.ID, time.Now().Unix())
+		stored, err := s.thumbnails.StoreThumbnail(ctx, file, filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+		}
+		link.Metadata[domain.MetaCustomThumbnail] = stored
+	}
+	link.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+
+	return link, nil
+}
+
+// AppLink returns the URI opening target in its platform's native app on os,
+// or an empty string when the platform is unknown or has no app link for os.
+func (s *LinkService) AppLink(target string, os domain.MobileOS) string {
+	if s.socialResolver == nil {
+		return ""
+	}
+	platform, err := s.socialResolver.Resolve(urlcanon.Canonicalize(target))
+	if err != nil || platform == nil {
+		return ""
+	}
+	return platform.AppLinks.For(os, target)
+}
+
+// RecordLinkHealth stores the result of a destination check on a link.
+// Consecutive broken checks are counted; when deactivateAfter is positive and the count
+// reaches it, the link is deactivated so visitors stop hitting a dead page.
+func (s *LinkService) RecordLinkHealth(ctx context.Context, linkID domain.LinkID, health *domain.LinkHealth, deactivateAfter int) (*domain.Link, error) {
+	// Re-read right before saving so concurrent dashboard edits are not overwritten
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	h := *health
+	h.Failures = 0
+	h.Deactivated = false
+	if h.IsBroken() {
+		h.Failures = 1
+		if link.Health != nil {
+			h.Failures = link.Health.Failures + 1
+			h.Deactivated = link.Health.Deactivated
+		}
+		if deactivateAfter > 0 && h.Failures >= deactivateAfter && link.IsActive {
+			link.IsActive = false
+			h.Deactivated = true
+			fmt.Printf("[WARN] Deactivated link %s after %d failed checks\n", link.ID, h.Failures)
+		}
+	}
+	link.Health = &h
+
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save link health: %w", err)
+	}
+
+	return link, nil
+}
+
+// maxImportRows bounds a single bulk import.
+const maxImportRows = 500
+
+// importedMetadataKeys are the metadata keys kept on import. Keys rendered as raw HTML
+// (social icons, embeds) are dropped and rebuilt by RefreshMetadata instead.
+var importedMetadataKeys = []string{"og:title", "og:description", "og:image"}
+
+// ExportLinks returns all of a user's links, including inactive ones, in display order.
+// Links in the trash are left out.
+func (s *LinkService) ExportLinks(ctx context.Context, userID domain.UserID) ([]domain.LinkRecord, error) {
+	links, err := s.activeLinks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+
+	records := make([]domain.LinkRecord, 0, len(links))
+	for _, link := range links {
+		records = append(records, domain.LinkRecord{
+			Title:    link.Title,
+			URL:      link.URL,
+			Type:     link.Type,
+			Order:    link.Order,
+			IsActive: link.IsActive,
+			Slug:     link.Slug,
+			Metadata: link.Metadata,
+		})
+	}
+	return records, nil
+}
+
+// ImportLinks creates links from records. Each row is validated on its own and
+// URLs already present (or repeated in the file) are skipped like CreateLink does.
+// In merge mode valid rows are imported and invalid ones reported; in replace mode
+// existing links are only moved to the trash when every row is valid.
+// Metadata is not fetched for imported links to keep large imports fast.
+func (s *LinkService) ImportLinks(ctx context.Context, userID domain.UserID, records []domain.LinkRecord, mode domain.LinkImportMode) (*domain.LinkImportResult, error) {
+	if mode == "" {
+		mode = domain.ImportMerge
+	}
+	if mode != domain.ImportMerge && mode != domain.ImportReplace {
+		return nil, fmt.Errorf("unknown import mode %q: %w", mode, domain.ErrBadRequest)
+	}
+	if len(records) > maxImportRows {
+		return nil, fmt.Errorf("an import can have at most %d links: %w", maxImportRows, domain.ErrBadRequest)
+	}
+
+	existing, err := s.activeLinks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch existing links: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	if mode == domain.ImportMerge {
+		for _, link := range existing {
+			seen[urlcanon.Canonicalize(link.URL)] = true
+		}
+	}
+
+	result := &domain.LinkImportResult{}
+	var valid []domain.LinkRecord
+	for i, record := range records {
+		row := i + 1
+		if record.Type == "" {
+			record.Type = domain.LinkTypeStandard
+		}
+		if err := validator.ValidateStruct(record); err != nil {
+			result.Errors = append(result.Errors, domain.LinkImportIssue{Row: row, URL: record.URL, Message: err.Error()})
+			continue
+		}
+		if record.Slug != "" && !slugPattern.MatchString(record.Slug) {
+			result.Errors = append(result.Errors, domain.LinkImportIssue{Row: row, URL: record.URL, Message: "invalid slug"})
+			continue
+		}
+		if err := s.CheckURL(record.URL); err != nil {
+			result.Errors = append(result.Errors, domain.LinkImportIssue{Row: row, URL: record.URL, Message: err.Error()})
+			continue
+		}
+		key := urlcanon.Canonicalize(record.URL)
+		if seen[key] {
+			result.Skipped = append(result.Skipped, domain.LinkImportIssue{Row: row, URL: record.URL, Message: "link with this URL already exists"})
+			continue
+		}
+		seen[key] = true
+		valid = append(valid, record)
+	}
+
+	if mode == domain.ImportReplace {
+		if len(result.Errors) > 0 {
+			return result, nil
+		}
+		for _, link := range existing {
+			if err := s.trashLink(ctx, link, userID); err != nil {
+				return nil, err
+			}
+		}
+		existing = nil
+	}
+
+	// Imported links keep their relative order and go after the links kept by a merge
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].Order < valid[j].Order })
+	order := nextOrder(existing)
+	now := time.Now()
+	for _, record := range valid {
+		link := &domain.Link{
+			ID:        domain.LinkID(uuid.New().String()),
+			UserID:    userID,
+			Title:     record.Title,
+			URL:       record.URL,
+			Type:      record.Type,
+			Order:     order,
+			IsActive:  record.IsActive,
+			Metadata:  make(map[string]string),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		for _, key := range importedMetadataKeys {
+			if v := record.Metadata[key]; v != "" {
+				link.Metadata[key] = v
+			}
+		}
+
+		if record.Slug != "" && s.slugAvailable(ctx, record.Slug, link.ID) {
+			link.Slug = record.Slug
+		} else if slug, err := s.generateSlug(ctx, record.Title); err == nil {
+			link.Slug = slug
+		}
+
+		if link.Type == domain.LinkTypeSocial && s.socialResolver != nil {
+			if platform, err := s.socialResolver.Resolve(urlcanon.Canonicalize(link.URL)); err == nil && platform != nil {
+				link.Metadata["social:name"] = platform.Name
+				link.Metadata["social:icon"] = platform.IconSVG
+				link.Metadata["social:color"] = platform.Color
+			}
+		}
+
+		if err := s.repo.Save(ctx, link); err != nil {
+			return nil, fmt.Errorf("failed to save imported link %q: %w", record.URL, err)
+		}
+		order++
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// ReorderLinks reorders links for a user. Links stay in their current group;
+// use LinkGroupService.ArrangeLinks to move them between groups.
 func (s *LinkService) ReorderLinks(ctx context.Context, userID domain.UserID, orderedIDs []domain.LinkID) error {
-	return s.repo.Reorder(ctx, userID, orderedIDs)
+	links, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch links: %w", err)
+	}
+	groupOf := make(map[domain.LinkID]domain.LinkGroupID, len(links))
+	for _, link := range links {
+		groupOf[link.ID] = link.GroupID
+	}
+
+	placements := make([]domain.LinkPlacement, len(orderedIDs))
+	for i, id := range orderedIDs {
+		placements[i] = domain.LinkPlacement{LinkID: id, GroupID: groupOf[id]}
+	}
+	return s.repo.Reorder(ctx, userID, placements)
 }
 
-// ListLinks returns all links for a user, ordered by position.
+// ListLinks returns the links of a user whose publish window is open right now,
+// ordered by position. Links that are scheduled for later, already expired or in
+// the trash are skipped.
 func (s *LinkService) ListLinks(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
-	return s.repo.ListByUser(ctx, userID)
+	links, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	visible := make([]*domain.Link, 0, len(links))
+	for _, link := range links {
+		if !link.IsTrashed() && link.IsWithinSchedule(now) {
+			visible = append(visible, link)
+		}
+	}
+	return visible, nil
+}
+
+// ListAllLinks returns every link of a user, including scheduled and expired ones,
+// ordered by position. It is meant for the owner's dashboard; trashed links are
+// listed by ListTrash instead.
+func (s *LinkService) ListAllLinks(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
+	return s.activeLinks(ctx, userID)
 }
 
-// GetLink retrieves a single link by ID.
+// GetLink retrieves a single link by ID. Links in the trash are not found.
 func (s *LinkService) GetLink(ctx context.Context, linkID domain.LinkID) (*domain.Link, error) {
-	return s.repo.GetByID(ctx, linkID)
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.IsTrashed() {
+		return nil, fmt.Errorf("link %s is in the trash: %w", linkID, domain.ErrNotFound)
+	}
+	return link, nil
 }
@@ -2,6 +2,9 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,7 +17,7 @@ func TestLinkService_CreateLink(t *testing.T) {
 	ctx := context.Background()
 	repo := mocks.NewMockLinkRepository()
 	metadataFetcher := mocks.NewMockMetadataFetcher()
-	svc := service.NewLinkService(repo, metadataFetcher, nil)
+	svc := service.NewLinkService(repo, metadataFetcher, nil, nil, nil, nil)
 	userID := domain.UserID("user-123")
 
 	t.Run("creates link successfully", func(t *testing.T) {
@@ -75,13 +78,20 @@ func TestLinkService_CreateLink(t *testing.T) {
 			t.Errorf("expected order >= 2, got %d", link.Order)
 		}
 	})
+
+	t.Run("rejects duplicate URL in another form", func(t *testing.T) {
+		_, err := svc.CreateLink(ctx, userID, "Again", "https://EXAMPLE.com:443/?utm_source=ig", domain.LinkTypeStandard)
+		if !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict, got %v", err)
+		}
+	})
 }
 
 func TestLinkService_UpdateLink(t *testing.T) {
 	ctx := context.Background()
 	repo := mocks.NewMockLinkRepository()
 	metadataFetcher := mocks.NewMockMetadataFetcher()
-	svc := service.NewLinkService(repo, metadataFetcher, nil)
+	svc := service.NewLinkService(repo, metadataFetcher, nil, nil, nil, nil)
 	userID := domain.UserID("user-123")
 	linkID := domain.LinkID("link-456")
 
@@ -138,13 +148,28 @@ func TestLinkService_UpdateLink(t *testing.T) {
 			t.Error("expected error for unauthorized user")
 		}
 	})
+
+	t.Run("rejects URL of another link", func(t *testing.T) {
+		repo.AddLink(&domain.Link{ID: "link-other", UserID: userID, URL: "https://other.com/page"})
+
+		taken := "https://Other.com/page/?fbclid=abc"
+		if _, err := svc.UpdateLink(ctx, linkID, userID, nil, &taken, nil, nil); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict, got %v", err)
+		}
+
+		// Rewriting its own URL in another form is not a duplicate
+		same := "https://UPDATED.com/"
+		if _, err := svc.UpdateLink(ctx, linkID, userID, nil, &same, nil, nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
 }
 
 func TestLinkService_DeleteLink(t *testing.T) {
 	ctx := context.Background()
 	repo := mocks.NewMockLinkRepository()
 	metadataFetcher := mocks.NewMockMetadataFetcher()
-	svc := service.NewLinkService(repo, metadataFetcher, nil)
+	svc := service.NewLinkService(repo, metadataFetcher, nil, nil, nil, nil)
 	userID := domain.UserID("user-123")
 	linkID := domain.LinkID("link-789")
 
@@ -153,16 +178,19 @@ func TestLinkService_DeleteLink(t *testing.T) {
 		UserID: userID,
 	})
 
-	t.Run("deletes link successfully", func(t *testing.T) {
+	t.Run("moves link to the trash", func(t *testing.T) {
 		err := svc.DeleteLink(ctx, linkID, userID)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
-		// Verify deleted
-		_, err = repo.GetByID(ctx, linkID)
-		if err == nil {
-			t.Error("expected link to be deleted")
+		// Verify trashed and hidden
+		link, err := repo.GetByID(ctx, linkID)
+		if err != nil || !link.IsTrashed() {
+			t.Errorf("expected link to be kept in the trash, got %+v (%v)", link, err)
+		}
+		if _, err := svc.GetLink(ctx, linkID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("expected trashed link to be hidden, got %v", err)
 		}
 	})
 
@@ -185,7 +213,7 @@ func TestLinkService_ListLinks(t *testing.T) {
 	ctx := context.Background()
 	repo := mocks.NewMockLinkRepository()
 	metadataFetcher := mocks.NewMockMetadataFetcher()
-	svc := service.NewLinkService(repo, metadataFetcher, nil)
+	svc := service.NewLinkService(repo, metadataFetcher, nil, nil, nil, nil)
 	userID := domain.UserID("user-list")
 
 	// Add links
@@ -216,3 +244,719 @@ func TestLinkService_ListLinks(t *testing.T) {
 		}
 	})
 }
+
+func TestLinkService_ListLinks_Schedule(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, mocks.NewMockMetadataFetcher(), nil, nil, nil, nil)
+	userID := domain.UserID("user-schedule")
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	repo.AddLink(&domain.Link{ID: "live", UserID: userID, Order: 0, StartsAt: &past, EndsAt: &future})
+	repo.AddLink(&domain.Link{ID: "pending", UserID: userID, Order: 1, StartsAt: &future})
+	repo.AddLink(&domain.Link{ID: "expired", UserID: userID, Order: 2, EndsAt: &past})
+	repo.AddLink(&domain.Link{ID: "always", UserID: userID, Order: 3})
+
+	t.Run("hides links outside their window", func(t *testing.T) {
+		links, err := svc.ListLinks(ctx, userID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(links) != 2 {
+			t.Fatalf("expected 2 links, got %d", len(links))
+		}
+		if links[0].ID != "live" || links[1].ID != "always" {
+			t.Errorf("unexpected links: %s, %s", links[0].ID, links[1].ID)
+		}
+	})
+
+	t.Run("ListAllLinks keeps every link", func(t *testing.T) {
+		links, err := svc.ListAllLinks(ctx, userID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(links) != 4 {
+			t.Errorf("expected 4 links, got %d", len(links))
+		}
+	})
+}
+
+func TestLinkService_ScheduleLink(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, mocks.NewMockMetadataFetcher(), nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+	linkID := domain.LinkID("link-campaign")
+
+	repo.AddLink(&domain.Link{ID: linkID, UserID: userID, IsActive: true})
+
+	start := time.Now().Add(time.Hour)
+	end := start.Add(24 * time.Hour)
+
+	t.Run("sets window and fallback", func(t *testing.T) {
+		link, err := svc.ScheduleLink(ctx, linkID, userID, &start, &end, "https://example.com/sold-out")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.StartsAt == nil || !link.StartsAt.Equal(start) {
+			t.Errorf("expected start %v, got %v", start, link.StartsAt)
+		}
+		if link.EndsAt == nil || !link.EndsAt.Equal(end) {
+			t.Errorf("expected end %v, got %v", end, link.EndsAt)
+		}
+		if link.FallbackURL != "https://example.com/sold-out" {
+			t.Errorf("expected fallback URL, got %q", link.FallbackURL)
+		}
+	})
+
+	t.Run("rejects end before start", func(t *testing.T) {
+		_, err := svc.ScheduleLink(ctx, linkID, userID, &end, &start, "")
+		if err == nil {
+			t.Error("expected error for inverted window")
+		}
+	})
+
+	t.Run("clears window", func(t *testing.T) {
+		link, err := svc.ScheduleLink(ctx, linkID, userID, nil, nil, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.StartsAt != nil || link.EndsAt != nil {
+			t.Error("expected window to be cleared")
+		}
+	})
+
+	t.Run("rejects other user", func(t *testing.T) {
+		_, err := svc.ScheduleLink(ctx, linkID, domain.UserID("other-user"), nil, nil, "")
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_CreateLink_GeneratesSlug(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	first, err := svc.CreateLink(ctx, userID, "My Shop!", "https://shop.example.com", domain.LinkTypeStandard)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.Slug != "my-shop" {
+		t.Errorf("expected slug 'my-shop', got %q", first.Slug)
+	}
+
+	// Same title from another user collides and gets a random suffix
+	second, err := svc.CreateLink(ctx, domain.UserID("user-456"), "My Shop!", "https://shop.example.com", domain.LinkTypeStandard)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(second.Slug, "my-shop-") || second.Slug == first.Slug {
+		t.Errorf("expected suffixed slug, got %q", second.Slug)
+	}
+}
+
+func TestLinkService_SetLinkSlug(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, Title: "Merch"})
+	repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, Title: "Tour", Slug: "tour"})
+
+	t.Run("sets custom slug", func(t *testing.T) {
+		link, err := svc.SetLinkSlug(ctx, "link-1", userID, "  Summer-Drop ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.Slug != "summer-drop" {
+			t.Errorf("expected 'summer-drop', got %q", link.Slug)
+		}
+
+		resolved, err := svc.ResolveLink(ctx, "summer-drop")
+		if err != nil || resolved.ID != "link-1" {
+			t.Errorf("expected slug to resolve to link-1, got %v (%v)", resolved, err)
+		}
+	})
+
+	t.Run("rejects taken slug", func(t *testing.T) {
+		_, err := svc.SetLinkSlug(ctx, "link-1", userID, "tour")
+		if !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid slug", func(t *testing.T) {
+		_, err := svc.SetLinkSlug(ctx, "link-1", userID, "no spaces/allowed")
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+
+	t.Run("empty slug regenerates from title", func(t *testing.T) {
+		link, err := svc.SetLinkSlug(ctx, "link-1", userID, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.Slug != "merch" {
+			t.Errorf("expected 'merch', got %q", link.Slug)
+		}
+	})
+
+	t.Run("rejects other user", func(t *testing.T) {
+		_, err := svc.SetLinkSlug(ctx, "link-1", domain.UserID("other-user"), "mine")
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_SetLinkVariants(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com"})
+
+	t.Run("labels variants in order", func(t *testing.T) {
+		link, err := svc.SetLinkVariants(ctx, "link-1", userID, []domain.LinkVariant{
+			{URL: "https://a.example.com", Weight: 70},
+			{URL: "https://b.example.com", Weight: 30},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(link.Variants) != 2 || link.Variants[0].Name != "A" || link.Variants[1].Name != "B" {
+			t.Errorf("expected variants A and B, got %+v", link.Variants)
+		}
+	})
+
+	t.Run("sticks to a variant per visitor", func(t *testing.T) {
+		link, _ := svc.GetLink(ctx, "link-1")
+		first := link.VariantFor("visitor-1")
+		for i := 0; i < 10; i++ {
+			if got := link.VariantFor("visitor-1"); got.Name != first.Name {
+				t.Fatalf("expected sticky variant %s, got %s", first.Name, got.Name)
+			}
+		}
+	})
+
+	t.Run("rejects zero weight", func(t *testing.T) {
+		_, err := svc.SetLinkVariants(ctx, "link-1", userID, []domain.LinkVariant{{URL: "https://a.example.com", Weight: 0}})
+		if err == nil {
+			t.Error("expected error for zero weight")
+		}
+	})
+
+	t.Run("empty list disables rotation", func(t *testing.T) {
+		link, err := svc.SetLinkVariants(ctx, "link-1", userID, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.VariantFor("visitor-1") != nil {
+			t.Error("expected no variant after clearing")
+		}
+	})
+
+	t.Run("rejects other user", func(t *testing.T) {
+		_, err := svc.SetLinkVariants(ctx, "link-1", domain.UserID("other-user"), nil)
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_SetLinkRules(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com"})
+
+	t.Run("normalizes values", func(t *testing.T) {
+		link, err := svc.SetLinkRules(ctx, "link-1", userID, []domain.RedirectRule{
+			{Field: domain.RuleFieldCountry, Values: []string{" de", "at "}, URL: "https://example.de"},
+			{Field: domain.RuleFieldDeviceType, Values: []string{"Mobile"}, URL: "https://apps.example.com"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := link.Rules[0].Values; len(got) != 2 || got[0] != "DE" || got[1] != "AT" {
+			t.Errorf("expected [DE AT], got %v", got)
+		}
+		if got := link.Rules[1].Values; len(got) != 1 || got[0] != "mobile" {
+			t.Errorf("expected [mobile], got %v", got)
+		}
+
+		if rule := link.MatchRule("at", "mobile"); rule == nil || rule.URL != "https://example.de" {
+			t.Errorf("expected country rule to win, got %v", rule)
+		}
+		if rule := link.MatchRule("US", "mobile"); rule == nil || rule.URL != "https://apps.example.com" {
+			t.Errorf("expected device rule, got %v", rule)
+		}
+		if rule := link.MatchRule("US", "desktop"); rule != nil {
+			t.Errorf("expected no rule, got %v", rule)
+		}
+	})
+
+	t.Run("rejects unknown device", func(t *testing.T) {
+		_, err := svc.SetLinkRules(ctx, "link-1", userID, []domain.RedirectRule{
+			{Field: domain.RuleFieldDeviceType, Values: []string{"tablet"}, URL: "https://example.com"},
+		})
+		if err == nil {
+			t.Error("expected error for unknown device type")
+		}
+	})
+
+	t.Run("rejects other user", func(t *testing.T) {
+		_, err := svc.SetLinkRules(ctx, "link-1", domain.UserID("other-user"), nil)
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_ProtectLink(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com"})
+
+	t.Run("hashes password", func(t *testing.T) {
+		link, err := svc.ProtectLink(ctx, "link-1", userID, domain.ProtectionPassword, "s3cret")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.PasswordHash == "" || link.PasswordHash == "s3cret" {
+			t.Fatalf("expected a password hash, got %q", link.PasswordHash)
+		}
+		if !svc.CheckLinkPassword(link, "s3cret") {
+			t.Error("expected correct password to unlock")
+		}
+		if svc.CheckLinkPassword(link, "wrong") {
+			t.Error("expected wrong password to be rejected")
+		}
+
+		data, _ := json.Marshal(link)
+		if strings.Contains(string(data), link.PasswordHash) || strings.Contains(string(data), "password_hash") {
+			t.Errorf("password hash leaked into JSON: %s", data)
+		}
+		for _, v := range link.Metadata {
+			if v == link.PasswordHash {
+				t.Error("password hash leaked into metadata")
+			}
+		}
+	})
+
+	t.Run("empty password keeps current hash", func(t *testing.T) {
+		before, _ := svc.GetLink(ctx, "link-1")
+		hash := before.PasswordHash
+		link, err := svc.ProtectLink(ctx, "link-1", userID, domain.ProtectionPassword, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.PasswordHash != hash {
+			t.Error("expected password hash to be kept")
+		}
+	})
+
+	t.Run("sensitive mode drops password", func(t *testing.T) {
+		link, err := svc.ProtectLink(ctx, "link-1", userID, domain.ProtectionSensitive, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.PasswordHash != "" {
+			t.Error("expected password hash to be cleared")
+		}
+	})
+
+	t.Run("password mode requires a password", func(t *testing.T) {
+		if _, err := svc.ProtectLink(ctx, "link-1", userID, domain.ProtectionPassword, ""); err == nil {
+			t.Error("expected error without password")
+		}
+	})
+
+	t.Run("rejects other user", func(t *testing.T) {
+		if _, err := svc.ProtectLink(ctx, "link-1", domain.UserID("other-user"), domain.ProtectionNone, ""); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_SetLinkProduct(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://amazon.com/dp/B000", Type: domain.LinkTypeStandard})
+
+	t.Run("normalizes and sets product type", func(t *testing.T) {
+		link, err := svc.SetLinkProduct(ctx, "link-1", userID, &domain.Product{
+			Price:            "49.90",
+			SalePrice:        "39.90",
+			Currency:         "eur ",
+			SKU:              " SKU-1 ",
+			AffiliateNetwork: "Amazon",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if link.Type != domain.LinkTypeProduct {
+			t.Errorf("expected product type, got %s", link.Type)
+		}
+		if link.Product.Currency != "EUR" || link.Product.SKU != "SKU-1" || link.Product.AffiliateNetwork != "amazon" {
+			t.Errorf("unexpected product %+v", link.Product)
+		}
+		if !link.Product.OnSale() || link.Product.CurrentPrice() != "39.90" {
+			t.Errorf("expected sale price to apply, got %s", link.Product.CurrentPrice())
+		}
+		if !link.IsAffiliate() {
+			t.Error("expected affiliate link")
+		}
+	})
+
+	t.Run("rejects negative price", func(t *testing.T) {
+		_, err := svc.SetLinkProduct(ctx, "link-1", userID, &domain.Product{Price: "-1", Currency: "EUR"})
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+
+	t.Run("requires currency with price", func(t *testing.T) {
+		_, err := svc.SetLinkProduct(ctx, "link-1", userID, &domain.Product{Price: "10"})
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		if _, err := svc.SetLinkProduct(ctx, "link-1", "other-user", nil); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for unauthorized user, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_RecordLinkHealth(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com", IsActive: true})
+
+	broken := &domain.LinkHealth{StatusCode: 404, CheckedAt: time.Now()}
+
+	link, err := svc.RecordLinkHealth(ctx, "link-1", broken, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if link.Health.Failures != 1 || !link.IsActive {
+		t.Fatalf("expected first failure to keep link active, got %+v", link.Health)
+	}
+
+	link, _ = svc.RecordLinkHealth(ctx, "link-1", broken, 2)
+	if link.IsActive || !link.Health.Deactivated {
+		t.Fatalf("expected link to be deactivated after 2 failures, got %+v", link.Health)
+	}
+
+	// Re-activating by hand resets the count
+	active := true
+	link, _ = svc.UpdateLink(ctx, "link-1", userID, nil, nil, nil, &active)
+	if link.Health.Failures != 0 || link.Health.Deactivated {
+		t.Errorf("expected reset health after re-activation, got %+v", link.Health)
+	}
+
+	link, _ = svc.RecordLinkHealth(ctx, "link-1", &domain.LinkHealth{StatusCode: 200, CheckedAt: time.Now()}, 2)
+	if link.Health.IsBroken() || link.Health.Failures != 0 || !link.IsActive {
+		t.Errorf("expected healthy link, got %+v", link.Health)
+	}
+}
+
+func TestLinkService_ImportLinks(t *testing.T) {
+	ctx := context.Background()
+	userID := domain.UserID("user-123")
+
+	t.Run("merge skips duplicates and reports invalid rows", func(t *testing.T) {
+		repo := mocks.NewMockLinkRepository()
+		svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com", Order: 0})
+
+		result, err := svc.ImportLinks(ctx, userID, []domain.LinkRecord{
+			{Title: "Existing", URL: "https://example.com", IsActive: true},
+			{Title: "Blog", URL: "https://blog.example.com", IsActive: true, Slug: "blog", Metadata: map[string]string{"og:image": "https://img", "social:icon": "<svg/>"}},
+			{Title: "Blog again", URL: "https://blog.example.com", IsActive: true},
+			{Title: "", URL: "not a url"},
+		}, domain.ImportMerge)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Created != 1 || len(result.Skipped) != 2 || len(result.Errors) != 1 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+		if result.Errors[0].Row != 4 {
+			t.Errorf("expected error on row 4, got %d", result.Errors[0].Row)
+		}
+
+		links, _ := repo.ListByUser(ctx, userID)
+		if len(links) != 2 {
+			t.Fatalf("expected 2 links, got %d", len(links))
+		}
+		imported := links[1]
+		if imported.Slug != "blog" || imported.Order != 1 || imported.Type != domain.LinkTypeStandard {
+			t.Errorf("unexpected imported link %+v", imported)
+		}
+		if imported.Metadata["og:image"] != "https://img" {
+			t.Error("expected og metadata to be imported")
+		}
+		if _, ok := imported.Metadata["social:icon"]; ok {
+			t.Error("expected non-og metadata to be dropped")
+		}
+	})
+
+	t.Run("merge numbers links after the highest kept order", func(t *testing.T) {
+		repo := mocks.NewMockLinkRepository()
+		svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://one.example.com", Order: 0})
+		repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, URL: "https://two.example.com", Order: 1})
+		if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+
+		if _, err := svc.ImportLinks(ctx, userID, []domain.LinkRecord{
+			{Title: "New", URL: "https://new.example.com", IsActive: true},
+		}, domain.ImportMerge); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		links, _ := svc.ListAllLinks(ctx, userID)
+		if len(links) != 2 || links[0].ID != "link-2" || links[1].Order != 2 {
+			t.Errorf("expected the imported link after link-2, got %+v", links)
+		}
+	})
+
+	t.Run("replace leaves links untouched when a row is invalid", func(t *testing.T) {
+		repo := mocks.NewMockLinkRepository()
+		svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com"})
+
+		result, err := svc.ImportLinks(ctx, userID, []domain.LinkRecord{
+			{Title: "New", URL: "https://new.example.com"},
+			{Title: "Bad", URL: "ftp//broken"},
+		}, domain.ImportReplace)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Created != 0 || len(result.Errors) != 1 {
+			t.Errorf("unexpected result %+v", result)
+		}
+		if _, err := repo.GetByID(ctx, "link-1"); err != nil {
+			t.Error("expected existing link to survive a failed replace")
+		}
+	})
+
+	t.Run("replace swaps all links", func(t *testing.T) {
+		repo := mocks.NewMockLinkRepository()
+		svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+		repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://example.com"})
+
+		result, err := svc.ImportLinks(ctx, userID, []domain.LinkRecord{
+			{Title: "Same URL", URL: "https://example.com"},
+		}, domain.ImportReplace)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Created != 1 {
+			t.Errorf("expected 1 created link, got %d", result.Created)
+		}
+		links, _ := svc.ListAllLinks(ctx, userID)
+		if len(links) != 1 || links[0].ID == "link-1" {
+			t.Errorf("expected the old link to be replaced, got %+v", links)
+		}
+		if trash, _ := svc.ListTrash(ctx, userID); len(trash) != 1 || trash[0].ID != "link-1" {
+			t.Errorf("expected the old link in the trash, got %+v", trash)
+		}
+	})
+
+	t.Run("rejects unknown mode", func(t *testing.T) {
+		svc := service.NewLinkService(mocks.NewMockLinkRepository(), nil, nil, nil, nil, nil)
+		_, err := svc.ImportLinks(ctx, userID, nil, "append")
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+}
+
+func TestLinkService_SafetyPolicy(t *testing.T) {
+	ctx := context.Background()
+	userID := domain.UserID("user-123")
+	repo := mocks.NewMockLinkRepository()
+	safety := mocks.NewMockLinkSafetyChecker("malware.example")
+	svc := service.NewLinkService(repo, nil, nil, nil, safety, nil)
+
+	t.Run("CreateLink", func(t *testing.T) {
+		if _, err := svc.CreateLink(ctx, userID, "Bad", "https://malware.example/x", domain.LinkTypeStandard); !errors.Is(err, domain.ErrUnsafeURL) {
+			t.Errorf("expected unsafe URL error, got %v", err)
+		}
+		if _, err := svc.CreateLink(ctx, userID, "Good", "https://good.example", domain.LinkTypeStandard); err != nil {
+			t.Errorf("expected safe link to be created, got %v", err)
+		}
+	})
+
+	t.Run("UpdateLink", func(t *testing.T) {
+		links, _ := svc.ListLinks(ctx, userID)
+		bad := "https://malware.example/x"
+		if _, err := svc.UpdateLink(ctx, links[0].ID, userID, nil, &bad, nil, nil); !errors.Is(err, domain.ErrUnsafeURL) {
+			t.Errorf("expected unsafe URL error, got %v", err)
+		}
+		if stored, _ := repo.GetByID(ctx, links[0].ID); stored.URL != "https://good.example" {
+			t.Errorf("expected the URL to be unchanged, got %s", stored.URL)
+		}
+	})
+
+	t.Run("SetLinkRules", func(t *testing.T) {
+		links, _ := svc.ListLinks(ctx, userID)
+		_, err := svc.SetLinkRules(ctx, links[0].ID, userID, []domain.RedirectRule{
+			{Field: domain.RuleFieldCountry, Values: []string{"DE"}, URL: "https://malware.example/de"},
+		})
+		if !errors.Is(err, domain.ErrUnsafeURL) {
+			t.Errorf("expected unsafe URL error, got %v", err)
+		}
+	})
+
+	t.Run("ImportLinks", func(t *testing.T) {
+		result, err := svc.ImportLinks(ctx, userID, []domain.LinkRecord{
+			{Title: "Bad", URL: "https://cdn.malware.example"},
+			{Title: "Fine", URL: "https://fine.example"},
+		}, domain.ImportMerge)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Created != 1 || len(result.Errors) != 1 || result.Errors[0].Row != 1 {
+			t.Errorf("expected the unsafe row to be reported, got %+v", result)
+		}
+	})
+}
+
+func TestLinkService_LimitLink(t *testing.T) {
+	ctx := context.Background()
+	userID := domain.UserID("user-123")
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://drop.example", IsActive: true})
+
+	if _, err := svc.LimitLink(ctx, "link-1", "user-other", 1, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+
+	link, err := svc.LimitLink(ctx, "link-1", userID, 2, 0)
+	if err != nil {
+		t.Fatalf("LimitLink failed: %v", err)
+	}
+	if !link.IsCapped() || link.IsExhausted() {
+		t.Errorf("expected a capped link with clicks left, got %+v", link)
+	}
+
+	for i, want := range []bool{true, true, false} {
+		allowed, err := svc.CountClick(ctx, "link-1", "")
+		if err != nil {
+			t.Fatalf("CountClick failed: %v", err)
+		}
+		if allowed != want {
+			t.Errorf("click %d: expected allowed=%v, got %v", i+1, want, allowed)
+		}
+	}
+
+	link, _ = svc.GetLink(ctx, "link-1")
+	if !link.IsExhausted() || link.ClickCount != 2 {
+		t.Errorf("expected the link to be used up after 2 clicks, got %d clicks", link.ClickCount)
+	}
+
+	// Removing the limit frees the link again
+	if _, err := svc.LimitLink(ctx, "link-1", userID, 0, 0); err != nil {
+		t.Fatalf("LimitLink failed: %v", err)
+	}
+	if allowed, _ := svc.CountClick(ctx, "link-1", ""); !allowed {
+		t.Error("expected clicks to be allowed without a limit")
+	}
+}
+
+func TestLinkService_Thumbnails(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	metadataFetcher := mocks.NewMockMetadataFetcher()
+	thumbnails := mocks.NewMockThumbnailStore()
+	svc := service.NewLinkService(repo, metadataFetcher, nil, nil, nil, thumbnails)
+	userID := domain.UserID("user-123")
+
+	metadataFetcher.AddMetadata("https://shop.example.com/item", &domain.LinkMetadata{Title: "Item", ImageURL: "/img/item.png"})
+	link, err := svc.CreateLink(ctx, userID, "Item", "https://shop.example.com/item", domain.LinkTypeStandard)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("caches the OG image", func(t *testing.T) {
+		if len(thumbnails.CacheCalls) != 1 || thumbnails.CacheCalls[0] != "https://shop.example.com/img/item.png" {
+			t.Fatalf("expected the relative OG image to be resolved and cached, got %v", thumbnails.CacheCalls)
+		}
+		if link.Metadata["og:image"] != "/img/item.png" {
+			t.Errorf("expected the original OG image to be kept, got %q", link.Metadata["og:image"])
+		}
+		if got := link.ThumbnailURL(); !strings.HasPrefix(got, thumbnails.BaseURL+"/thumbnails/"+string(link.ID)) {
+			t.Errorf("expected the stored copy as thumbnail, got %q", got)
+		}
+	})
+
+	t.Run("custom thumbnail wins and can be removed", func(t *testing.T) {
+		updated, err := svc.SetLinkThumbnail(ctx, link.ID, userID, strings.NewReader("image"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := updated.ThumbnailURL(); got != updated.Metadata[domain.MetaCustomThumbnail] || got == "" {
+			t.Errorf("expected the custom thumbnail, got %q", got)
+		}
+
+		updated, err = svc.SetLinkThumbnail(ctx, link.ID, userID, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := updated.ThumbnailURL(); got != updated.Metadata[domain.MetaThumbnail] {
+			t.Errorf("expected the cached OG image after removal, got %q", got)
+		}
+	})
+
+	t.Run("rejects other users", func(t *testing.T) {
+		_, err := svc.SetLinkThumbnail(ctx, link.ID, "someone-else", strings.NewReader("image"))
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden, got %v", err)
+		}
+	})
+
+	t.Run("keeps the remote image when caching fails", func(t *testing.T) {
+		thumbnails.CacheErr = errors.New("boom")
+		metadataFetcher.AddMetadata("https://other.example.com", &domain.LinkMetadata{Title: "Other", ImageURL: "https://cdn.other.example.com/og.jpg"})
+		other, err := svc.CreateLink(ctx, userID, "Other", "https://other.example.com", domain.LinkTypeStandard)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := other.ThumbnailURL(); got != "https://cdn.other.example.com/og.jpg" {
+			t.Errorf("expected the remote OG image, got %q", got)
+		}
+	})
+
+	t.Run("uploads need a thumbnail store", func(t *testing.T) {
+		plain := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+		_, err := plain.SetLinkThumbnail(ctx, link.ID, userID, strings.NewReader("image"))
+		if !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+)
+
+// activeLinks returns the user's links that are not in the trash, ordered by position.
+func (s *LinkService) activeLinks(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
+	links, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*domain.Link, 0, len(links))
+	for _, link := range links {
+		if !link.IsTrashed() {
+			active = append(active, link)
+		}
+	}
+	return active, nil
+}
+
+// nextOrder returns the position after the last of links. Trashing does not
+// compact the other links, so their orders may have gaps and the count of
+// links can be lower than the highest order.
+func nextOrder(links []*domain.Link) int {
+	order := 0
+	for _, link := range links {
+		if link.Order >= order {
+			order = link.Order + 1
+		}
+	}
+	return order
+}
+
+// trashLink marks a link as deleted and records the deletion in its history.
+func (s *LinkService) trashLink(ctx context.Context, link *domain.Link, userID domain.UserID) error {
+	before := snapshotLink(link)
+
+	now := time.Now()
+	link.DeletedAt = &now
+	link.UpdatedAt = now
+	if err := s.repo.Save(ctx, link); err != nil {
+		return fmt.Errorf("failed to move link %s to the trash: %w", link.ID, err)
+	}
+	s.recordRevision(ctx, userID, domain.RevisionDelete, before, nil)
+	return nil
+}
+
+// ListTrash returns the user's trashed links, most recently deleted first.
+func (s *LinkService) ListTrash(ctx context.Context, userID domain.UserID) ([]*domain.Link, error) {
+	links, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var trashed []*domain.Link
+	for _, link := range links {
+		if link.IsTrashed() {
+			trashed = append(trashed, link)
+		}
+	}
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(*trashed[j].DeletedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreLink takes a link out of the trash and puts it back at the end of the list.
+// It keeps its slug, which stays reserved while the link is in the trash, and fails
+// with domain.ErrConflict when another link now points to the same destination.
+func (s *LinkService) RestoreLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID) (*domain.Link, error) {
+	link, err := s.trashedLink(ctx, linkID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.activeLinks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch existing links: %w", err)
+	}
+	if err := checkDuplicateURL(active, link.URL, link.ID); err != nil {
+		return nil, err
+	}
+
+	before := snapshotLink(link)
+	link.DeletedAt = nil
+	link.Order = nextOrder(active)
+	link.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to restore link: %w", err)
+	}
+	s.recordRevision(ctx, userID, domain.RevisionRestore, before, link)
+
+	return link, nil
+}
+
+// PurgeLink permanently deletes a link from the trash.
+func (s *LinkService) PurgeLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID) error {
+	if _, err := s.trashedLink(ctx, linkID, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, linkID)
+}
+
+// PurgeTrash permanently deletes the user's links that were trashed before cutoff.
+// It returns how many links were purged.
+func (s *LinkService) PurgeTrash(ctx context.Context, userID domain.UserID, cutoff time.Time) (int, error) {
+	trashed, err := s.ListTrash(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	purged := 0
+	for _, link := range trashed {
+		if !link.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if err := s.repo.Delete(ctx, link.ID); err != nil {
+			return purged, fmt.Errorf("failed to purge link %s: %w", link.ID, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// trashedLink loads a link and verifies it belongs to the user and is in the trash.
+func (s *LinkService) trashedLink(ctx context.Context, linkID domain.LinkID, userID domain.UserID) (*domain.Link, error) {
+	link, err := s.repo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	if link.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: link does not belong to user: %w", domain.ErrForbidden)
+	}
+	if !link.IsTrashed() {
+		return nil, fmt.Errorf("link is not in the trash: %w", domain.ErrBadRequest)
+	}
+	return link, nil
+}
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elchemista/driplnk/internal/domain"
+	"github.com/elchemista/driplnk/internal/mocks"
+	"github.com/elchemista/driplnk/internal/service"
+)
+
+func TestLinkService_Trash(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, Title: "Tour", URL: "https://tour.example.com", Slug: "tour", Order: 0})
+	repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, Title: "Merch", URL: "https://merch.example.com", Order: 2})
+
+	if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+
+	t.Run("HiddenWhileTrashed", func(t *testing.T) {
+		links, _ := svc.ListAllLinks(ctx, userID)
+		if len(links) != 1 || links[0].ID != "link-2" {
+			t.Errorf("expected only link-2 in the dashboard list, got %+v", links)
+		}
+		if public, _ := svc.ListLinks(ctx, userID); len(public) != 1 {
+			t.Errorf("expected only link-2 on the profile, got %+v", public)
+		}
+		if _, err := svc.ResolveLink(ctx, "tour"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("expected trashed slug not to resolve, got %v", err)
+		}
+		trash, _ := svc.ListTrash(ctx, userID)
+		if len(trash) != 1 || trash[0].ID != "link-1" {
+			t.Errorf("expected link-1 in the trash, got %+v", trash)
+		}
+	})
+
+	t.Run("RestoreAndPurgeRequireTrash", func(t *testing.T) {
+		if _, err := svc.RestoreLink(ctx, "link-2", userID); !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request restoring a live link, got %v", err)
+		}
+		if err := svc.PurgeLink(ctx, "link-2", userID); !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("expected bad request purging a live link, got %v", err)
+		}
+		if _, err := svc.RestoreLink(ctx, "link-1", "user-other"); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected forbidden for another user, got %v", err)
+		}
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		link, err := svc.RestoreLink(ctx, "link-1", userID)
+		if err != nil {
+			t.Fatalf("RestoreLink failed: %v", err)
+		}
+		if link.IsTrashed() || link.Order != 3 || link.Slug != "tour" {
+			t.Errorf("expected link back after the highest order with its slug, got %+v", link)
+		}
+		if _, err := svc.ResolveLink(ctx, "tour"); err != nil {
+			t.Errorf("expected restored slug to resolve, got %v", err)
+		}
+	})
+
+	t.Run("RestoreDuplicateURL", func(t *testing.T) {
+		repo.AddLink(&domain.Link{ID: "link-3", UserID: userID, URL: "https://shop.example.com", Order: 4})
+		if err := svc.DeleteLink(ctx, "link-3", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+		if _, err := svc.CreateLink(ctx, userID, "Shop", "https://Shop.example.com/", domain.LinkTypeStandard); err != nil {
+			t.Fatalf("CreateLink failed: %v", err)
+		}
+		if _, err := svc.RestoreLink(ctx, "link-3", userID); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("expected conflict restoring a link whose URL was re-created, got %v", err)
+		}
+		if err := svc.PurgeLink(ctx, "link-3", userID); err != nil {
+			t.Fatalf("PurgeLink failed: %v", err)
+		}
+	})
+
+	t.Run("PurgeTrash", func(t *testing.T) {
+		old := time.Now().Add(-48 * time.Hour)
+		repo.AddLink(&domain.Link{ID: "link-old", UserID: userID, URL: "https://old.example.com", DeletedAt: &old})
+		if err := svc.DeleteLink(ctx, "link-2", userID); err != nil {
+			t.Fatalf("DeleteLink failed: %v", err)
+		}
+
+		purged, err := svc.PurgeTrash(ctx, userID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("PurgeTrash failed: %v", err)
+		}
+		if purged != 1 {
+			t.Errorf("expected 1 purged link, got %d", purged)
+		}
+		if _, err := repo.GetByID(ctx, "link-old"); err == nil {
+			t.Error("expected the expired link to be gone")
+		}
+		if trash, _ := svc.ListTrash(ctx, userID); len(trash) != 1 || trash[0].ID != "link-2" {
+			t.Errorf("expected the recently deleted link to stay in the trash, got %+v", trash)
+		}
+	})
+}
+
+func TestLinkService_CreateLinkAfterTrash(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLinkRepository()
+	svc := service.NewLinkService(repo, nil, nil, nil, nil, nil)
+	userID := domain.UserID("user-123")
+
+	repo.AddLink(&domain.Link{ID: "link-1", UserID: userID, URL: "https://tour.example.com", Order: 0})
+	repo.AddLink(&domain.Link{ID: "link-2", UserID: userID, URL: "https://merch.example.com", Order: 1})
+	if err := svc.DeleteLink(ctx, "link-1", userID); err != nil {
+		t.Fatalf("DeleteLink failed: %v", err)
+	}
+
+	// The trashed link leaves a gap, so counting the active links would reuse order 1
+	link, err := svc.CreateLink(ctx, userID, "Shop", "https://shop.example.com", domain.LinkTypeStandard)
+	if err != nil {
+		t.Fatalf("CreateLink failed: %v", err)
+	}
+	if link.Order != 2 {
+		t.Errorf("expected the new link after the highest order, got %d", link.Order)
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE links DROP COLUMN IF EXISTS fallback_url;
+ALTER TABLE links DROP COLUMN IF EXISTS ends_at;
+ALTER TABLE links DROP COLUMN IF EXISTS starts_at;
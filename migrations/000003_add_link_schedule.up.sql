@@ -0,0 +1,4 @@
+-- Optional publish window for links
+ALTER TABLE links ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS ends_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS fallback_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_links_slug;
+ALTER TABLE links DROP COLUMN IF EXISTS slug;
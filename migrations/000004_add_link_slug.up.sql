@@ -0,0 +1,5 @@
+-- Optional vanity slug used by /go/{slug} and /{handle}/{slug}
+ALTER TABLE links ADD COLUMN IF NOT EXISTS slug TEXT NOT NULL DEFAULT '';
+
+-- Slugs are globally unique; empty means "no slug"
+CREATE UNIQUE INDEX IF NOT EXISTS idx_links_slug ON links(slug) WHERE slug <> '';
@@ -0,0 +1,2 @@
+-- Weighted A/B destinations for links
+ALTER TABLE links ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,2 @@
+-- Geo/device targeted redirect rules for links
+ALTER TABLE links ADD COLUMN IF NOT EXISTS rules JSONB NOT NULL DEFAULT '[]'::jsonb;
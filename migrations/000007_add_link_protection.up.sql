@@ -0,0 +1,3 @@
+-- Password / sensitive content gate for links
+ALTER TABLE links ADD COLUMN IF NOT EXISTS protection TEXT NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
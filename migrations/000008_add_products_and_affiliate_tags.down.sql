@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS affiliate_tags;
+ALTER TABLE links DROP COLUMN IF EXISTS product;
@@ -0,0 +1,3 @@
+-- Structured product fields for links and per-user affiliate tags
+ALTER TABLE links ADD COLUMN IF NOT EXISTS product JSONB;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS affiliate_tags JSONB NOT NULL DEFAULT '{}'::jsonb;
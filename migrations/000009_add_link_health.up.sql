@@ -0,0 +1,2 @@
+-- Last destination check recorded by the link health checker
+ALTER TABLE links ADD COLUMN IF NOT EXISTS health JSONB;
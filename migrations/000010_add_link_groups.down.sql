@@ -0,0 +1,2 @@
+ALTER TABLE links DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS link_groups;
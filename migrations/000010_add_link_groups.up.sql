@@ -0,0 +1,16 @@
+-- Titled profile sections that links can be grouped under
+CREATE TABLE IF NOT EXISTS link_groups (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    collapsed BOOLEAN NOT NULL DEFAULT false,
+    group_order INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_groups_user_order ON link_groups(user_id, group_order);
+
+-- Deleting a group moves its links back to the ungrouped list
+ALTER TABLE links ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES link_groups(id) ON DELETE SET NULL;
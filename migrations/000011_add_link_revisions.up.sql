@@ -0,0 +1,15 @@
+-- Append-only edit history of links. Rows outlive the link (no foreign key on link_id)
+-- so deleted links can be restored.
+CREATE TABLE IF NOT EXISTS link_revisions (
+    id UUID PRIMARY KEY,
+    link_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id),
+    actor_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    before_snapshot JSONB,
+    after_snapshot JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_revisions_link ON link_revisions(link_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_link_revisions_user ON link_revisions(user_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- Deleted links wait in the trash until they are restored or purged
+ALTER TABLE links ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_links_deleted_at ON links(deleted_at) WHERE deleted_at IS NOT NULL;
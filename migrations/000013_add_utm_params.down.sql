@@ -0,0 +1,2 @@
+ALTER TABLE links DROP COLUMN IF EXISTS utm;
+ALTER TABLE users DROP COLUMN IF EXISTS utm;
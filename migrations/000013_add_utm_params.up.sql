@@ -0,0 +1,3 @@
+-- Default UTM parameters per profile and per-link overrides, appended at redirect time
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm JSONB;
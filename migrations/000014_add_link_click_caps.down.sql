@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS link_visitors;
+ALTER TABLE links DROP COLUMN IF EXISTS unique_visitors;
+ALTER TABLE links DROP COLUMN IF EXISTS max_visitors;
+ALTER TABLE links DROP COLUMN IF EXISTS max_clicks;
+ALTER TABLE links ALTER COLUMN click_count DROP NOT NULL;
@@ -0,0 +1,14 @@
+-- Click and unique visitor limits for limited-use links
+UPDATE links SET click_count = 0 WHERE click_count IS NULL;
+ALTER TABLE links ALTER COLUMN click_count SET NOT NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS max_clicks BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS max_visitors BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS unique_visitors BIGINT NOT NULL DEFAULT 0;
+
+-- Visitors counted towards unique_visitors, so returning visitors are not counted twice
+CREATE TABLE IF NOT EXISTS link_visitors (
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    visitor_id TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (link_id, visitor_id)
+);
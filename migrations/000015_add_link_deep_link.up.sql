@@ -0,0 +1,2 @@
+-- Links that try to open the destination's native app on phones
+ALTER TABLE links ADD COLUMN IF NOT EXISTS deep_link BOOLEAN NOT NULL DEFAULT false;
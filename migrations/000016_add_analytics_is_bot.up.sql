@@ -0,0 +1,2 @@
+-- Flag events of crawlers, link preview fetchers and monitors; reported apart from real traffic
+ALTER TABLE analytics_events ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT false;
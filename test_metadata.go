@@ -11,7 +11,7 @@ import (
 func main() {
 	fmt.Println("Testing HTMLFetcher...")
 
-	fetcher := seo.NewHTMLFetcher()
+	fetcher := seo.NewHTMLFetcher(nil)
 	ctx := context.Background()
 
 	url := "https://elchemista.com/"
@@ -64,9 +64,9 @@ func Base(title string, theme string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var3 string
-		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(getCSRF(ctx))
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(CSRFToken(ctx))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/layout/base.templ`, Line: 18, Col: 49}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/layout/base.templ`, Line: 18, Col: 51}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -101,7 +101,8 @@ func Base(title string, theme string) templ.Component {
 	})
 }
 
-func getCSRF(ctx context.Context) string {
+// CSRFToken returns the request's CSRF token, for forms that are submitted without Turbo.
+func CSRFToken(ctx context.Context) string {
 	if v := ctx.Value("csrf_token"); v != nil {
 		return v.(string)
 	}